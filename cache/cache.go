@@ -2,12 +2,16 @@ package cache
 
 import (
 	"battleship/dto"
+	"errors"
 	"fmt"
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
 	"sync"
+	"time"
 )
 
+// GameCache keeps the sockets of the games on this server. It is read and written only under Mux, use the functions
+// of this file to access it.
 var GameCache = struct {
 	Mux   sync.Locker
 	Cache map[string]GameData
@@ -32,3 +36,101 @@ func (r *GameData) getUser(userId string) (string, error) {
 	log.Error().Str("userId", userId).Msg("cannot find userId in GameData")
 	return "", dto.NotFoundError1(fmt.Sprintf("cannot find userId %s in GameData", userId))
 }
+
+// GetGameData returns a copy of the sockets of the game, so they can be written without holding the lock.
+func GetGameData(gameId string) (GameData, bool) {
+	GameCache.Mux.Lock()
+	defer GameCache.Mux.Unlock()
+	g, ok := GameCache.Cache[gameId]
+	return g, ok
+}
+
+// SetGameSocket registers conn as the socket of side 1 or 2 of the game, a previous socket of the side is replaced.
+func SetGameSocket(gameId string, side int, userId string, conn *websocket.Conn) {
+	GameCache.Mux.Lock()
+	defer GameCache.Mux.Unlock()
+	g := GameCache.Cache[gameId]
+	if side == 1 {
+		g.Side1UserId = userId
+		g.Side1Socket = conn
+	} else {
+		g.Side2UserId = userId
+		g.Side2Socket = conn
+	}
+	GameCache.Cache[gameId] = g
+}
+
+// RemoveGameSocket removes conn from the game if it is still the socket of the user, the game is removed when it has
+// no socket left. It returns false when the user has connected with another socket since.
+func RemoveGameSocket(gameId string, userId string, conn *websocket.Conn) bool {
+	GameCache.Mux.Lock()
+	defer GameCache.Mux.Unlock()
+	g, ok := GameCache.Cache[gameId]
+	if !ok {
+		return false
+	}
+	switch {
+	case g.Side1UserId == userId && g.Side1Socket == conn:
+		g.Side1Socket = nil
+	case g.Side2UserId == userId && g.Side2Socket == conn:
+		g.Side2Socket = nil
+	default:
+		return false
+	}
+	if g.Side1Socket == nil && g.Side2Socket == nil {
+		delete(GameCache.Cache, gameId)
+	} else {
+		GameCache.Cache[gameId] = g
+	}
+	return true
+}
+
+// GameCount returns the number of games in the registry.
+func GameCount() int {
+	GameCache.Mux.Lock()
+	defer GameCache.Mux.Unlock()
+	return len(GameCache.Cache)
+}
+
+// GameRegistryStats counts the games and the connected sockets of the registry. It fails when the registry lock is
+// not taken in timeout, e.g. it is held by a stuck goroutine.
+func GameRegistryStats(timeout time.Duration) (games int, sockets int, err error) {
+	locked := make(chan struct{})
+	abandoned := make(chan struct{})
+	go func() {
+		GameCache.Mux.Lock()
+		select {
+		case locked <- struct{}{}:
+		case <-abandoned:
+			GameCache.Mux.Unlock()
+		}
+	}()
+	select {
+	case <-locked:
+	case <-time.After(timeout):
+		close(abandoned)
+		return 0, 0, errors.New("connection registry is locked")
+	}
+	defer GameCache.Mux.Unlock()
+	for _, g := range GameCache.Cache {
+		games++
+		if g.Side1Socket != nil {
+			sockets++
+		}
+		if g.Side2Socket != nil {
+			sockets++
+		}
+	}
+	return games, sockets, nil
+}
+
+// GameConnection returns whether the sides of a game have a socket on this server.
+func GameConnection(gameId string) (side1 bool, side2 bool) {
+	GameCache.Mux.Lock()
+	defer GameCache.Mux.Unlock()
+	g, ok := GameCache.Cache[gameId]
+	if !ok {
+		return false, false
+	}
+	return g.Side1Socket != nil, g.Side2Socket != nil
+}
@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// ChatRateCache keeps the time of recent chat messages of each user.
+var ChatRateCache = struct {
+	Mux   sync.Locker
+	Cache map[string][]time.Time
+}{
+	Cache: make(map[string][]time.Time),
+	Mux:   new(sync.Mutex),
+}
+
+// AllowChat records a message of the user at now if the user has sent less than limit messages in the window.
+func AllowChat(userId string, now time.Time, limit int, window time.Duration) bool {
+	ChatRateCache.Mux.Lock()
+	defer ChatRateCache.Mux.Unlock()
+	recent := ChatRateCache.Cache[userId][:0]
+	for _, t := range ChatRateCache.Cache[userId] {
+		if t.Add(window).After(now) {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= limit {
+		ChatRateCache.Cache[userId] = recent
+		return false
+	}
+	ChatRateCache.Cache[userId] = append(recent, now)
+	return true
+}
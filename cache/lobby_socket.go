@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"github.com/gorilla/websocket"
+	"sync"
+)
+
+// LobbySocketCache keeps sockets that are subscribed to lobby updates.
+var LobbySocketCache = struct {
+	Mux   sync.Locker
+	Cache map[*websocket.Conn]bool
+}{
+	Cache: make(map[*websocket.Conn]bool),
+	Mux:   new(sync.Mutex),
+}
+
+func AddLobbySocket(conn *websocket.Conn) {
+	LobbySocketCache.Mux.Lock()
+	defer LobbySocketCache.Mux.Unlock()
+	LobbySocketCache.Cache[conn] = true
+}
+
+func RemoveLobbySocket(conn *websocket.Conn) {
+	LobbySocketCache.Mux.Lock()
+	defer LobbySocketCache.Mux.Unlock()
+	delete(LobbySocketCache.Cache, conn)
+}
+
+// GetLobbySockets returns a copy of subscribed sockets, so they can be written without holding the lock.
+func GetLobbySockets() []*websocket.Conn {
+	LobbySocketCache.Mux.Lock()
+	defer LobbySocketCache.Mux.Unlock()
+	conns := make([]*websocket.Conn, 0, len(LobbySocketCache.Cache))
+	for conn := range LobbySocketCache.Cache {
+		conns = append(conns, conn)
+	}
+	return conns
+}
@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"battleship/model"
+	"sync"
+	"time"
+)
+
+// MatchmakingTicket is a user waiting in the queue. BlockedWith has the users that the user has blocked or that have
+// blocked the user, it is never paired with them.
+type MatchmakingTicket struct {
+	UserId      string
+	Settings    model.GameSettings
+	Rating      int
+	EnqueueTime time.Time
+	BlockedWith map[string]bool
+	// TossCommitment is the coin toss commitment of the user for the matched game
+	TossCommitment string
+}
+
+var MatchmakingQueue = struct {
+	Mux     sync.Locker
+	Tickets []MatchmakingTicket
+}{
+	Tickets: []MatchmakingTicket{},
+	Mux:     new(sync.Mutex),
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Presence is the websocket presence of a user in a game.
+type Presence struct {
+	Connected         bool
+	DisconnectedSince time.Time
+}
+
+// PresenceCache keeps presence of game sides, keys are gameId:userId.
+var PresenceCache = struct {
+	Mux   sync.Locker
+	Cache map[string]Presence
+}{
+	Cache: make(map[string]Presence),
+	Mux:   new(sync.Mutex),
+}
+
+func presenceKey(gameId string, userId string) string {
+	return gameId + ":" + userId
+}
+
+func MarkConnected(gameId string, userId string) {
+	PresenceCache.Mux.Lock()
+	defer PresenceCache.Mux.Unlock()
+	PresenceCache.Cache[presenceKey(gameId, userId)] = Presence{Connected: true}
+}
+
+// MarkDisconnected updates the presence of a connected user, a presence that is removed as the game has ended is not
+// created again.
+func MarkDisconnected(gameId string, userId string, at time.Time) {
+	PresenceCache.Mux.Lock()
+	defer PresenceCache.Mux.Unlock()
+	key := presenceKey(gameId, userId)
+	if _, ok := PresenceCache.Cache[key]; ok {
+		PresenceCache.Cache[key] = Presence{DisconnectedSince: at}
+	}
+}
+
+// GetPresence returns false if the user has never connected to the game.
+func GetPresence(gameId string, userId string) (Presence, bool) {
+	PresenceCache.Mux.Lock()
+	defer PresenceCache.Mux.Unlock()
+	presence, ok := PresenceCache.Cache[presenceKey(gameId, userId)]
+	return presence, ok
+}
+
+// ResetDisconnected starts the disconnected time of the sides that are not connected again at at, e.g. when a paused
+// game is resumed the pause does not count. A side that has never connected is counted from at too.
+func ResetDisconnected(gameId string, at time.Time, userIds ...string) {
+	PresenceCache.Mux.Lock()
+	defer PresenceCache.Mux.Unlock()
+	for _, userId := range userIds {
+		key := presenceKey(gameId, userId)
+		if presence, ok := PresenceCache.Cache[key]; !ok || !presence.Connected {
+			PresenceCache.Cache[key] = Presence{DisconnectedSince: at}
+		}
+	}
+}
+
+func RemovePresence(gameId string, userIds ...string) {
+	PresenceCache.Mux.Lock()
+	defer PresenceCache.Mux.Unlock()
+	for _, userId := range userIds {
+		delete(PresenceCache.Cache, presenceKey(gameId, userId))
+	}
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"battleship/model"
+	"testing"
+	"time"
+)
+
+func TestResetDisconnectedExcludesPause(t *testing.T) {
+	policy := model.AbandonmentPolicy{MaxDisconnectedSec: 60}
+	pausedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	resumedAt := pausedAt.Add(5 * time.Minute)
+
+	tests := []struct {
+		name       string
+		connect    bool
+		disconnect bool
+		check      time.Duration
+		want       bool
+	}{
+		{"disconnected during pause, just after resume", true, true, time.Second, false},
+		{"disconnected during pause, limit after resume", true, true, time.Minute, true},
+		{"never connected, just after resume", false, false, time.Second, false},
+		{"connected during pause", true, false, time.Hour, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gameId, userId := "game", tt.name
+			defer RemovePresence(gameId, userId)
+			if tt.connect {
+				MarkConnected(gameId, userId)
+			}
+			if tt.disconnect {
+				MarkDisconnected(gameId, userId, pausedAt.Add(time.Second))
+			}
+			ResetDisconnected(gameId, resumedAt, userId)
+
+			presence, _ := GetPresence(gameId, userId)
+			side := model.SidePresence{Connected: presence.Connected, DisconnectedSince: presence.DisconnectedSince}
+			if got := policy.IsAbandoned(side, resumedAt.Add(tt.check)); got != tt.want {
+				t.Errorf("IsAbandoned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"sync/atomic"
+)
+
+// serverState keeps the modes of this server instance, they are read by readiness checks.
+var serverState struct {
+	draining           int32
+	maintenance        int32
+	maintenanceMessage atomic.Value
+}
+
+// SetDraining marks the server as shutting down, it does not take new traffic.
+func SetDraining(draining bool) {
+	atomic.StoreInt32(&serverState.draining, boolToInt32(draining))
+}
+
+func IsDraining() bool {
+	return atomic.LoadInt32(&serverState.draining) == 1
+}
+
+// SetMaintenance marks the server as in maintenance, new games are rejected with message.
+func SetMaintenance(maintenance bool, message string) {
+	serverState.maintenanceMessage.Store(message)
+	atomic.StoreInt32(&serverState.maintenance, boolToInt32(maintenance))
+}
+
+func IsMaintenance() bool {
+	return atomic.LoadInt32(&serverState.maintenance) == 1
+}
+
+func MaintenanceMessage() string {
+	message, _ := serverState.maintenanceMessage.Load().(string)
+	return message
+}
+
+func boolToInt32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"github.com/gorilla/websocket"
+	"sync"
+)
+
+// SocketWriterCache keeps a write lock per socket. gorilla/websocket allows one writer at a time and a socket is
+// written by its game, by broadcasts and by http handlers, so every write goes through WriteMessage.
+var SocketWriterCache = struct {
+	Mux   sync.Locker
+	Cache map[*websocket.Conn]sync.Locker
+}{
+	Cache: make(map[*websocket.Conn]sync.Locker),
+	Mux:   new(sync.Mutex),
+}
+
+// WriteMessage writes a text message to conn under its write lock. Nothing is written to a nil conn, it is a side
+// that is not connected.
+func WriteMessage(conn *websocket.Conn, data []byte) error {
+	if conn == nil {
+		return nil
+	}
+	lock := writeLock(conn)
+	lock.Lock()
+	defer lock.Unlock()
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// ForgetSocket drops the write lock of a socket that is closed.
+func ForgetSocket(conn *websocket.Conn) {
+	SocketWriterCache.Mux.Lock()
+	defer SocketWriterCache.Mux.Unlock()
+	delete(SocketWriterCache.Cache, conn)
+}
+
+func writeLock(conn *websocket.Conn) sync.Locker {
+	SocketWriterCache.Mux.Lock()
+	defer SocketWriterCache.Mux.Unlock()
+	lock, ok := SocketWriterCache.Cache[conn]
+	if !ok {
+		lock = new(sync.Mutex)
+		SocketWriterCache.Cache[conn] = lock
+	}
+	return lock
+}
@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"github.com/gorilla/websocket"
+)
+
+// AllSockets returns the game, user and lobby sockets.
+func AllSockets() []*websocket.Conn {
+	var conns []*websocket.Conn
+	GameCache.Mux.Lock()
+	for _, g := range GameCache.Cache {
+		for _, conn := range []*websocket.Conn{g.Side1Socket, g.Side2Socket} {
+			if conn != nil {
+				conns = append(conns, conn)
+			}
+		}
+	}
+	GameCache.Mux.Unlock()
+
+	UserSocketCache.Mux.Lock()
+	for _, conn := range UserSocketCache.Cache {
+		conns = append(conns, conn)
+	}
+	UserSocketCache.Mux.Unlock()
+
+	return append(conns, GetLobbySockets()...)
+}
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"github.com/gorilla/websocket"
+	"sync"
+)
+
+// UserSocketCache keeps sockets that are bound to a user instead of a game, e.g. while waiting in matchmaking.
+var UserSocketCache = struct {
+	Mux   sync.Locker
+	Cache map[string]*websocket.Conn
+}{
+	Cache: make(map[string]*websocket.Conn),
+	Mux:   new(sync.Mutex),
+}
+
+func GetUserSocket(userId string) (*websocket.Conn, bool) {
+	UserSocketCache.Mux.Lock()
+	defer UserSocketCache.Mux.Unlock()
+	conn, ok := UserSocketCache.Cache[userId]
+	return conn, ok
+}
+
+func SetUserSocket(userId string, conn *websocket.Conn) {
+	UserSocketCache.Mux.Lock()
+	defer UserSocketCache.Mux.Unlock()
+	UserSocketCache.Cache[userId] = conn
+}
+
+func RemoveUserSocket(userId string, conn *websocket.Conn) {
+	UserSocketCache.Mux.Lock()
+	defer UserSocketCache.Mux.Unlock()
+	if UserSocketCache.Cache[userId] == conn {
+		delete(UserSocketCache.Cache, userId)
+	}
+}
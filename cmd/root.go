@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"battleship/config"
+	"battleship/utils"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"strings"
 )
 
 var rootCMD = &cobra.Command{
@@ -12,6 +14,9 @@ var rootCMD = &cobra.Command{
 }
 var configFilePath string
 
+// defaultSecretPrefix starts the secrets of the shipped config, they are public and only good for development.
+const defaultSecretPrefix = "change-me"
+
 func init() {
 	cobra.OnInitialize(Configure)
 	rootCMD.AddCommand(startCMD)
@@ -20,6 +25,31 @@ func init() {
 
 func Configure() {
 	config.Init(configFilePath)
+	configureIdCodec()
+	configureOtp()
+}
+
+func configureIdCodec() {
+	secrets := make(map[int]string, len(config.C.Ids.Keys))
+	for _, key := range config.C.Ids.Keys {
+		if config.C.Mode != "dev" && strings.HasPrefix(key.Secret, defaultSecretPrefix) {
+			log.Fatal().Int("version", key.Version).Msg("id key is the default one, set ids.keys")
+		}
+		secrets[key.Version] = key.Secret
+	}
+	if err := utils.InitIdCodec(config.C.Ids.CurrentKey, secrets); err != nil {
+		log.Fatal().Err(err).Msg("cannot initialize id codec")
+	}
+}
+
+func configureOtp() {
+	key := config.C.Otp.HashKey
+	if len(key) < 16 {
+		log.Fatal().Msg("otp hash key is shorter than 16 characters")
+	}
+	if config.C.Mode != "dev" && strings.HasPrefix(key, defaultSecretPrefix) {
+		log.Fatal().Msg("otp hash key is the default one, set otp.hash_key")
+	}
 }
 
 func Execute() {
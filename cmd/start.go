@@ -1,12 +1,25 @@
 package cmd
 
 import (
+	"battleship/cache"
+	"battleship/config"
 	"battleship/db/mongodb"
+	"battleship/di"
+	"battleship/dto"
 	"battleship/http"
+	"battleship/scheduler"
+	"battleship/service"
+	"battleship/socket"
+	"battleship/tracing"
 	"context"
+	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 var startCMD = &cobra.Command{
@@ -14,11 +27,54 @@ var startCMD = &cobra.Command{
 	Short: "start server",
 	Run: func(cmd *cobra.Command, args []string) {
 		client := connectToMongo()
-		defer client.Close()
-		http.StartHttpServer()
+		shutdownTracing := tracing.Init()
+		startJobs()
+		e := http.StartHttpServer()
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		sig := <-signals
+		log.Info().Str("signal", sig.String()).Msg("shutting down")
+		shutdown(e, client, shutdownTracing)
 	},
 }
 
+// shutdown stops taking new games, tells players to reconnect later, lets in-flight requests finish and then
+// closes sockets, jobs, webhook deliveries, tracing and the mongo client in that order.
+func shutdown(e *echo.Echo, client *mongodb.Client, shutdownTracing func(ctx context.Context) error) {
+	cfg := config.C.Shutdown
+	cache.SetDraining(true)
+	err := di.CreateOutgoingEventHandler().ServerRestarting(context.Background(), dto.ServerRestartingEvent{
+		RetryAfterSec: cfg.RetryAfterSec,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("cannot send server restarting event")
+	}
+	time.Sleep(time.Duration(cfg.DrainDelaySec) * time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.TimeoutSec)*time.Second)
+	defer cancel()
+	err = e.Shutdown(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("http server did not shut down cleanly")
+	}
+	socket.CloseAll("server restarting")
+	err = scheduler.Stop(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("jobs did not stop cleanly")
+	}
+	err = service.WaitWebhookDeliveries(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("webhook deliveries did not end cleanly")
+	}
+	err = shutdownTracing(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot shut down tracing")
+	}
+	client.Close(ctx)
+	log.Info().Msg("server stopped")
+}
+
 func connectToMongo() *mongodb.Client {
 	//mongodb
 	client, err := mongodb.CreateMongoClient()
@@ -29,5 +85,38 @@ func connectToMongo() *mongodb.Client {
 	if err != nil {
 		log.Fatal().Err(err).Msg("")
 	}
+	err = mongodb.EnsureIndexes()
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
 	return client
 }
+
+func startJobs() {
+	adminService := di.CreateAdminService()
+	adminService.SyncServerSetting(context.Background())
+	scheduler.Every("server_setting_sync", time.Duration(config.C.Admin.SettingSyncIntervalSec)*time.Second,
+		adminService.SyncServerSetting)
+
+	matchmakingService := di.CreateMatchmakingService()
+	scheduler.Every("matchmaking_expiry", time.Duration(config.C.Matchmaking.SweepIntervalSec)*time.Second,
+		matchmakingService.ExpireTickets)
+
+	gameService := di.CreateGameService()
+	scheduler.Every("game_expiry", time.Duration(config.C.Game.ExpireSweepIntervalSec)*time.Second,
+		gameService.ExpireGames)
+	scheduler.Every("pause_expiry", time.Duration(config.C.Pause.SweepIntervalSec)*time.Second,
+		gameService.ResumeExpiredPauses)
+
+	webhookService := di.CreateWebhookService()
+	scheduler.Every("webhook_retry", time.Duration(config.C.Webhook.RetryIntervalSec)*time.Second,
+		webhookService.RetryDue)
+
+	leaderboardService := di.CreateLeaderboardService()
+	scheduler.Every("season_archive", time.Duration(config.C.Leaderboard.ArchiveIntervalSec)*time.Second,
+		leaderboardService.ArchiveEndedSeasons)
+
+	antiCheatService := di.CreateAntiCheatService()
+	scheduler.Every("anti_cheat", time.Duration(config.C.AntiCheat.IntervalSec)*time.Second,
+		antiCheatService.AnalyseFinishedGames)
+}
@@ -16,11 +16,31 @@ var (
 )
 
 type Config struct {
-	Mode     string  `yaml:"mode"`
-	HttpPort string  `yaml:"http_port"`
-	Logging  Logging `yaml:"logging"`
-	MongoDB  Mongodb `yaml:"mongodb"`
-	Cors     Cors    `yaml:"cors"`
+	Mode        string      `yaml:"mode"`
+	HttpPort    string      `yaml:"http_port"`
+	Logging     Logging     `yaml:"logging"`
+	MongoDB     Mongodb     `yaml:"mongodb"`
+	Cors        Cors        `yaml:"cors"`
+	Otp         Otp         `yaml:"otp"`
+	Sms         Sms         `yaml:"sms"`
+	Session     Session     `yaml:"session"`
+	Ids         Ids         `yaml:"ids"`
+	Matchmaking Matchmaking `yaml:"matchmaking"`
+	Rating      Rating      `yaml:"rating"`
+	Leaderboard Leaderboard `yaml:"leaderboard"`
+	Lobby       Lobby       `yaml:"lobby"`
+	Invite      Invite      `yaml:"invite"`
+	Game        Game        `yaml:"game"`
+	Abandonment Abandonment `yaml:"abandonment"`
+	Pause       Pause       `yaml:"pause"`
+	Chat        Chat        `yaml:"chat"`
+	Report      Report      `yaml:"report"`
+	AntiCheat   AntiCheat   `yaml:"anti_cheat"`
+	Webhook     Webhook     `yaml:"webhook"`
+	Admin       Admin       `yaml:"admin"`
+	Tracing     Tracing     `yaml:"tracing"`
+	Health      Health      `yaml:"health"`
+	Shutdown    Shutdown    `yaml:"shutdown"`
 }
 
 type Logging struct {
@@ -39,6 +59,213 @@ type Cors struct {
 	Domain string `yaml:"domain"`
 }
 
+type Otp struct {
+	Length            int    `yaml:"length"`
+	ExpireSec         int    `yaml:"expire_sec"`
+	MaxAttempts       int    `yaml:"max_attempts"`
+	ResendIntervalSec int    `yaml:"resend_interval_sec"`
+	MaxPerHour        int    `yaml:"max_per_hour"`
+	HashKey           string `yaml:"hash_key"`
+}
+
+type Sms struct {
+	Sender   string `yaml:"sender"` //console or file
+	FilePath string `yaml:"file_path"`
+}
+
+type Session struct {
+	ExpireHours int `yaml:"expire_hours"`
+}
+
+type Ids struct {
+	CurrentKey int     `yaml:"current_key"`
+	Keys       []IdKey `yaml:"keys"`
+}
+
+type Matchmaking struct {
+	TimeoutSec               int `yaml:"timeout_sec"`
+	SweepIntervalSec         int `yaml:"sweep_interval_sec"`
+	RatingWindow             int `yaml:"rating_window"`
+	RatingWindowGrowthPerSec int `yaml:"rating_window_growth_per_sec"`
+}
+
+type Rating struct {
+	Initial            int     `yaml:"initial"`
+	KFactor            int     `yaml:"k_factor"`
+	ProvisionalKFactor int     `yaml:"provisional_k_factor"`
+	ProvisionalGames   int     `yaml:"provisional_games"`
+	TimeoutFactor      float64 `yaml:"timeout_factor"` //rating change multiplier for games ended by timeout
+	AbandonFactor      float64 `yaml:"abandon_factor"` //rating change multiplier for games ended by abandonment
+}
+
+type Lobby struct {
+	PageSize    int `yaml:"page_size"`
+	MaxPageSize int `yaml:"max_page_size"`
+}
+
+type Game struct {
+	InitExpireSec          int `yaml:"init_expire_sec"`
+	JoinedExpireSec        int `yaml:"joined_expire_sec"`
+	ExpireSweepIntervalSec int `yaml:"expire_sweep_interval_sec"`
+}
+
+type Abandonment struct {
+	MaxMissedTurns     int `yaml:"max_missed_turns"`
+	MaxDisconnectedSec int `yaml:"max_disconnected_sec"`
+}
+
+type Pause struct {
+	MaxSec           int `yaml:"max_sec"`
+	SweepIntervalSec int `yaml:"sweep_interval_sec"`
+}
+
+type Webhook struct {
+	Endpoints         []WebhookEndpoint `yaml:"endpoints"`
+	TimeoutSec        int               `yaml:"timeout_sec"`
+	MaxAttempts       int               `yaml:"max_attempts"`
+	InitialBackoffSec int               `yaml:"initial_backoff_sec"`
+	MaxBackoffSec     int               `yaml:"max_backoff_sec"`
+	RetryIntervalSec  int               `yaml:"retry_interval_sec"`
+}
+
+// WebhookEndpoint receives the listed events, or all events when Events is empty. Deliveries are signed with Secret.
+type WebhookEndpoint struct {
+	Name   string   `yaml:"name"`
+	Url    string   `yaml:"url"`
+	Secret string   `yaml:"secret"`
+	Events []string `yaml:"events"`
+}
+
+// Shutdown waits DrainDelaySec after readiness fails so load balancers stop sending traffic, then waits up to
+// TimeoutSec for in-flight requests.
+type Shutdown struct {
+	DrainDelaySec int `yaml:"drain_delay_sec"`
+	TimeoutSec    int `yaml:"timeout_sec"`
+	RetryAfterSec int `yaml:"retry_after_sec"`
+}
+
+type Health struct {
+	DbTimeoutMs       int `yaml:"db_timeout_ms"`
+	RegistryTimeoutMs int `yaml:"registry_timeout_ms"`
+	JobMissedBeats    int `yaml:"job_missed_beats"`
+}
+
+type Tracing struct {
+	Exporter    string  `yaml:"exporter"` //none, stdout or file
+	FilePath    string  `yaml:"file_path"`
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// Admin has the token of the admin endpoints. Server settings changed by admins are reloaded from the db every
+// SettingSyncIntervalSec, so all instances follow them.
+type Admin struct {
+	Token                  string `yaml:"token"`
+	SettingSyncIntervalSec int    `yaml:"setting_sync_interval_sec"`
+	NoticeMaxLength        int    `yaml:"notice_max_length"`
+}
+
+// Report restricts a user for AutoRestrictHours when AutoRestrictCount of its reports are confirmed in
+// AutoRestrictWindowDays. ChatLogSize last messages of the game are attached to a report.
+type Report struct {
+	Categories             []string `yaml:"categories"`
+	MaxCommentLength       int      `yaml:"max_comment_length"`
+	ChatLogSize            int      `yaml:"chat_log_size"`
+	AutoRestrictCount      int      `yaml:"auto_restrict_count"`
+	AutoRestrictWindowDays int      `yaml:"auto_restrict_window_days"`
+	AutoRestrictHours      int      `yaml:"auto_restrict_hours"`
+}
+
+// AntiCheat analyses BatchSize finished games every IntervalSec. A side of a game is flagged when FastShotRatio of at
+// least MinShots shots came faster than MinReactionMs, or when its hits on cells it had no information about are
+// HitRateZScore deviations above chance. The winner of a pair is flagged when it has at least PairMinGames games with
+// the loser in PairWindowDays, won PairWinRatio of them and at least PairMinQuickLosses of the losses were abandons
+// or timeouts after at most QuickLossMaxMoves moves. A flag keeps MaxEvidence last evidences.
+type AntiCheat struct {
+	IntervalSec        int     `yaml:"interval_sec"`
+	BatchSize          int     `yaml:"batch_size"`
+	MinShots           int     `yaml:"min_shots"`
+	MinReactionMs      int     `yaml:"min_reaction_ms"`
+	FastShotRatio      float64 `yaml:"fast_shot_ratio"`
+	HitRateZScore      float64 `yaml:"hit_rate_z_score"`
+	PairWindowDays     int     `yaml:"pair_window_days"`
+	PairMinGames       int     `yaml:"pair_min_games"`
+	PairWinRatio       float64 `yaml:"pair_win_ratio"`
+	PairMinQuickLosses int     `yaml:"pair_min_quick_losses"`
+	QuickLossMaxMoves  int     `yaml:"quick_loss_max_moves"`
+	MaxEvidence        int     `yaml:"max_evidence"`
+}
+
+type Chat struct {
+	MaxLength          int      `yaml:"max_length"`
+	RateLimitCount     int      `yaml:"rate_limit_count"`
+	RateLimitWindowSec int      `yaml:"rate_limit_window_sec"`
+	HistorySize        int      `yaml:"history_size"`
+	Emotes             []string `yaml:"emotes"`
+	ProfanityFilter    string   `yaml:"profanity_filter"` //none or word_list
+	BannedWords        []string `yaml:"banned_words"`
+}
+
+type Invite struct {
+	ExpireSec           int `yaml:"expire_sec"`
+	MinPasscodeLength   int `yaml:"min_passcode_length"`
+	MaxPasscodeLength   int `yaml:"max_passcode_length"`
+	MaxPasscodeFailures int `yaml:"max_passcode_failures"` //wrong passcodes after which the game cannot be joined
+}
+
+type Leaderboard struct {
+	PageSize           int      `yaml:"page_size"`
+	MaxPageSize        int      `yaml:"max_page_size"`
+	ArchiveTop         int      `yaml:"archive_top"`
+	ArchiveIntervalSec int      `yaml:"archive_interval_sec"`
+	Seasons            []Season `yaml:"seasons"`
+}
+
+type Season struct {
+	Id    string `yaml:"id"`
+	Name  string `yaml:"name"`
+	Start string `yaml:"start"` //RFC3339
+	End   string `yaml:"end"`   //RFC3339
+}
+
+// Period returns season start and end, zero times are returned for malformed dates.
+func (r Season) Period() (start time.Time, end time.Time) {
+	start, err := time.Parse(time.RFC3339, r.Start)
+	if err != nil {
+		log.Error().Str("season", r.Id).Str("start", r.Start).Msg("season start is not RFC3339")
+	}
+	end, err = time.Parse(time.RFC3339, r.End)
+	if err != nil {
+		log.Error().Str("season", r.Id).Str("end", r.End).Msg("season end is not RFC3339")
+	}
+	return start, end
+}
+
+// SeasonAt returns the configured season that contains t.
+func SeasonAt(t time.Time) (Season, bool) {
+	for _, s := range C.Leaderboard.Seasons {
+		start, end := s.Period()
+		if !t.Before(start) && t.Before(end) {
+			return s, true
+		}
+	}
+	return Season{}, false
+}
+
+func FindSeason(id string) (Season, bool) {
+	for _, s := range C.Leaderboard.Seasons {
+		if s.Id == id {
+			return s, true
+		}
+	}
+	return Season{}, false
+}
+
+type IdKey struct {
+	Version int    `yaml:"version"`
+	Secret  string `yaml:"secret"`
+}
+
 func Init(filename string) {
 	loadConfigs(filename)
 	logConfigure()
@@ -71,7 +298,34 @@ func loadConfigs(filename string) {
 	}
 
 	C = c
-	log.Info().Msgf("Following configuration is loaded:\n%+v\n", c)
+	log.Info().Msgf("Following configuration is loaded:\n%+v\n", c.redacted())
+}
+
+// redacted returns a copy of the config to log, secrets are masked.
+func (r Config) redacted() Config {
+	r.MongoDB.Password = mask(r.MongoDB.Password)
+	r.Otp.HashKey = mask(r.Otp.HashKey)
+	keys := make([]IdKey, len(r.Ids.Keys))
+	for i, key := range r.Ids.Keys {
+		key.Secret = mask(key.Secret)
+		keys[i] = key
+	}
+	r.Ids.Keys = keys
+	endpoints := make([]WebhookEndpoint, len(r.Webhook.Endpoints))
+	for i, endpoint := range r.Webhook.Endpoints {
+		endpoint.Secret = mask(endpoint.Secret)
+		endpoints[i] = endpoint
+	}
+	r.Webhook.Endpoints = endpoints
+	r.Admin.Token = mask(r.Admin.Token)
+	return r
+}
+
+func mask(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "***"
 }
 
 func logConfigure() {
@@ -0,0 +1,470 @@
+package controllers
+
+import (
+	"battleship/dto"
+	"battleship/middlewares"
+	"battleship/service"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+type AdminController interface {
+	RedeliverWebhook(ctx echo.Context) error
+	GetMaintenance(ctx echo.Context) error
+	SetMaintenance(ctx echo.Context) error
+	Broadcast(ctx echo.Context) error
+	GetLiveGames(ctx echo.Context) error
+	GetGame(ctx echo.Context) error
+	FinishGame(ctx echo.Context) error
+	CancelGame(ctx echo.Context) error
+	BanUser(ctx echo.Context) error
+	UnbanUser(ctx echo.Context) error
+	GetAudit(ctx echo.Context) error
+	GetReports(ctx echo.Context) error
+	ReviewReport(ctx echo.Context) error
+	GetCheatFlags(ctx echo.Context) error
+	ReviewCheatFlag(ctx echo.Context) error
+}
+
+type AdminControllerImpl struct {
+	adminService service.AdminService
+}
+
+func NewAdminControllerImpl(adminService service.AdminService) AdminControllerImpl {
+	return AdminControllerImpl{
+		adminService: adminService,
+	}
+}
+
+// Redeliver webhook
+// @Summary Redeliver webhook
+// @Description Send a webhook delivery of the delivery log again, it gets a new set of retries. A delivery that is being attempted is refused with a conflict
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-Actor header string false "Name of the admin, it is recorded in the audit"
+// @Param RedeliverWebhookRequest body dto.RedeliverWebhookRequest true "Redeliver Webhook Request"
+// @Success 200 {object} dto.RedeliverWebhookResponse "Redeliver Webhook Response"
+// @Router /api/admin/webhook/redeliver [post]
+func (r AdminControllerImpl) RedeliverWebhook(ctx echo.Context) error {
+	request := new(dto.RedeliverWebhookRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	request.AdminActor = adminActor(ctx)
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.RedeliverWebhook(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Str("delivery_id", request.DeliveryId).Msg("cannot redeliver webhook")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Get maintenance
+// @Summary Get maintenance mode
+// @Description Get maintenance mode of the server
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} dto.MaintenanceResponse "Maintenance Response"
+// @Router /api/admin/maintenance [get]
+func (r AdminControllerImpl) GetMaintenance(ctx echo.Context) error {
+	response, err := r.adminService.GetMaintenance(ctx.Request().Context())
+	if err != nil {
+		log.Info().Err(err).Msg("cannot get maintenance mode")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Set maintenance
+// @Summary Set maintenance mode
+// @Description In maintenance mode new games are rejected and games in progress go on, the mode is kept in the db
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-Actor header string false "Name of the admin, it is recorded in the audit"
+// @Param SetMaintenanceRequest body dto.SetMaintenanceRequest true "Set Maintenance Request"
+// @Success 200 {object} dto.MaintenanceResponse "Maintenance Response"
+// @Router /api/admin/maintenance [put]
+func (r AdminControllerImpl) SetMaintenance(ctx echo.Context) error {
+	request := new(dto.SetMaintenanceRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	request.AdminActor = adminActor(ctx)
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.SetMaintenance(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Msg("cannot set maintenance mode")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Broadcast
+// @Summary Broadcast notice
+// @Description Send a notice to all sockets connected to this server
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-Actor header string false "Name of the admin, it is recorded in the audit"
+// @Param BroadcastRequest body dto.BroadcastRequest true "Broadcast Request"
+// @Success 200 {object} dto.BroadcastResponse "Broadcast Response"
+// @Router /api/admin/broadcast [post]
+func (r AdminControllerImpl) Broadcast(ctx echo.Context) error {
+	request := new(dto.BroadcastRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	request.AdminActor = adminActor(ctx)
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.Broadcast(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Msg("cannot broadcast notice")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Get live games
+// @Summary Get live games
+// @Description Get joined, started and paused games with the socket status of their sides on this server
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param limit query int false "Limit"
+// @Success 200 {object} dto.GetLiveGamesResponse "Get Live Games Response"
+// @Router /api/admin/game/live [get]
+func (r AdminControllerImpl) GetLiveGames(ctx echo.Context) error {
+	request := new(dto.GetLiveGamesRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.GetLiveGames(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Msg("cannot get live games")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Get game
+// @Summary Get game as admin
+// @Description Get the game as it is stored, with the ships of both sides, and its event log
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param game_id path string true "Game Id"
+// @Success 200 {object} dto.AdminGameResponse "Admin Game Response"
+// @Router /api/admin/game/{game_id} [get]
+func (r AdminControllerImpl) GetGame(ctx echo.Context) error {
+	request := new(dto.AdminGameRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.GetGame(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Str("game_id", request.GameId).Msg("cannot get game")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Finish game
+// @Summary Finish game
+// @Description Finish a started or paused game with a reason, the game has no winner when winner_user_id is empty
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-Actor header string false "Name of the admin, it is recorded in the audit"
+// @Param FinishGameRequest body dto.FinishGameRequest true "Finish Game Request"
+// @Success 200 {object} dto.AdminGameResponse "Admin Game Response"
+// @Router /api/admin/game/finish [post]
+func (r AdminControllerImpl) FinishGame(ctx echo.Context) error {
+	request := new(dto.FinishGameRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	request.AdminActor = adminActor(ctx)
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.FinishGame(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Str("game_id", request.GameId).Msg("cannot finish game")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Cancel game
+// @Summary Cancel game
+// @Description Cancel a game that is not started yet with a reason
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-Actor header string false "Name of the admin, it is recorded in the audit"
+// @Param CancelGameAdminRequest body dto.CancelGameAdminRequest true "Cancel Game Request"
+// @Success 200 {object} dto.AdminGameResponse "Admin Game Response"
+// @Router /api/admin/game/cancel [post]
+func (r AdminControllerImpl) CancelGame(ctx echo.Context) error {
+	request := new(dto.CancelGameAdminRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	request.AdminActor = adminActor(ctx)
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.CancelGame(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Str("game_id", request.GameId).Msg("cannot cancel game")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Ban user
+// @Summary Ban user
+// @Description Ban a user from logging in and starting games, the ban is permanent when duration_hours is 0
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-Actor header string false "Name of the admin, it is recorded in the audit"
+// @Param BanUserRequest body dto.BanUserRequest true "Ban User Request"
+// @Success 200 {object} dto.UserBanResponse "User Ban Response"
+// @Router /api/admin/user/ban [post]
+func (r AdminControllerImpl) BanUser(ctx echo.Context) error {
+	request := new(dto.BanUserRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	request.AdminActor = adminActor(ctx)
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.BanUser(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Str("user_id", request.UserId).Msg("cannot ban user")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Unban user
+// @Summary Unban user
+// @Description Remove the ban of a user
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-Actor header string false "Name of the admin, it is recorded in the audit"
+// @Param UnbanUserRequest body dto.UnbanUserRequest true "Unban User Request"
+// @Success 200 {object} dto.UserBanResponse "User Ban Response"
+// @Router /api/admin/user/unban [post]
+func (r AdminControllerImpl) UnbanUser(ctx echo.Context) error {
+	request := new(dto.UnbanUserRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	request.AdminActor = adminActor(ctx)
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.UnbanUser(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Str("user_id", request.UserId).Msg("cannot unban user")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Get audit
+// @Summary Get admin audit
+// @Description Get the admin actions from the newest, of one target or of all targets
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param target_id query string false "Game, user or webhook delivery id"
+// @Param limit query int false "Limit"
+// @Success 200 {object} dto.GetAuditResponse "Get Audit Response"
+// @Router /api/admin/audit [get]
+func (r AdminControllerImpl) GetAudit(ctx echo.Context) error {
+	request := new(dto.GetAuditRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.GetAudit(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Msg("cannot get admin audit")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Get reports
+// @Summary Get reports
+// @Description Get the user reports of a status from the oldest, pending reports are the moderation queue
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param status query string false "pending (default), confirmed or dismissed"
+// @Param limit query int false "Limit"
+// @Success 200 {object} dto.GetReportsResponse "Get Reports Response"
+// @Router /api/admin/report [get]
+func (r AdminControllerImpl) GetReports(ctx echo.Context) error {
+	request := new(dto.GetReportsRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.GetReports(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Msg("cannot get reports")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Review report
+// @Summary Review report
+// @Description Confirm or dismiss a pending report, the reported user is restricted after enough confirmed reports
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-Actor header string false "Name of the admin, it is recorded in the audit"
+// @Param ReviewReportRequest body dto.ReviewReportRequest true "Review Report Request"
+// @Success 200 {object} dto.ReviewReportResponse "Review Report Response"
+// @Router /api/admin/report/review [post]
+func (r AdminControllerImpl) ReviewReport(ctx echo.Context) error {
+	request := new(dto.ReviewReportRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	request.AdminActor = adminActor(ctx)
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.ReviewReport(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Str("report_id", request.ReportId).Msg("cannot review report")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Get cheat flags
+// @Summary Get cheat flags
+// @Description Get the flags of the anti-cheat analysis of a status from the oldest with their evidence, pending flags are the review queue
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param status query string false "pending (default), confirmed or dismissed"
+// @Param limit query int false "Limit"
+// @Success 200 {object} dto.GetCheatFlagsResponse "Get Cheat Flags Response"
+// @Router /api/admin/cheat-flag [get]
+func (r AdminControllerImpl) GetCheatFlags(ctx echo.Context) error {
+	request := new(dto.GetCheatFlagsRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.GetCheatFlags(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Msg("cannot get cheat flags")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Review cheat flag
+// @Summary Review cheat flag
+// @Description Confirm or dismiss a pending cheat flag, the user is not banned by the review
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-Actor header string false "Name of the admin, it is recorded in the audit"
+// @Param ReviewCheatFlagRequest body dto.ReviewCheatFlagRequest true "Review Cheat Flag Request"
+// @Success 200 {object} dto.ReviewCheatFlagResponse "Review Cheat Flag Response"
+// @Router /api/admin/cheat-flag/review [post]
+func (r AdminControllerImpl) ReviewCheatFlag(ctx echo.Context) error {
+	request := new(dto.ReviewCheatFlagRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	request.AdminActor = adminActor(ctx)
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	response, err := r.adminService.ReviewCheatFlag(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Str("flag_id", request.FlagId).Msg("cannot review cheat flag")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func adminActor(ctx echo.Context) dto.AdminActor {
+	return dto.AdminActor{
+		Actor:    ctx.Request().Header.Get(middlewares.AdminActorHeader),
+		RemoteIp: ctx.RealIP(),
+	}
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"battleship/dto"
+	"battleship/service"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+type AuthController interface {
+	RequestOtp(ctx echo.Context) error
+	VerifyOtp(ctx echo.Context) error
+}
+
+type AuthControllerImpl struct {
+	authService service.AuthService
+}
+
+func NewAuthControllerImpl(authService service.AuthService) AuthControllerImpl {
+	return AuthControllerImpl{
+		authService: authService,
+	}
+}
+
+// Request OTP
+// @Summary Request OTP
+// @Description send a one time password to the mobile number
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RequestOtpRequest true "Request OTP Request"
+// @Success 200 {object} dto.RequestOtpResponse "Request OTP Response"
+// @Router /api/v1/auth/otp [post]
+func (r AuthControllerImpl) RequestOtp(ctx echo.Context) error {
+	request := new(dto.RequestOtpRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	res, err := r.authService.RequestOtp(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("mobile", request.Mobile).Err(err).Msg("cannot request otp")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, res)
+}
+
+// Verify OTP
+// @Summary Verify OTP
+// @Description verify the one time password and create a session, user is created if mobile is new
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.VerifyOtpRequest true "Verify OTP Request"
+// @Success 200 {object} dto.VerifyOtpResponse "Verify OTP Response"
+// @Router /api/v1/auth/otp/verify [post]
+func (r AuthControllerImpl) VerifyOtp(ctx echo.Context) error {
+	request := new(dto.VerifyOtpRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	res, err := r.authService.VerifyOtp(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("mobile", request.Mobile).Err(err).Msg("cannot verify otp")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, res)
+}
@@ -3,7 +3,6 @@ package controllers
 import (
 	"battleship/dto"
 	"battleship/service"
-	"battleship/utils"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
 	"net/http"
@@ -18,15 +17,24 @@ type GameController interface {
 	ChangeTurn(ctx echo.Context) error
 	RevealEnemyFields(ctx echo.Context) error
 	Explode(ctx echo.Context) error
+	GetUserGames(ctx echo.Context) error
+	CancelGame(ctx echo.Context) error
+	RequestPause(ctx echo.Context) error
+	AcceptPause(ctx echo.Context) error
+	Resume(ctx echo.Context) error
+	CreateInvite(ctx echo.Context) error
+	RevokeInvite(ctx echo.Context) error
 }
 
 type GameControllerImpl struct {
-	gameService service.GameService
+	gameService   service.GameService
+	inviteService service.InviteService
 }
 
-func NewGameControllerImpl(gameService service.GameService) GameControllerImpl {
+func NewGameControllerImpl(gameService service.GameService, inviteService service.InviteService) GameControllerImpl {
 	return GameControllerImpl{
-		gameService: gameService,
+		gameService:   gameService,
+		inviteService: inviteService,
 	}
 }
 
@@ -49,7 +57,7 @@ func (r GameControllerImpl) CreateGame(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
-	res, err := r.gameService.CreateGame(*request)
+	res, err := r.gameService.CreateGame(ctx.Request().Context(), *request)
 	if err != nil {
 		log.Info().Str("userId", request.UserId).Err(err).Msg("cannot create game")
 		return err
@@ -80,11 +88,15 @@ func (r GameControllerImpl) GetGame(ctx echo.Context) error {
 		return dto.BadRequest1("user_id must has value")
 	}
 	request := dto.GetGameRequest{
-		UserGameRequest: dto.UserGameRequest{UserId: utils.MaskId(userId), GameId: utils.MaskId(gameId)},
+		UserGameRequest: dto.UserGameRequest{UserId: userId, GameId: gameId},
 	}
-	game, err := r.gameService.GetGame(request)
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	game, err := r.gameService.GetGame(ctx.Request().Context(), request)
 	if err != nil {
-		log.Info().Str("gameId", utils.MaskId(gameId)).Err(err).Msg("cannot get Game")
+		log.Info().Str("gameId", request.GameId).Err(err).Msg("cannot get Game")
 		return err
 	}
 	return ctx.JSON(http.StatusOK, game)
@@ -92,7 +104,7 @@ func (r GameControllerImpl) GetGame(ctx echo.Context) error {
 
 // Join game
 // @Summary Join game
-// @Description Join to a battleship game instance
+// @Description Join to a battleship game instance by game id or invite code, private games need the invite code
 // @Tags Game
 // @Accept json
 // @Produce json
@@ -109,7 +121,7 @@ func (r GameControllerImpl) JoinGame(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
-	response, err := r.gameService.JoinGame(*request)
+	response, err := r.gameService.JoinGame(ctx.Request().Context(), *request)
 	if err != nil {
 		log.Info().Str("userId", request.UserId).Err(err).Msg("cannot join game")
 		return err
@@ -120,7 +132,7 @@ func (r GameControllerImpl) JoinGame(ctx echo.Context) error {
 
 // submit ship locations
 // @Summary submit ship locations
-// @Description submit ship locations
+// @Description submit ship locations. In commit-reveal games commitment is the hex sha256 of "i1,i2,...,i10:salt" with the indexes sorted ascending, and salt is revealed to both sides when the game is finished. toss_seed is the random value of the side for the first turn coin toss, the proof is in the toss field of the game
 // @Tags Game
 // @Accept json
 // @Produce json
@@ -138,7 +150,7 @@ func (r GameControllerImpl) SubmitShipsLocations(ctx echo.Context) error {
 		return err
 	}
 
-	response, err := r.gameService.SubmitShipsLocations(*request)
+	response, err := r.gameService.SubmitShipsLocations(ctx.Request().Context(), *request)
 	if err != nil {
 		return err
 	}
@@ -165,7 +177,7 @@ func (r GameControllerImpl) MoveShip(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
-	response, err := r.gameService.MoveShip(*request)
+	response, err := r.gameService.MoveShip(ctx.Request().Context(), *request)
 	if err != nil {
 		return err
 	}
@@ -193,7 +205,7 @@ func (r GameControllerImpl) ChangeTurn(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
-	response, err := r.gameService.ChangeTurn(*request)
+	response, err := r.gameService.ChangeTurn(ctx.Request().Context(), *request)
 	if err != nil {
 		return err
 	}
@@ -221,7 +233,7 @@ func (r GameControllerImpl) RevealEnemyFields(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
-	response, err := r.gameService.Reveal(*request)
+	response, err := r.gameService.Reveal(ctx.Request().Context(), *request)
 	if err != nil {
 		return err
 	}
@@ -249,10 +261,205 @@ func (r GameControllerImpl) Explode(ctx echo.Context) error {
 	if err != nil {
 		return err
 	}
-	response, err := r.gameService.Explode(*request)
+	response, err := r.gameService.Explode(ctx.Request().Context(), *request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Get user games
+// @Summary Get user games
+// @Description Get game history of user from the newest, next_cursor is empty on the last page
+// @Tags Game
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User Id"
+// @Param status query string false "init, joined, start or finished"
+// @Param result query string false "win or loss"
+// @Param from query string false "create date from, RFC3339"
+// @Param to query string false "create date to, RFC3339"
+// @Param opponent_id query string false "Opponent User Id"
+// @Param cursor query string false "next_cursor of previous page"
+// @Param limit query int false "Page size, 20 by default"
+// @Success 200 {object} dto.GetUserGamesResponse "Get User Games Response"
+// @Router /api/v1/user/{user_id}/games [get]
+func (r GameControllerImpl) GetUserGames(ctx echo.Context) error {
+	request := new(dto.GetUserGamesRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.gameService.GetUserGames(ctx.Request().Context(), *request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Cancel game
+// @Summary Cancel game
+// @Description Cancel a game that is not started yet, only creator of the game can cancel it
+// @Tags Game
+// @Accept json
+// @Produce json
+// @Param request body dto.CancelGameRequest true "Cancel Game Request"
+// @Success 200 {object} dto.GetGameResponse "Get Game Response"
+// @Router /api/v1/game/cancel [post]
+func (r GameControllerImpl) CancelGame(ctx echo.Context) error {
+	request := new(dto.CancelGameRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.gameService.CancelGame(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("userId", request.UserId).Str("gameId", request.GameId).Err(err).Msg("cannot cancel game")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Request pause
+// @Summary Request pause
+// @Description Ask the other side to pause a started game, turn timers are frozen while the game is paused
+// @Tags Game
+// @Accept json
+// @Produce json
+// @Param request body dto.PauseGameRequest true "Pause Game Request"
+// @Success 200 {object} dto.PauseGameResponse "Pause Game Response"
+// @Router /api/v1/game/pause [post]
+func (r GameControllerImpl) RequestPause(ctx echo.Context) error {
+	request := new(dto.PauseGameRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.gameService.RequestPause(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("userId", request.UserId).Str("gameId", request.GameId).Err(err).Msg("cannot request pause")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Accept pause
+// @Summary Accept pause
+// @Description Accept the pause request of the other side, the game is resumed automatically after pause_deadline
+// @Tags Game
+// @Accept json
+// @Produce json
+// @Param request body dto.AcceptPauseRequest true "Accept Pause Request"
+// @Success 200 {object} dto.PauseGameResponse "Pause Game Response"
+// @Router /api/v1/game/pause/accept [post]
+func (r GameControllerImpl) AcceptPause(ctx echo.Context) error {
+	request := new(dto.AcceptPauseRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.gameService.AcceptPause(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("userId", request.UserId).Str("gameId", request.GameId).Err(err).Msg("cannot accept pause")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Resume game
+// @Summary Resume game
+// @Description Ask to resume a paused game, the game is resumed when both sides have asked
+// @Tags Game
+// @Accept json
+// @Produce json
+// @Param request body dto.ResumeGameRequest true "Resume Game Request"
+// @Success 200 {object} dto.PauseGameResponse "Pause Game Response"
+// @Router /api/v1/game/resume [post]
+func (r GameControllerImpl) Resume(ctx echo.Context) error {
+	request := new(dto.ResumeGameRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.gameService.Resume(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("userId", request.UserId).Str("gameId", request.GameId).Err(err).Msg("cannot resume game")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Create invite
+// @Summary Create invite
+// @Description Create a new invite code for a game that is waiting for the second side, only creator of the game can invite
+// @Tags Game
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateInviteRequest true "Create Invite Request"
+// @Success 200 {object} dto.InviteResponse "Invite Response"
+// @Router /api/v1/game/invite [post]
+func (r GameControllerImpl) CreateInvite(ctx echo.Context) error {
+	request := new(dto.CreateInviteRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.inviteService.CreateInvite(ctx.Request().Context(), *request)
 	if err != nil {
+		log.Info().Str("userId", request.UserId).Str("gameId", request.GameId).Err(err).Msg("cannot create invite")
 		return err
 	}
+	return ctx.JSON(http.StatusOK, response)
+}
 
+// Revoke invite
+// @Summary Revoke invite
+// @Description Revoke an invite code, only creator of the invite can revoke it
+// @Tags Game
+// @Accept json
+// @Produce json
+// @Param request body dto.RevokeInviteRequest true "Revoke Invite Request"
+// @Success 200 {object} dto.RevokeInviteResponse "Revoke Invite Response"
+// @Router /api/v1/game/invite/revoke [post]
+func (r GameControllerImpl) RevokeInvite(ctx echo.Context) error {
+	request := new(dto.RevokeInviteRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.inviteService.RevokeInvite(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("userId", request.UserId).Err(err).Msg("cannot revoke invite")
+		return err
+	}
 	return ctx.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"battleship/dto"
+	"battleship/service"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type HealthController interface {
+	Livez(ctx echo.Context) error
+	Readyz(ctx echo.Context) error
+}
+
+type HealthControllerImpl struct {
+	healthService service.HealthService
+}
+
+func NewHealthControllerImpl(healthService service.HealthService) HealthControllerImpl {
+	return HealthControllerImpl{
+		healthService: healthService,
+	}
+}
+
+// Liveness probe
+// @Summary Liveness probe
+// @Description Returns ok while the process serves http, dependencies are not checked
+// @Tags Health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse "Health Response"
+// @Router /livez [get]
+func (r HealthControllerImpl) Livez(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, r.healthService.Live(ctx.Request().Context()))
+}
+
+// Readiness probe
+// @Summary Readiness probe
+// @Description Checks mongodb, the connection registry, scheduled jobs and the server mode. It returns 503 if any check fails
+// @Tags Health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse "Health Response"
+// @Failure 503 {object} dto.HealthResponse "Health Response"
+// @Router /readyz [get]
+func (r HealthControllerImpl) Readyz(ctx echo.Context) error {
+	response := r.healthService.Ready(ctx.Request().Context())
+	if response.Status == dto.HealthFail {
+		return ctx.JSON(http.StatusServiceUnavailable, response)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"battleship/dto"
+	"battleship/service"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+type LeaderboardController interface {
+	GetLeaderboard(ctx echo.Context) error
+	GetSeasons(ctx echo.Context) error
+}
+
+type LeaderboardControllerImpl struct {
+	leaderboardService service.LeaderboardService
+}
+
+func NewLeaderboardControllerImpl(leaderboardService service.LeaderboardService) LeaderboardControllerImpl {
+	return LeaderboardControllerImpl{
+		leaderboardService: leaderboardService,
+	}
+}
+
+// Get leaderboard
+// @Summary Get leaderboard
+// @Description Get a page of all-time, seasonal or weekly leaderboard, my_position is filled when user_id is given
+// @Tags Leaderboard
+// @Accept json
+// @Produce json
+// @Param board query string false "all_time, season or weekly"
+// @Param season_id query string false "Season Id, current season if empty"
+// @Param page query int false "Page, starts from 1"
+// @Param page_size query int false "Page size"
+// @Param user_id query string false "User Id"
+// @Success 200 {object} dto.GetLeaderboardResponse "Get Leaderboard Response"
+// @Router /api/v1/leaderboard [get]
+func (r LeaderboardControllerImpl) GetLeaderboard(ctx echo.Context) error {
+	request := new(dto.GetLeaderboardRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.leaderboardService.GetLeaderboard(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("board", string(request.Board)).Err(err).Msg("cannot get leaderboard")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Get seasons
+// @Summary Get seasons
+// @Description Get configured seasons, winners of archived seasons are included
+// @Tags Leaderboard
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.GetSeasonsResponse "Get Seasons Response"
+// @Router /api/v1/leaderboard/seasons [get]
+func (r LeaderboardControllerImpl) GetSeasons(ctx echo.Context) error {
+	response, err := r.leaderboardService.GetSeasons(ctx.Request().Context())
+	if err != nil {
+		log.Info().Err(err).Msg("cannot get seasons")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"battleship/dto"
+	"battleship/service"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+type LobbyController interface {
+	GetLobby(ctx echo.Context) error
+}
+
+type LobbyControllerImpl struct {
+	lobbyService service.LobbyService
+}
+
+func NewLobbyControllerImpl(lobbyService service.LobbyService) LobbyControllerImpl {
+	return LobbyControllerImpl{
+		lobbyService: lobbyService,
+	}
+}
+
+// Get lobby
+// @Summary Get lobby
+// @Description Get public games that are waiting for the second side, from the newest. Live updates are sent on /socket/lobby
+// @Tags Game
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of games"
+// @Param user_id query string false "User Id, games of users blocked with the user are left out"
+// @Success 200 {object} dto.GetLobbyResponse "Get Lobby Response"
+// @Router /api/v1/lobby [get]
+func (r LobbyControllerImpl) GetLobby(ctx echo.Context) error {
+	request := new(dto.GetLobbyRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	response, err := r.lobbyService.GetLobby(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Err(err).Msg("cannot get lobby")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"battleship/dto"
+	"battleship/service"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+type MatchmakingController interface {
+	Enqueue(ctx echo.Context) error
+	Cancel(ctx echo.Context) error
+}
+
+type MatchmakingControllerImpl struct {
+	matchmakingService service.MatchmakingService
+}
+
+func NewMatchmakingControllerImpl(matchmakingService service.MatchmakingService) MatchmakingControllerImpl {
+	return MatchmakingControllerImpl{
+		matchmakingService: matchmakingService,
+	}
+}
+
+// Enqueue for matchmaking
+// @Summary Enqueue for matchmaking
+// @Description wait for a random opponent with the same settings, match_found event is sent on /socket/user when paired
+// @Tags Matchmaking
+// @Accept json
+// @Produce json
+// @Param request body dto.EnqueueMatchmakingRequest true "Enqueue Matchmaking Request"
+// @Success 200 {object} dto.EnqueueMatchmakingResponse "Enqueue Matchmaking Response"
+// @Router /api/v1/matchmaking/enqueue [post]
+func (r MatchmakingControllerImpl) Enqueue(ctx echo.Context) error {
+	request := new(dto.EnqueueMatchmakingRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	res, err := r.matchmakingService.Enqueue(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("userId", request.UserId).Err(err).Msg("cannot enqueue for matchmaking")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, res)
+}
+
+// Cancel matchmaking
+// @Summary Cancel matchmaking
+// @Description leave the matchmaking queue
+// @Tags Matchmaking
+// @Accept json
+// @Produce json
+// @Param request body dto.CancelMatchmakingRequest true "Cancel Matchmaking Request"
+// @Success 200 {object} dto.CancelMatchmakingResponse "Cancel Matchmaking Response"
+// @Router /api/v1/matchmaking/cancel [post]
+func (r MatchmakingControllerImpl) Cancel(ctx echo.Context) error {
+	request := new(dto.CancelMatchmakingRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	res, err := r.matchmakingService.Cancel(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("userId", request.UserId).Err(err).Msg("cannot cancel matchmaking")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, res)
+}
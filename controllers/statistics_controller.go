@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"battleship/dto"
+	"battleship/service"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+type StatisticsController interface {
+	GetUserStats(ctx echo.Context) error
+}
+
+type StatisticsControllerImpl struct {
+	statisticsService service.StatisticsService
+}
+
+func NewStatisticsControllerImpl(statisticsService service.StatisticsService) StatisticsControllerImpl {
+	return StatisticsControllerImpl{
+		statisticsService: statisticsService,
+	}
+}
+
+// Get user statistics
+// @Summary Get user statistics
+// @Description Get statistics of user finished games
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User Id"
+// @Success 200 {object} dto.UserStatsResponse "User Stats Response"
+// @Router /api/v1/user/{user_id}/stats [get]
+func (r StatisticsControllerImpl) GetUserStats(ctx echo.Context) error {
+	userId, err := dto.UnmaskId(ctx.Param("user_id"))
+	if err != nil {
+		return err
+	}
+
+	response, err := r.statisticsService.GetUserStats(ctx.Request().Context(), userId)
+	if err != nil {
+		log.Info().Str("userId", userId).Err(err).Msg("cannot get user stats")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -3,7 +3,6 @@ package controllers
 import (
 	"battleship/dto"
 	"battleship/service"
-	"battleship/utils"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
 	"net/http"
@@ -12,15 +11,25 @@ import (
 type UserController interface {
 	CreateUser(ctx echo.Context) error
 	GetUser(ctx echo.Context) error
+	GetRatingHistory(ctx echo.Context) error
+	BlockUser(ctx echo.Context) error
+	UnblockUser(ctx echo.Context) error
+	GetBlocks(ctx echo.Context) error
+	ReportUser(ctx echo.Context) error
 }
 
 type UserControllerImpl struct {
-	userService service.UserService
+	userService   service.UserService
+	blockService  service.BlockService
+	reportService service.ReportService
 }
 
-func NewUserController(userService service.UserService) UserControllerImpl {
+func NewUserController(userService service.UserService, blockService service.BlockService,
+	reportService service.ReportService) UserControllerImpl {
 	return UserControllerImpl{
-		userService: userService,
+		userService:   userService,
+		blockService:  blockService,
+		reportService: reportService,
 	}
 }
 
@@ -40,7 +49,7 @@ func (r UserControllerImpl) CreateUser(ctx echo.Context) error {
 		return dto.BadRequest1(err.Error())
 	}
 
-	res, err := r.userService.CreateUser(*request)
+	res, err := r.userService.CreateUser(ctx.Request().Context(), *request)
 	if err != nil {
 		log.Info().Err(err).Msg("cannot create user")
 		return err
@@ -64,10 +73,144 @@ func (r UserControllerImpl) GetUser(ctx echo.Context) error {
 		return dto.BadRequest1("userId must has value")
 	}
 
-	user, err := r.userService.GetUser(utils.MaskId(userId))
+	userId, err := dto.UnmaskId(userId)
 	if err != nil {
-		log.Info().Str("userId", utils.MaskId(userId)).Err(err).Msg("cannot get user")
+		return err
+	}
+
+	user, err := r.userService.GetUser(ctx.Request().Context(), userId)
+	if err != nil {
+		log.Info().Str("userId", userId).Err(err).Msg("cannot get user")
 		return err
 	}
 	return ctx.JSON(http.StatusOK, user)
 }
+
+// Get user rating history
+// @Summary Get user rating history
+// @Description Get the latest rating changes of user in ranked games
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param user_id path string true " "
+// @Success 200 {object} dto.RatingHistoryResponse "Rating History Response"
+// @Router /api/v1/user/{user_id}/rating-history [get]
+func (r UserControllerImpl) GetRatingHistory(ctx echo.Context) error {
+	userId, err := dto.UnmaskId(ctx.Param("user_id"))
+	if err != nil {
+		return err
+	}
+
+	response, err := r.userService.GetRatingHistory(ctx.Request().Context(), userId)
+	if err != nil {
+		log.Info().Str("userId", userId).Err(err).Msg("cannot get rating history")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Block user
+// @Summary Block user
+// @Description Block a user, the users are never paired by matchmaking and cannot join games of each other
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param user_id path string true " "
+// @Param BlockUserRequest body dto.BlockUserRequest true "Block User Request"
+// @Success 200 {object} dto.GetBlocksResponse "Get Blocks Response"
+// @Router /api/v1/user/{user_id}/block [post]
+func (r UserControllerImpl) BlockUser(ctx echo.Context) error {
+	request := new(dto.BlockUserRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.blockService.Block(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("userId", request.UserId).Err(err).Msg("cannot block user")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Unblock user
+// @Summary Unblock user
+// @Description Remove a block of the user
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param user_id path string true " "
+// @Param BlockUserRequest body dto.BlockUserRequest true "Block User Request"
+// @Success 200 {object} dto.GetBlocksResponse "Get Blocks Response"
+// @Router /api/v1/user/{user_id}/unblock [post]
+func (r UserControllerImpl) UnblockUser(ctx echo.Context) error {
+	request := new(dto.BlockUserRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.blockService.Unblock(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("userId", request.UserId).Err(err).Msg("cannot unblock user")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Get blocks
+// @Summary Get blocked users
+// @Description Get the users blocked by the user
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param user_id path string true " "
+// @Success 200 {object} dto.GetBlocksResponse "Get Blocks Response"
+// @Router /api/v1/user/{user_id}/blocks [get]
+func (r UserControllerImpl) GetBlocks(ctx echo.Context) error {
+	userId, err := dto.UnmaskId(ctx.Param("user_id"))
+	if err != nil {
+		return err
+	}
+
+	response, err := r.blockService.GetBlocks(ctx.Request().Context(), userId)
+	if err != nil {
+		log.Info().Str("userId", userId).Err(err).Msg("cannot get blocks")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// Report user
+// @Summary Report user
+// @Description Report the opponent of a game, the chat of the game is attached to the report
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param ReportUserRequest body dto.ReportUserRequest true "Report User Request"
+// @Success 200 {object} dto.ReportUserResponse "Report User Response"
+// @Router /api/v1/report [post]
+func (r UserControllerImpl) ReportUser(ctx echo.Context) error {
+	request := new(dto.ReportUserRequest)
+	if err := ctx.Bind(request); err != nil {
+		log.Warn().Err(err).Msg("Bad request")
+		return dto.BadRequest1(err.Error())
+	}
+	err := request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
+	response, err := r.reportService.Report(ctx.Request().Context(), *request)
+	if err != nil {
+		log.Info().Str("userId", request.UserId).Err(err).Msg("cannot report user")
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
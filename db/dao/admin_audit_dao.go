@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type AdminAuditDao interface {
+	Insert(ctx context.Context, audit model.AdminAudit) (id string, err error)
+	FindLast(ctx context.Context, targetId string, limit int64) (audits []model.AdminAudit, err error)
+}
+
+type AdminAuditDaoImpl struct {
+}
+
+func NewAdminAuditDaoImpl() AdminAuditDaoImpl {
+	return AdminAuditDaoImpl{}
+}
+
+func (r AdminAuditDaoImpl) Insert(ctx context.Context, audit model.AdminAudit) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "AdminAuditDao.Insert")
+	defer span.End()
+	defer metrics.ObserveDao("admin_audit", "insert", time.Now())
+	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionAudit).
+		InsertOne(ctx, audit)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("action", string(audit.Action)).Err(err).Msg("cannot insert admin audit")
+		return "", dto.ParseError(err)
+	}
+	return one.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+// FindLast returns the newest audits first, audits of all targets are returned when targetId is empty.
+func (r AdminAuditDaoImpl) FindLast(ctx context.Context, targetId string, limit int64) (audits []model.AdminAudit, err error) {
+	ctx, span := tracing.Start(ctx, "AdminAuditDao.FindLast")
+	defer span.End()
+	defer metrics.ObserveDao("admin_audit", "find_last", time.Now())
+	audits = []model.AdminAudit{}
+	filter := bson.M{}
+	if targetId != "" {
+		filter["target_id"] = targetId
+	}
+	opts := options.Find()
+	opts.SetSort(bson.M{"time": -1})
+	opts.SetLimit(limit)
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionAudit).
+		Find(ctx, filter, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot find admin audits")
+		return audits, dto.ParseError(err)
+	}
+	err = many.All(ctx, &audits)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode admin audits")
+	}
+	return audits, dto.ParseError(err)
+}
@@ -0,0 +1,99 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type ChatDao interface {
+	Insert(ctx context.Context, message model.ChatMessage) (id string, err error)
+	FindLast(ctx context.Context, gameId primitive.ObjectID, limit int64) (messages []model.ChatMessage, err error)
+	SetMute(ctx context.Context, gameId primitive.ObjectID, userId primitive.ObjectID, muted bool) error
+	IsMuted(ctx context.Context, gameId primitive.ObjectID, userId primitive.ObjectID) (muted bool, err error)
+}
+
+type ChatDaoImpl struct {
+}
+
+func NewChatDaoImpl() ChatDaoImpl {
+	return ChatDaoImpl{}
+}
+
+func (r ChatDaoImpl) Insert(ctx context.Context, message model.ChatMessage) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "ChatDao.Insert")
+	defer span.End()
+	defer metrics.ObserveDao("chat", "insert", time.Now())
+	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionChat).
+		InsertOne(ctx, message)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("gameId", message.GameId.Hex()).Err(err).Msg("cannot insert chat message")
+		return "", dto.ParseError(err)
+	}
+	return one.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+// FindLast returns the last messages of the game from the oldest to the newest.
+func (r ChatDaoImpl) FindLast(ctx context.Context, gameId primitive.ObjectID, limit int64) (messages []model.ChatMessage, err error) {
+	ctx, span := tracing.Start(ctx, "ChatDao.FindLast")
+	defer span.End()
+	defer metrics.ObserveDao("chat", "find_last", time.Now())
+	messages = []model.ChatMessage{}
+	opts := options.Find()
+	opts.SetSort(bson.M{"time": -1})
+	opts.SetLimit(limit)
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionChat).
+		Find(ctx, bson.M{"game_id": gameId}, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("gameId", gameId.Hex()).Err(err).Msg("cannot find chat messages")
+		return messages, dto.ParseError(err)
+	}
+	err = many.All(ctx, &messages)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("gameId", gameId.Hex()).Err(err).Msg("cannot decode chat messages")
+		return messages, dto.ParseError(err)
+	}
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+func (r ChatDaoImpl) SetMute(ctx context.Context, gameId primitive.ObjectID, userId primitive.ObjectID, muted bool) error {
+	ctx, span := tracing.Start(ctx, "ChatDao.SetMute")
+	defer span.End()
+	defer metrics.ObserveDao("chat", "set_mute", time.Now())
+	collection := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionChatMute)
+	filter := bson.M{"game_id": gameId, "user_id": userId}
+	var err error
+	if muted {
+		_, err = collection.UpdateOne(ctx, filter, bson.M{"$set": filter}, options.Update().SetUpsert(true))
+	} else {
+		_, err = collection.DeleteOne(ctx, filter)
+	}
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("gameId", gameId.Hex()).Str("userId", userId.Hex()).Err(err).Msg("cannot set chat mute")
+		return dto.ParseError(err)
+	}
+	return nil
+}
+
+func (r ChatDaoImpl) IsMuted(ctx context.Context, gameId primitive.ObjectID, userId primitive.ObjectID) (muted bool, err error) {
+	ctx, span := tracing.Start(ctx, "ChatDao.IsMuted")
+	defer span.End()
+	defer metrics.ObserveDao("chat", "is_muted", time.Now())
+	count, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionChatMute).
+		CountDocuments(ctx, bson.M{"game_id": gameId, "user_id": userId})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("gameId", gameId.Hex()).Str("userId", userId.Hex()).Err(err).Msg("cannot get chat mute")
+		return false, dto.ParseError(err)
+	}
+	return count > 0, nil
+}
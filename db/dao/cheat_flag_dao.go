@@ -0,0 +1,115 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type CheatFlagDao interface {
+	AddEvidence(ctx context.Context, userId primitive.ObjectID, otherUserId *primitive.ObjectID, kind model.CheatKind,
+		evidence model.CheatEvidence, maxEvidence int) error
+	GetOne(ctx context.Context, flagId string) (flag model.CheatFlag, err error)
+	UpdateIfPending(ctx context.Context, flag model.CheatFlag) (updated bool, err error)
+	FindByStatus(ctx context.Context, status model.ReportStatus, limit int64) (flags []model.CheatFlag, err error)
+}
+
+type CheatFlagDaoImpl struct {
+}
+
+func NewCheatFlagDaoImpl() CheatFlagDaoImpl {
+	return CheatFlagDaoImpl{}
+}
+
+// AddEvidence adds the evidence to the pending flag of the user and kind, the flag is created if there is none. Only
+// the last maxEvidence evidences are kept.
+func (r CheatFlagDaoImpl) AddEvidence(ctx context.Context, userId primitive.ObjectID, otherUserId *primitive.ObjectID,
+	kind model.CheatKind, evidence model.CheatEvidence, maxEvidence int) error {
+	ctx, span := tracing.Start(ctx, "CheatFlagDao.AddEvidence")
+	defer span.End()
+	defer metrics.ObserveDao("cheat_flag", "add_evidence", time.Now())
+	now := time.Now()
+	filter := bson.M{
+		"user_id":       userId,
+		"other_user_id": otherUserId,
+		"kind":          kind,
+		"status":        model.ReportPending,
+	}
+	update := bson.M{
+		"$setOnInsert": bson.M{"create_date": now},
+		"$set":         bson.M{"update_date": now},
+		"$push":        bson.M{"evidence": bson.M{"$each": bson.A{evidence}, "$slice": -maxEvidence}},
+	}
+	_, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionCheatFlag).
+		UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", userId.Hex()).Str("kind", string(kind)).Err(err).Msg("cannot add cheat evidence")
+	}
+	return dto.ParseError(err)
+}
+
+func (r CheatFlagDaoImpl) GetOne(ctx context.Context, flagId string) (flag model.CheatFlag, err error) {
+	ctx, span := tracing.Start(ctx, "CheatFlagDao.GetOne")
+	defer span.End()
+	defer metrics.ObserveDao("cheat_flag", "get_one", time.Now())
+	hex, err := primitive.ObjectIDFromHex(flagId)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("flagId", flagId).Err(err).Msg("cannot convert to objectId")
+		return flag, dto.ParseError(err)
+	}
+	err = mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionCheatFlag).
+		FindOne(ctx, bson.M{"_id": hex}).Decode(&flag)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("flagId", flagId).Err(err).Msg("cannot decode cheat flag")
+	}
+	return flag, dto.ParseError(err)
+}
+
+// UpdateIfPending saves the review of a flag only if it is still pending, so a flag is not reviewed twice. Evidence is
+// not saved, it may have been added since the flag was read.
+func (r CheatFlagDaoImpl) UpdateIfPending(ctx context.Context, flag model.CheatFlag) (updated bool, err error) {
+	ctx, span := tracing.Start(ctx, "CheatFlagDao.UpdateIfPending")
+	defer span.End()
+	defer metrics.ObserveDao("cheat_flag", "update_if_pending", time.Now())
+	result, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionCheatFlag).
+		UpdateOne(ctx, bson.M{"_id": flag.Id, "status": model.ReportPending}, bson.M{"$set": bson.M{
+			"status":      flag.Status,
+			"review_note": flag.ReviewNote,
+			"reviewed_by": flag.ReviewedBy,
+			"review_date": flag.ReviewDate,
+		}})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("flagId", flag.Id.Hex()).Err(err).Msg("cannot update cheat flag")
+		return false, dto.ParseError(err)
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// FindByStatus returns the flags of the status from the oldest, so the review queue is worked in order.
+func (r CheatFlagDaoImpl) FindByStatus(ctx context.Context, status model.ReportStatus, limit int64) (flags []model.CheatFlag, err error) {
+	ctx, span := tracing.Start(ctx, "CheatFlagDao.FindByStatus")
+	defer span.End()
+	defer metrics.ObserveDao("cheat_flag", "find_by_status", time.Now())
+	flags = []model.CheatFlag{}
+	opts := options.Find()
+	opts.SetSort(bson.M{"create_date": 1})
+	opts.SetLimit(limit)
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionCheatFlag).
+		Find(ctx, bson.M{"status": status}, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot find cheat flags")
+		return flags, dto.ParseError(err)
+	}
+	err = many.All(ctx, &flags)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode cheat flags")
+	}
+	return flags, dto.ParseError(err)
+}
@@ -3,17 +3,46 @@ package dao
 import (
 	"battleship/db/mongodb"
 	"battleship/dto"
+	"battleship/metrics"
 	"battleship/model"
+	"battleship/tracing"
 	"context"
-	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
 )
 
 type GameDao interface {
-	Insert(game model.Game) (id string, err error)
-	GetOne(gameId string) (game model.Game, err error)
-	Update(game model.Game) error
+	Insert(ctx context.Context, game model.Game) (id string, err error)
+	GetOne(ctx context.Context, gameId string) (game model.Game, err error)
+	Update(ctx context.Context, game model.Game) error
+	FindByUser(ctx context.Context, filter GameFilter, limit int64) (games []model.Game, err error)
+	FindOpen(ctx context.Context, limit int64) (games []model.Game, err error)
+	FindExpired(ctx context.Context, initBefore time.Time, joinedBefore time.Time, limit int64) (games []model.Game, err error)
+	UpdateIfStatus(ctx context.Context, game model.Game, status model.GameStatus) (updated bool, err error)
+	FindPauseExpired(ctx context.Context, before time.Time, limit int64) (games []model.Game, err error)
+	FindLive(ctx context.Context, limit int64) (games []model.Game, err error)
+	FindCheatUnchecked(ctx context.Context, limit int64) (games []model.Game, err error)
+	ClaimCheatCheck(ctx context.Context, gameId primitive.ObjectID) (claimed bool, err error)
+	AddPasscodeFailure(ctx context.Context, gameId primitive.ObjectID) (failures int, err error)
+}
+
+const (
+	WinResult  = "win"
+	LossResult = "loss"
+)
+
+// GameFilter selects games of UserId, nil fields are not filtered. Before is the cursor, only games with smaller id
+// are returned.
+type GameFilter struct {
+	UserId     primitive.ObjectID
+	OpponentId *primitive.ObjectID
+	Status     *model.GameStatus
+	Result     string
+	From       *time.Time
+	To         *time.Time
+	Before     *primitive.ObjectID
 }
 
 type GameDaoImpl struct {
@@ -23,38 +52,288 @@ func NewGameDaoImpl() GameDaoImpl {
 	return GameDaoImpl{}
 }
 
-func (r GameDaoImpl) Insert(game model.Game) (id string, err error) {
-	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).InsertOne(context.TODO(), game)
+func (r GameDaoImpl) Insert(ctx context.Context, game model.Game) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "GameDao.Insert")
+	defer span.End()
+	defer metrics.ObserveDao("game", "insert", time.Now())
+	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).InsertOne(ctx, game)
 	if err != nil {
-		log.Warn().Str("gameId", game.Id.Hex()).Err(err).Msg("cannot insert Game")
+		tracing.Log(ctx).Warn().Str("gameId", game.Id.Hex()).Err(err).Msg("cannot insert Game")
 		return "", dto.ParseError(err)
 	}
 	return one.InsertedID.(primitive.ObjectID).Hex(), nil
 }
 
-func (r GameDaoImpl) Update(game model.Game) error {
+func (r GameDaoImpl) Update(ctx context.Context, game model.Game) error {
+	ctx, span := tracing.Start(ctx, "GameDao.Update")
+	defer span.End()
+	defer metrics.ObserveDao("game", "update", time.Now())
 	res, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).
-		UpdateOne(context.TODO(), bson.M{"_id": game.Id}, bson.D{{"$set", game}})
+		UpdateOne(ctx, bson.M{"_id": game.Id}, gameUpdate(game))
 	if err != nil {
-		log.Warn().Str("gameId", game.Id.Hex()).Err(err).Msg("cannot update Game")
+		tracing.Log(ctx).Warn().Str("gameId", game.Id.Hex()).Err(err).Msg("cannot update Game")
 		return dto.ParseError(err)
 	}
-	log.Debug().Int64("matched_count", res.MatchedCount).Int64("modified_count", res.ModifiedCount).
+	tracing.Log(ctx).Debug().Int64("matched_count", res.MatchedCount).Int64("modified_count", res.ModifiedCount).
 		Str("game_id", game.Id.Hex()).Msg("")
 	return nil
 }
 
-func (r GameDaoImpl) GetOne(gameId string) (game model.Game, err error) {
+func (r GameDaoImpl) GetOne(ctx context.Context, gameId string) (game model.Game, err error) {
+	ctx, span := tracing.Start(ctx, "GameDao.GetOne")
+	defer span.End()
+	defer metrics.ObserveDao("game", "get_one", time.Now())
 	hex, err := primitive.ObjectIDFromHex(gameId)
 	if err != nil {
-		log.Warn().Str("gameId", gameId).Err(err).Msg("cannot convert to objectId")
+		tracing.Log(ctx).Warn().Str("gameId", gameId).Err(err).Msg("cannot convert to objectId")
 		return game, dto.ParseError(err)
 	}
 	filter := bson.D{{"_id", hex}}
-	one := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).FindOne(context.TODO(), filter)
+	one := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).FindOne(ctx, filter)
 	err = one.Decode(&game)
 	if err != nil {
-		log.Warn().Str("gameId", gameId).Err(err).Msg("cannot decode Game")
+		tracing.Log(ctx).Warn().Str("gameId", gameId).Err(err).Msg("cannot decode Game")
 	}
 	return game, dto.ParseError(err)
 }
+
+// FindByUser returns games of the filter sorted from the newest.
+func (r GameDaoImpl) FindByUser(ctx context.Context, filter GameFilter, limit int64) (games []model.Game, err error) {
+	ctx, span := tracing.Start(ctx, "GameDao.FindByUser")
+	defer span.End()
+	defer metrics.ObserveDao("game", "find_by_user", time.Now())
+	games = []model.Game{}
+	query := bson.M{}
+	if filter.OpponentId != nil {
+		query["$or"] = bson.A{
+			bson.M{"side_1_user": filter.UserId, "side_2_user": *filter.OpponentId},
+			bson.M{"side_1_user": *filter.OpponentId, "side_2_user": filter.UserId},
+		}
+	} else {
+		query["$or"] = bson.A{
+			bson.M{"side_1_user": filter.UserId},
+			bson.M{"side_2_user": filter.UserId},
+		}
+	}
+	if filter.Status != nil {
+		query["status"] = *filter.Status
+	}
+	switch filter.Result {
+	case WinResult:
+		query["winner_user"] = filter.UserId
+	case LossResult:
+		query["winner_user"] = bson.M{"$nin": bson.A{filter.UserId, nil}}
+	}
+	if filter.From != nil || filter.To != nil {
+		date := bson.M{}
+		if filter.From != nil {
+			date["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			date["$lt"] = *filter.To
+		}
+		query["create_date"] = date
+	}
+	if filter.Before != nil {
+		query["_id"] = bson.M{"$lt": *filter.Before}
+	}
+
+	opts := options.Find()
+	opts.SetSort(bson.M{"_id": -1})
+	opts.SetLimit(limit)
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).
+		Find(ctx, query, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", filter.UserId.Hex()).Err(err).Msg("cannot find games")
+		return games, dto.ParseError(err)
+	}
+	err = many.All(ctx, &games)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", filter.UserId.Hex()).Err(err).Msg("cannot decode games")
+	}
+	return games, dto.ParseError(err)
+}
+
+// FindOpen returns public games that are waiting for the second side, sorted from the newest.
+func (r GameDaoImpl) FindOpen(ctx context.Context, limit int64) (games []model.Game, err error) {
+	ctx, span := tracing.Start(ctx, "GameDao.FindOpen")
+	defer span.End()
+	defer metrics.ObserveDao("game", "find_open", time.Now())
+	games = []model.Game{}
+	opts := options.Find()
+	opts.SetSort(bson.M{"create_date": -1})
+	opts.SetLimit(limit)
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).
+		Find(ctx, bson.M{"status": model.Init, "public": true}, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot find open games")
+		return games, dto.ParseError(err)
+	}
+	err = many.All(ctx, &games)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode open games")
+	}
+	return games, dto.ParseError(err)
+}
+
+// FindExpired returns games created before initBefore that nobody joined, and joined games that are not started
+// since joinedBefore.
+func (r GameDaoImpl) FindExpired(ctx context.Context, initBefore time.Time, joinedBefore time.Time, limit int64) (games []model.Game, err error) {
+	ctx, span := tracing.Start(ctx, "GameDao.FindExpired")
+	defer span.End()
+	defer metrics.ObserveDao("game", "find_expired", time.Now())
+	games = []model.Game{}
+	query := bson.M{"$or": bson.A{
+		bson.M{"status": model.Init, "create_date": bson.M{"$lt": initBefore}},
+		bson.M{"status": model.Joined, "last_move_time": bson.M{"$lt": joinedBefore}},
+	}}
+	opts := options.Find()
+	opts.SetLimit(limit)
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).
+		Find(ctx, query, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot find expired games")
+		return games, dto.ParseError(err)
+	}
+	err = many.All(ctx, &games)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode expired games")
+	}
+	return games, dto.ParseError(err)
+}
+
+// gameUpdate sets the fields of the game. The pause fields are omitted from $set when they are nil, so they are
+// unset in the same update, otherwise a pause request or deadline of a finished pause would be kept.
+func gameUpdate(game model.Game) bson.M {
+	// passcode failures are only counted by AddPasscodeFailure, a stale count must not overwrite them
+	game.PasscodeFails = 0
+	update := bson.M{"$set": game}
+	unset := bson.M{}
+	if game.PauseBy == nil {
+		unset["pause_requested_by"] = ""
+	}
+	if game.ResumeBy == nil {
+		unset["resume_requested_by"] = ""
+	}
+	if game.PausedAt == nil {
+		unset["paused_at"] = ""
+	}
+	if game.PauseDeadline == nil {
+		unset["pause_deadline"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update
+}
+
+// UpdateIfStatus updates the game only if its stored status is still status, so concurrent changes are not lost.
+func (r GameDaoImpl) UpdateIfStatus(ctx context.Context, game model.Game, status model.GameStatus) (updated bool, err error) {
+	ctx, span := tracing.Start(ctx, "GameDao.UpdateIfStatus")
+	defer span.End()
+	defer metrics.ObserveDao("game", "update_if_status", time.Now())
+	res, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).
+		UpdateOne(ctx, bson.M{"_id": game.Id, "status": status}, gameUpdate(game))
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("gameId", game.Id.Hex()).Err(err).Msg("cannot update Game")
+		return false, dto.ParseError(err)
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// FindPauseExpired returns paused games whose pause deadline is before the given time.
+func (r GameDaoImpl) FindPauseExpired(ctx context.Context, before time.Time, limit int64) (games []model.Game, err error) {
+	ctx, span := tracing.Start(ctx, "GameDao.FindPauseExpired")
+	defer span.End()
+	defer metrics.ObserveDao("game", "find_pause_expired", time.Now())
+	games = []model.Game{}
+	opts := options.Find()
+	opts.SetLimit(limit)
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).
+		Find(ctx, bson.M{"status": model.Paused, "pause_deadline": bson.M{"$lt": before}}, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot find pause expired games")
+		return games, dto.ParseError(err)
+	}
+	err = many.All(ctx, &games)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode pause expired games")
+	}
+	return games, dto.ParseError(err)
+}
+
+// FindLive returns joined, started and paused games, sorted from the latest move.
+func (r GameDaoImpl) FindLive(ctx context.Context, limit int64) (games []model.Game, err error) {
+	ctx, span := tracing.Start(ctx, "GameDao.FindLive")
+	defer span.End()
+	defer metrics.ObserveDao("game", "find_live", time.Now())
+	games = []model.Game{}
+	opts := options.Find()
+	opts.SetSort(bson.M{"last_move_time": -1})
+	opts.SetLimit(limit)
+	filter := bson.M{"status": bson.M{"$in": []model.GameStatus{model.Joined, model.Start, model.Paused}}}
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).
+		Find(ctx, filter, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot find live games")
+		return games, dto.ParseError(err)
+	}
+	err = many.All(ctx, &games)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode live games")
+	}
+	return games, dto.ParseError(err)
+}
+
+// FindCheatUnchecked returns finished games that the anti-cheat analysis has not checked, from the oldest.
+func (r GameDaoImpl) FindCheatUnchecked(ctx context.Context, limit int64) (games []model.Game, err error) {
+	ctx, span := tracing.Start(ctx, "GameDao.FindCheatUnchecked")
+	defer span.End()
+	defer metrics.ObserveDao("game", "find_cheat_unchecked", time.Now())
+	games = []model.Game{}
+	opts := options.Find()
+	opts.SetSort(bson.M{"end_date": 1})
+	opts.SetLimit(limit)
+	filter := bson.M{"status": model.Finished, "cheat_checked": bson.M{"$ne": true}}
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).
+		Find(ctx, filter, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot find cheat unchecked games")
+		return games, dto.ParseError(err)
+	}
+	err = many.All(ctx, &games)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode cheat unchecked games")
+	}
+	return games, dto.ParseError(err)
+}
+
+// ClaimCheatCheck marks the game as checked, claimed is false when another instance has already marked it.
+func (r GameDaoImpl) ClaimCheatCheck(ctx context.Context, gameId primitive.ObjectID) (claimed bool, err error) {
+	ctx, span := tracing.Start(ctx, "GameDao.ClaimCheatCheck")
+	defer span.End()
+	defer metrics.ObserveDao("game", "claim_cheat_check", time.Now())
+	result, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).
+		UpdateOne(ctx, bson.M{"_id": gameId, "cheat_checked": bson.M{"$ne": true}}, bson.M{"$set": bson.M{"cheat_checked": true}})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("gameId", gameId.Hex()).Err(err).Msg("cannot claim cheat check")
+		return false, dto.ParseError(err)
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// AddPasscodeFailure counts a wrong passcode of the game in one update and returns the failures so far.
+func (r GameDaoImpl) AddPasscodeFailure(ctx context.Context, gameId primitive.ObjectID) (failures int, err error) {
+	ctx, span := tracing.Start(ctx, "GameDao.AddPasscodeFailure")
+	defer span.End()
+	defer metrics.ObserveDao("game", "add_passcode_failure", time.Now())
+	game := model.Game{}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGame).
+		FindOneAndUpdate(ctx, bson.M{"_id": gameId}, bson.M{"$inc": bson.M{"passcode_failures": 1}}, opts).Decode(&game)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("gameId", gameId.Hex()).Err(err).Msg("cannot add passcode failure")
+		return 0, dto.ParseError(err)
+	}
+	return game.PasscodeFails, nil
+}
@@ -0,0 +1,99 @@
+package dao
+
+import (
+	"battleship/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+	"time"
+)
+
+// applyUpdate applies the $set and $unset of update to stored like mongodb does and returns the stored game.
+func applyUpdate(t *testing.T, stored bson.M, update bson.M) model.Game {
+	t.Helper()
+	raw, err := bson.Marshal(update["$set"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := bson.M{}
+	if err := bson.Unmarshal(raw, &set); err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range set {
+		stored[key] = value
+	}
+	if unset, ok := update["$unset"].(bson.M); ok {
+		for key := range unset {
+			delete(stored, key)
+		}
+	}
+	raw, err = bson.Marshal(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	game := model.Game{}
+	if err := bson.Unmarshal(raw, &game); err != nil {
+		t.Fatal(err)
+	}
+	return game
+}
+
+func TestGameUpdatePauseResumePause(t *testing.T) {
+	side1, side2 := primitive.NewObjectID(), primitive.NewObjectID()
+	now := time.Now()
+	stored := bson.M{}
+	game := applyUpdate(t, stored, gameUpdate(model.Game{
+		Id:        primitive.NewObjectID(),
+		Status:    model.Start,
+		Side1User: &side1,
+		Side2User: &side2,
+	}))
+
+	// side 1 requests, side 2 accepts
+	game.PauseBy = &side1
+	game = applyUpdate(t, stored, gameUpdate(game))
+	game.Pause(now, now.Add(time.Minute))
+	game = applyUpdate(t, stored, gameUpdate(game))
+	if game.Status != model.Paused || game.PauseBy != nil || game.PauseDeadline == nil {
+		t.Fatalf("game is not paused: %+v", game)
+	}
+
+	// side 1 asks to resume, side 2 resumes
+	game.ResumeBy = &side1
+	game = applyUpdate(t, stored, gameUpdate(game))
+	game.Resume(now.Add(time.Second))
+	game = applyUpdate(t, stored, gameUpdate(game))
+	if game.Status != model.Start {
+		t.Fatalf("game is not resumed: %+v", game)
+	}
+	if game.PauseBy != nil || game.ResumeBy != nil || game.PausedAt != nil || game.PauseDeadline != nil {
+		t.Fatalf("pause fields are kept after resume: %+v", game)
+	}
+
+	// side 2 requests the next pause, the old requests must not count
+	game.PauseBy = &side2
+	game = applyUpdate(t, stored, gameUpdate(game))
+	if game.PauseBy == nil || *game.PauseBy != side2 || game.ResumeBy != nil {
+		t.Fatalf("stale pause fields after second pause request: %+v", game)
+	}
+	game.Pause(now.Add(2*time.Second), now.Add(time.Minute))
+	game = applyUpdate(t, stored, gameUpdate(game))
+	if game.Status != model.Paused || game.PauseBy != nil || game.ResumeBy != nil {
+		t.Fatalf("stale pause fields after second pause: %+v", game)
+	}
+}
+
+func TestGameUpdateKeepsPasscodeFailures(t *testing.T) {
+	side1 := primitive.NewObjectID()
+	stored := bson.M{"passcode_failures": 3}
+	game := applyUpdate(t, stored, gameUpdate(model.Game{
+		Id:            primitive.NewObjectID(),
+		Status:        model.Init,
+		Side1User:     &side1,
+		PasscodeHash:  "salt:hash",
+		PasscodeFails: 1,
+	}))
+	if game.PasscodeFails != 3 {
+		t.Fatalf("stale passcode failures overwrote the stored ones, got %d", game.PasscodeFails)
+	}
+}
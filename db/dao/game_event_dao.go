@@ -3,19 +3,21 @@ package dao
 import (
 	"battleship/db/mongodb"
 	"battleship/dto"
+	"battleship/metrics"
 	"battleship/model"
+	"battleship/tracing"
 	"context"
-	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
 )
 
 type GameEventDao interface {
-	Insert(event model.GameEvent) (id string, err error)
-	FindMany(gameId string) (events []model.GameEvent, err error)
-	FindManyByType(gameId string, eventType model.GameEventType) (events []model.GameEvent, err error)
-	GetLast(GameId string) (event model.GameEvent, err error)
+	Insert(ctx context.Context, event model.GameEvent) (id string, err error)
+	FindMany(ctx context.Context, gameId string) (events []model.GameEvent, err error)
+	FindManyByType(ctx context.Context, gameId string, eventType model.GameEventType) (events []model.GameEvent, err error)
+	GetLast(ctx context.Context, GameId string) (event model.GameEvent, err error)
 }
 
 type GameEventDaoImpl struct {
@@ -25,57 +27,69 @@ func NewEventGameDaoImpl() GameEventDaoImpl {
 	return GameEventDaoImpl{}
 }
 
-func (r GameEventDaoImpl) Insert(event model.GameEvent) (id string, err error) {
-	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGameEvent).InsertOne(context.TODO(), event)
+func (r GameEventDaoImpl) Insert(ctx context.Context, event model.GameEvent) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "GameEventDao.Insert")
+	defer span.End()
+	defer metrics.ObserveDao("game_event", "insert", time.Now())
+	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGameEvent).InsertOne(ctx, event)
 	if err != nil {
-		log.Warn().Str("event_type", string(event.Type)).Err(err).Msg("cannot insert Game")
+		tracing.Log(ctx).Warn().Str("event_type", string(event.Type)).Err(err).Msg("cannot insert Game")
 		return "", dto.ParseError(err)
 	}
 	return one.InsertedID.(primitive.ObjectID).Hex(), nil
 }
 
-func (r GameEventDaoImpl) FindMany(gameId string) (events []model.GameEvent, err error) {
+func (r GameEventDaoImpl) FindMany(ctx context.Context, gameId string) (events []model.GameEvent, err error) {
+	ctx, span := tracing.Start(ctx, "GameEventDao.FindMany")
+	defer span.End()
+	defer metrics.ObserveDao("game_event", "find_many", time.Now())
 	events = []model.GameEvent{}
 	hex, err := primitive.ObjectIDFromHex(gameId)
 	if err != nil {
-		log.Warn().Str("gameId", gameId).Err(err).Msg("cannot convert to objectId")
+		tracing.Log(ctx).Warn().Str("gameId", gameId).Err(err).Msg("cannot convert to objectId")
 		return events, dto.ParseError(err)
 	}
 	filter := bson.D{{"game_id", hex}}
 	opts := options.Find()
 	opts.SetSort(bson.D{{"time", -1}})
-	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGameEvent).Find(context.TODO(), filter, opts)
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGameEvent).Find(ctx, filter, opts)
 	if err != nil {
-		log.Warn().Str("gameId", gameId).Err(err).Msg("cannot find game events")
+		tracing.Log(ctx).Warn().Str("gameId", gameId).Err(err).Msg("cannot find game events")
 	}
-	err = many.All(context.TODO(), &events)
+	err = many.All(ctx, &events)
 	if err != nil {
-		log.Warn().Str("gameId", gameId).Err(err).Msg("cannot decode Game events")
+		tracing.Log(ctx).Warn().Str("gameId", gameId).Err(err).Msg("cannot decode Game events")
 	}
 	return events, dto.ParseError(err)
 }
 
-func (r GameEventDaoImpl) FindManyByType(gameId string, eventType model.GameEventType) (events []model.GameEvent, err error) {
+func (r GameEventDaoImpl) FindManyByType(ctx context.Context, gameId string, eventType model.GameEventType) (events []model.GameEvent, err error) {
+	ctx, span := tracing.Start(ctx, "GameEventDao.FindManyByType")
+	defer span.End()
+	defer metrics.ObserveDao("game_event", "find_many_by_type", time.Now())
 	events = []model.GameEvent{}
 	hex, err := primitive.ObjectIDFromHex(gameId)
 	if err != nil {
-		log.Warn().Str("gameId", gameId).Err(err).Msg("cannot convert to objectId")
+		tracing.Log(ctx).Warn().Str("gameId", gameId).Err(err).Msg("cannot convert to objectId")
 		return events, dto.ParseError(err)
 	}
 	filter := bson.D{{"game_id", hex}, {"type", eventType}}
 	opts := options.Find()
 	opts.SetSort(bson.D{{"time", -1}})
-	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGameEvent).Find(context.TODO(), filter, opts)
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionGameEvent).Find(ctx, filter, opts)
 	if err != nil {
-		log.Warn().Str("gameId", gameId).Err(err).Msg("cannot find game events")
+		tracing.Log(ctx).Warn().Str("gameId", gameId).Err(err).Msg("cannot find game events")
 	}
-	err = many.All(context.TODO(), &events)
+	err = many.All(ctx, &events)
 	if err != nil {
-		log.Warn().Str("gameId", gameId).Err(err).Msg("cannot decode Game events")
+		tracing.Log(ctx).Warn().Str("gameId", gameId).Err(err).Msg("cannot decode Game events")
 	}
 	return events, dto.ParseError(err)
 }
 
-func (r GameEventDaoImpl) GetLast(GameId string) (event model.GameEvent, err error) {
+func (r GameEventDaoImpl) GetLast(ctx context.Context, GameId string) (event model.GameEvent, err error) {
+	ctx, span := tracing.Start(ctx, "GameEventDao.GetLast")
+	defer span.End()
+	defer metrics.ObserveDao("game_event", "get_last", time.Now())
 	return model.GameEvent{}, nil
 }
@@ -0,0 +1,66 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type InviteDao interface {
+	Insert(ctx context.Context, invite model.Invite) (id string, err error)
+	GetByCode(ctx context.Context, code string) (invite model.Invite, err error)
+	Revoke(ctx context.Context, code string, creatorId primitive.ObjectID) (revoked bool, err error)
+}
+
+type InviteDaoImpl struct {
+}
+
+func NewInviteDaoImpl() InviteDaoImpl {
+	return InviteDaoImpl{}
+}
+
+func (r InviteDaoImpl) Insert(ctx context.Context, invite model.Invite) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "InviteDao.Insert")
+	defer span.End()
+	defer metrics.ObserveDao("invite", "insert", time.Now())
+	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionInvite).
+		InsertOne(ctx, invite)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("gameId", invite.GameId.Hex()).Err(err).Msg("cannot insert invite")
+		return "", dto.ParseError(err)
+	}
+	return one.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r InviteDaoImpl) GetByCode(ctx context.Context, code string) (invite model.Invite, err error) {
+	ctx, span := tracing.Start(ctx, "InviteDao.GetByCode")
+	defer span.End()
+	defer metrics.ObserveDao("invite", "get_by_code", time.Now())
+	one := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionInvite).
+		FindOne(ctx, bson.M{"code": code})
+	err = one.Decode(&invite)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("code", code).Err(err).Msg("cannot decode invite")
+	}
+	return invite, dto.ParseError(err)
+}
+
+// Revoke marks the invite as revoked, only the creator of the invite can revoke it.
+func (r InviteDaoImpl) Revoke(ctx context.Context, code string, creatorId primitive.ObjectID) (revoked bool, err error) {
+	ctx, span := tracing.Start(ctx, "InviteDao.Revoke")
+	defer span.End()
+	defer metrics.ObserveDao("invite", "revoke", time.Now())
+	res, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionInvite).
+		UpdateOne(ctx, bson.M{"code": code, "creator_id": creatorId}, bson.M{"$set": bson.M{"revoked": true}})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("code", code).Err(err).Msg("cannot revoke invite")
+		return false, dto.ParseError(err)
+	}
+	return res.MatchedCount > 0, nil
+}
@@ -0,0 +1,128 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type LeaderboardDao interface {
+	AddResult(ctx context.Context, board model.LeaderboardType, period string, userId primitive.ObjectID, won bool, date time.Time) error
+	FindPage(ctx context.Context, board model.LeaderboardType, period string, skip int64, limit int64) (entries []model.LeaderboardEntry, err error)
+	GetEntry(ctx context.Context, board model.LeaderboardType, period string, userId string) (entry model.LeaderboardEntry, err error)
+	CountAhead(ctx context.Context, entry model.LeaderboardEntry) (count int64, err error)
+	Count(ctx context.Context, board model.LeaderboardType, period string) (count int64, err error)
+}
+
+type LeaderboardDaoImpl struct {
+}
+
+func NewLeaderboardDaoImpl() LeaderboardDaoImpl {
+	return LeaderboardDaoImpl{}
+}
+
+var leaderboardSort = bson.D{{Key: "wins", Value: -1}, {Key: "losses", Value: 1}, {Key: "user_id", Value: 1}}
+
+func (r LeaderboardDaoImpl) AddResult(ctx context.Context, board model.LeaderboardType, period string, userId primitive.ObjectID, won bool,
+	date time.Time) error {
+	ctx, span := tracing.Start(ctx, "LeaderboardDao.AddResult")
+	defer span.End()
+	defer metrics.ObserveDao("leaderboard", "add_result", time.Now())
+	field := "losses"
+	if won {
+		field = "wins"
+	}
+	filter := bson.M{"board": board, "period": period, "user_id": userId}
+	update := bson.M{
+		"$inc": bson.M{field: 1},
+		"$max": bson.M{"last_game_date": date},
+	}
+	_, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionBoard).
+		UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("board", string(board)).Str("period", period).Str("userId", userId.Hex()).Err(err).
+			Msg("cannot update leaderboard")
+		return dto.ParseError(err)
+	}
+	return nil
+}
+
+func (r LeaderboardDaoImpl) FindPage(ctx context.Context, board model.LeaderboardType, period string, skip int64, limit int64) (entries []model.LeaderboardEntry, err error) {
+	ctx, span := tracing.Start(ctx, "LeaderboardDao.FindPage")
+	defer span.End()
+	defer metrics.ObserveDao("leaderboard", "find_page", time.Now())
+	entries = []model.LeaderboardEntry{}
+	opts := options.Find()
+	opts.SetSort(leaderboardSort)
+	opts.SetSkip(skip)
+	opts.SetLimit(limit)
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionBoard).
+		Find(ctx, bson.M{"board": board, "period": period}, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("board", string(board)).Str("period", period).Err(err).Msg("cannot find leaderboard")
+		return entries, dto.ParseError(err)
+	}
+	err = many.All(ctx, &entries)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("board", string(board)).Str("period", period).Err(err).Msg("cannot decode leaderboard")
+	}
+	return entries, dto.ParseError(err)
+}
+
+func (r LeaderboardDaoImpl) GetEntry(ctx context.Context, board model.LeaderboardType, period string, userId string) (entry model.LeaderboardEntry, err error) {
+	ctx, span := tracing.Start(ctx, "LeaderboardDao.GetEntry")
+	defer span.End()
+	defer metrics.ObserveDao("leaderboard", "get_entry", time.Now())
+	hex, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", userId).Err(err).Msg("cannot convert to objectId")
+		return entry, dto.ParseError(err)
+	}
+	one := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionBoard).
+		FindOne(ctx, bson.M{"board": board, "period": period, "user_id": hex})
+	err = one.Decode(&entry)
+	if err != nil {
+		tracing.Log(ctx).Debug().Str("userId", userId).Err(err).Msg("cannot decode leaderboard entry")
+	}
+	return entry, dto.ParseError(err)
+}
+
+// CountAhead counts entries of the same board that rank strictly higher than entry.
+func (r LeaderboardDaoImpl) CountAhead(ctx context.Context, entry model.LeaderboardEntry) (count int64, err error) {
+	ctx, span := tracing.Start(ctx, "LeaderboardDao.CountAhead")
+	defer span.End()
+	defer metrics.ObserveDao("leaderboard", "count_ahead", time.Now())
+	filter := bson.M{
+		"board":  entry.Board,
+		"period": entry.Period,
+		"$or": bson.A{
+			bson.M{"wins": bson.M{"$gt": entry.Wins}},
+			bson.M{"wins": entry.Wins, "losses": bson.M{"$lt": entry.Losses}},
+		},
+	}
+	count, err = mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionBoard).
+		CountDocuments(ctx, filter)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("board", string(entry.Board)).Str("period", entry.Period).Err(err).Msg("cannot count leaderboard")
+	}
+	return count, dto.ParseError(err)
+}
+
+func (r LeaderboardDaoImpl) Count(ctx context.Context, board model.LeaderboardType, period string) (count int64, err error) {
+	ctx, span := tracing.Start(ctx, "LeaderboardDao.Count")
+	defer span.End()
+	defer metrics.ObserveDao("leaderboard", "count", time.Now())
+	count, err = mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionBoard).
+		CountDocuments(ctx, bson.M{"board": board, "period": period})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("board", string(board)).Str("period", period).Err(err).Msg("cannot count leaderboard")
+	}
+	return count, dto.ParseError(err)
+}
@@ -0,0 +1,111 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type OtpDao interface {
+	Insert(ctx context.Context, otp model.Otp) (id string, err error)
+	GetLast(ctx context.Context, mobile string) (otp model.Otp, err error)
+	CountSince(ctx context.Context, mobile string, since time.Time) (count int64, err error)
+	Delete(ctx context.Context, otpId primitive.ObjectID) error
+	UseAttempt(ctx context.Context, otpId primitive.ObjectID, maxAttempts int) (otp model.Otp, err error)
+	MarkVerified(ctx context.Context, otpId primitive.ObjectID) (updated bool, err error)
+}
+
+type OtpDaoImpl struct {
+}
+
+func NewOtpDaoImpl() OtpDaoImpl {
+	return OtpDaoImpl{}
+}
+
+func (r OtpDaoImpl) Insert(ctx context.Context, otp model.Otp) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "OtpDao.Insert")
+	defer span.End()
+	defer metrics.ObserveDao("otp", "insert", time.Now())
+	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionOtp).InsertOne(ctx, otp)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("mobile", otp.Mobile).Err(err).Msg("cannot insert otp")
+		return "", dto.ParseError(err)
+	}
+	return one.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r OtpDaoImpl) GetLast(ctx context.Context, mobile string) (otp model.Otp, err error) {
+	ctx, span := tracing.Start(ctx, "OtpDao.GetLast")
+	defer span.End()
+	defer metrics.ObserveDao("otp", "get_last", time.Now())
+	opts := options.FindOne()
+	opts.SetSort(bson.M{"create_date": -1})
+	one := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionOtp).
+		FindOne(ctx, bson.M{"mobile": mobile}, opts)
+	err = one.Decode(&otp)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("mobile", mobile).Err(err).Msg("cannot decode otp")
+	}
+	return otp, dto.ParseError(err)
+}
+
+func (r OtpDaoImpl) CountSince(ctx context.Context, mobile string, since time.Time) (count int64, err error) {
+	ctx, span := tracing.Start(ctx, "OtpDao.CountSince")
+	defer span.End()
+	defer metrics.ObserveDao("otp", "count_since", time.Now())
+	count, err = mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionOtp).
+		CountDocuments(ctx, bson.M{"mobile": mobile, "create_date": bson.M{"$gte": since}})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("mobile", mobile).Err(err).Msg("cannot count otp")
+	}
+	return count, dto.ParseError(err)
+}
+
+func (r OtpDaoImpl) Delete(ctx context.Context, otpId primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "OtpDao.Delete")
+	defer span.End()
+	defer metrics.ObserveDao("otp", "delete", time.Now())
+	_, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionOtp).
+		DeleteOne(ctx, bson.M{"_id": otpId})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("otpId", otpId.Hex()).Err(err).Msg("cannot delete otp")
+	}
+	return dto.ParseError(err)
+}
+
+// UseAttempt counts an attempt on the otp in one update and returns it, so parallel attempts cannot go over
+// maxAttempts. mongo.ErrNoDocuments is returned when the otp is verified or has no attempt left.
+func (r OtpDaoImpl) UseAttempt(ctx context.Context, otpId primitive.ObjectID, maxAttempts int) (otp model.Otp, err error) {
+	ctx, span := tracing.Start(ctx, "OtpDao.UseAttempt")
+	defer span.End()
+	defer metrics.ObserveDao("otp", "use_attempt", time.Now())
+	filter := bson.M{"_id": otpId, "verified": false, "attempts": bson.M{"$lt": maxAttempts}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionOtp).
+		FindOneAndUpdate(ctx, filter, bson.M{"$inc": bson.M{"attempts": 1}}, opts).Decode(&otp)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("otpId", otpId.Hex()).Err(err).Msg("cannot use otp attempt")
+	}
+	return otp, dto.ParseError(err)
+}
+
+// MarkVerified marks the otp verified only if it is not yet, so a code logs in once.
+func (r OtpDaoImpl) MarkVerified(ctx context.Context, otpId primitive.ObjectID) (updated bool, err error) {
+	ctx, span := tracing.Start(ctx, "OtpDao.MarkVerified")
+	defer span.End()
+	defer metrics.ObserveDao("otp", "mark_verified", time.Now())
+	result, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionOtp).
+		UpdateOne(ctx, bson.M{"_id": otpId, "verified": false}, bson.M{"$set": bson.M{"verified": true}})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("otpId", otpId.Hex()).Err(err).Msg("cannot mark otp verified")
+		return false, dto.ParseError(err)
+	}
+	return result.MatchedCount == 1, nil
+}
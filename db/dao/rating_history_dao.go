@@ -0,0 +1,64 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type RatingHistoryDao interface {
+	Insert(ctx context.Context, history model.RatingHistory) (id string, err error)
+	FindByUser(ctx context.Context, userId string, limit int64) (histories []model.RatingHistory, err error)
+}
+
+type RatingHistoryDaoImpl struct {
+}
+
+func NewRatingHistoryDaoImpl() RatingHistoryDaoImpl {
+	return RatingHistoryDaoImpl{}
+}
+
+func (r RatingHistoryDaoImpl) Insert(ctx context.Context, history model.RatingHistory) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "RatingHistoryDao.Insert")
+	defer span.End()
+	defer metrics.ObserveDao("rating_history", "insert", time.Now())
+	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionRating).InsertOne(ctx, history)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", history.UserId.Hex()).Err(err).Msg("cannot insert rating history")
+		return "", dto.ParseError(err)
+	}
+	return one.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r RatingHistoryDaoImpl) FindByUser(ctx context.Context, userId string, limit int64) (histories []model.RatingHistory, err error) {
+	ctx, span := tracing.Start(ctx, "RatingHistoryDao.FindByUser")
+	defer span.End()
+	defer metrics.ObserveDao("rating_history", "find_by_user", time.Now())
+	histories = []model.RatingHistory{}
+	hex, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", userId).Err(err).Msg("cannot convert to objectId")
+		return histories, dto.ParseError(err)
+	}
+	opts := options.Find()
+	opts.SetSort(bson.M{"time": -1})
+	opts.SetLimit(limit)
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionRating).
+		Find(ctx, bson.M{"user_id": hex}, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", userId).Err(err).Msg("cannot find rating history")
+		return histories, dto.ParseError(err)
+	}
+	err = many.All(ctx, &histories)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", userId).Err(err).Msg("cannot decode rating history")
+	}
+	return histories, dto.ParseError(err)
+}
@@ -0,0 +1,58 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type SeasonArchiveDao interface {
+	Insert(ctx context.Context, archive model.SeasonArchive) (id string, err error)
+	FindAll(ctx context.Context) (archives []model.SeasonArchive, err error)
+}
+
+type SeasonArchiveDaoImpl struct {
+}
+
+func NewSeasonArchiveDaoImpl() SeasonArchiveDaoImpl {
+	return SeasonArchiveDaoImpl{}
+}
+
+func (r SeasonArchiveDaoImpl) Insert(ctx context.Context, archive model.SeasonArchive) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "SeasonArchiveDao.Insert")
+	defer span.End()
+	defer metrics.ObserveDao("season_archive", "insert", time.Now())
+	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionSeason).InsertOne(ctx, archive)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("seasonId", archive.SeasonId).Err(err).Msg("cannot insert season archive")
+		return "", dto.ParseError(err)
+	}
+	return one.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r SeasonArchiveDaoImpl) FindAll(ctx context.Context) (archives []model.SeasonArchive, err error) {
+	ctx, span := tracing.Start(ctx, "SeasonArchiveDao.FindAll")
+	defer span.End()
+	defer metrics.ObserveDao("season_archive", "find_all", time.Now())
+	archives = []model.SeasonArchive{}
+	opts := options.Find()
+	opts.SetSort(bson.M{"end": -1})
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionSeason).
+		Find(ctx, bson.M{}, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot find season archives")
+		return archives, dto.ParseError(err)
+	}
+	err = many.All(ctx, &archives)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode season archives")
+	}
+	return archives, dto.ParseError(err)
+}
@@ -0,0 +1,58 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type ServerSettingDao interface {
+	Get(ctx context.Context) (setting model.ServerSetting, err error)
+	Save(ctx context.Context, setting model.ServerSetting) error
+}
+
+type ServerSettingDaoImpl struct {
+}
+
+func NewServerSettingDaoImpl() ServerSettingDaoImpl {
+	return ServerSettingDaoImpl{}
+}
+
+// Get returns the server setting, the default setting is returned when it is not saved yet.
+func (r ServerSettingDaoImpl) Get(ctx context.Context) (setting model.ServerSetting, err error) {
+	ctx, span := tracing.Start(ctx, "ServerSettingDao.Get")
+	defer span.End()
+	defer metrics.ObserveDao("server_setting", "get", time.Now())
+	err = mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionSetting).
+		FindOne(ctx, bson.M{"_id": model.ServerSettingId}).Decode(&setting)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return model.ServerSetting{Id: model.ServerSettingId}, nil
+	}
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode server setting")
+		return setting, dto.ParseError(err)
+	}
+	return setting, nil
+}
+
+func (r ServerSettingDaoImpl) Save(ctx context.Context, setting model.ServerSetting) error {
+	ctx, span := tracing.Start(ctx, "ServerSettingDao.Save")
+	defer span.End()
+	defer metrics.ObserveDao("server_setting", "save", time.Now())
+	setting.Id = model.ServerSettingId
+	_, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionSetting).
+		ReplaceOne(ctx, bson.M{"_id": model.ServerSettingId}, setting, options.Replace().SetUpsert(true))
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot save server setting")
+		return dto.ParseError(err)
+	}
+	return nil
+}
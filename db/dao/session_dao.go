@@ -0,0 +1,50 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type SessionDao interface {
+	Insert(ctx context.Context, session model.Session) (id string, err error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (session model.Session, err error)
+}
+
+type SessionDaoImpl struct {
+}
+
+func NewSessionDaoImpl() SessionDaoImpl {
+	return SessionDaoImpl{}
+}
+
+func (r SessionDaoImpl) Insert(ctx context.Context, session model.Session) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "SessionDao.Insert")
+	defer span.End()
+	defer metrics.ObserveDao("session", "insert", time.Now())
+	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionSession).InsertOne(ctx, session)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", session.UserId.Hex()).Err(err).Msg("cannot insert session")
+		return "", dto.ParseError(err)
+	}
+	return one.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r SessionDaoImpl) GetByTokenHash(ctx context.Context, tokenHash string) (session model.Session, err error) {
+	ctx, span := tracing.Start(ctx, "SessionDao.GetByTokenHash")
+	defer span.End()
+	defer metrics.ObserveDao("session", "get_by_token_hash", time.Now())
+	one := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionSession).
+		FindOne(ctx, bson.M{"token_hash": tokenHash})
+	err = one.Decode(&session)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode session")
+	}
+	return session, dto.ParseError(err)
+}
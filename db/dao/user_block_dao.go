@@ -0,0 +1,112 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type UserBlockDao interface {
+	Block(ctx context.Context, userId primitive.ObjectID, blockedUserId primitive.ObjectID) error
+	Unblock(ctx context.Context, userId primitive.ObjectID, blockedUserId primitive.ObjectID) error
+	FindByUser(ctx context.Context, userId primitive.ObjectID) (blocks []model.UserBlock, err error)
+	FindRelated(ctx context.Context, userId primitive.ObjectID) (blocks []model.UserBlock, err error)
+	IsBlocked(ctx context.Context, userId1 primitive.ObjectID, userId2 primitive.ObjectID) (blocked bool, err error)
+}
+
+type UserBlockDaoImpl struct {
+}
+
+func NewUserBlockDaoImpl() UserBlockDaoImpl {
+	return UserBlockDaoImpl{}
+}
+
+// Block is idempotent, blocking a user twice keeps the first block.
+func (r UserBlockDaoImpl) Block(ctx context.Context, userId primitive.ObjectID, blockedUserId primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "UserBlockDao.Block")
+	defer span.End()
+	defer metrics.ObserveDao("user_block", "block", time.Now())
+	filter := bson.M{"user_id": userId, "blocked_user_id": blockedUserId}
+	update := bson.M{"$setOnInsert": model.UserBlock{
+		UserId:        userId,
+		BlockedUserId: blockedUserId,
+		CreateDate:    time.Now(),
+	}}
+	_, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionBlock).
+		UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", userId.Hex()).Err(err).Msg("cannot block user")
+		return dto.ParseError(err)
+	}
+	return nil
+}
+
+func (r UserBlockDaoImpl) Unblock(ctx context.Context, userId primitive.ObjectID, blockedUserId primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "UserBlockDao.Unblock")
+	defer span.End()
+	defer metrics.ObserveDao("user_block", "unblock", time.Now())
+	_, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionBlock).
+		DeleteOne(ctx, bson.M{"user_id": userId, "blocked_user_id": blockedUserId})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", userId.Hex()).Err(err).Msg("cannot unblock user")
+		return dto.ParseError(err)
+	}
+	return nil
+}
+
+// FindByUser returns the blocks made by the user, from the newest.
+func (r UserBlockDaoImpl) FindByUser(ctx context.Context, userId primitive.ObjectID) (blocks []model.UserBlock, err error) {
+	ctx, span := tracing.Start(ctx, "UserBlockDao.FindByUser")
+	defer span.End()
+	defer metrics.ObserveDao("user_block", "find_by_user", time.Now())
+	return r.find(ctx, bson.M{"user_id": userId})
+}
+
+// FindRelated returns the blocks made by the user and the blocks made against the user.
+func (r UserBlockDaoImpl) FindRelated(ctx context.Context, userId primitive.ObjectID) (blocks []model.UserBlock, err error) {
+	ctx, span := tracing.Start(ctx, "UserBlockDao.FindRelated")
+	defer span.End()
+	defer metrics.ObserveDao("user_block", "find_related", time.Now())
+	return r.find(ctx, bson.M{"$or": []bson.M{{"user_id": userId}, {"blocked_user_id": userId}}})
+}
+
+// IsBlocked checks whether either user has blocked the other.
+func (r UserBlockDaoImpl) IsBlocked(ctx context.Context, userId1 primitive.ObjectID, userId2 primitive.ObjectID) (blocked bool, err error) {
+	ctx, span := tracing.Start(ctx, "UserBlockDao.IsBlocked")
+	defer span.End()
+	defer metrics.ObserveDao("user_block", "is_blocked", time.Now())
+	count, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionBlock).
+		CountDocuments(ctx, bson.M{"$or": []bson.M{
+			{"user_id": userId1, "blocked_user_id": userId2},
+			{"user_id": userId2, "blocked_user_id": userId1},
+		}})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", userId1.Hex()).Err(err).Msg("cannot count user blocks")
+		return false, dto.ParseError(err)
+	}
+	return count > 0, nil
+}
+
+func (r UserBlockDaoImpl) find(ctx context.Context, filter bson.M) (blocks []model.UserBlock, err error) {
+	blocks = []model.UserBlock{}
+	opts := options.Find()
+	opts.SetSort(bson.M{"create_date": -1})
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionBlock).
+		Find(ctx, filter, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot find user blocks")
+		return blocks, dto.ParseError(err)
+	}
+	err = many.All(ctx, &blocks)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode user blocks")
+	}
+	return blocks, dto.ParseError(err)
+}
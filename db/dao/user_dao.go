@@ -3,16 +3,22 @@ package dao
 import (
 	"battleship/db/mongodb"
 	"battleship/dto"
+	"battleship/metrics"
 	"battleship/model"
+	"battleship/tracing"
 	"context"
-	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
 )
 
 type UserDao interface {
-	Insert(user model.User) (id string, err error)
-	GetOne(id string) (user model.User, err error)
+	Insert(ctx context.Context, user model.User) (id string, err error)
+	GetOne(ctx context.Context, id string) (user model.User, err error)
+	GetByMobile(ctx context.Context, mobile string) (user model.User, err error)
+	SetRating(ctx context.Context, userId primitive.ObjectID, ratedGames int, rating int) (updated bool, err error)
+	FindByIds(ctx context.Context, ids []primitive.ObjectID) (users []model.User, err error)
+	SetBan(ctx context.Context, userId primitive.ObjectID, ban *model.UserBan) error
 }
 
 type UserDaoImpl struct {
@@ -22,27 +28,101 @@ func NewUserDaoImpl() UserDaoImpl {
 	return UserDaoImpl{}
 }
 
-func (r UserDaoImpl) GetOne(id string) (user model.User, err error) {
+func (r UserDaoImpl) GetOne(ctx context.Context, id string) (user model.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserDao.GetOne")
+	defer span.End()
+	defer metrics.ObserveDao("user", "get_one", time.Now())
 	hex, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		log.Warn().Str("userId", id).Err(err).Msg("cannot convert to ObjectId")
+		tracing.Log(ctx).Warn().Str("userId", id).Err(err).Msg("cannot convert to ObjectId")
 		return user, dto.ParseError(err)
 	}
 
-	result := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionUser).FindOne(context.TODO(), bson.D{{"_id", hex}})
+	result := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionUser).FindOne(ctx, bson.D{{"_id", hex}})
 
 	err = result.Decode(&user)
 	if err != nil {
-		log.Warn().Str("userId", id).Err(err).Msg("cannot decode user")
+		tracing.Log(ctx).Warn().Str("userId", id).Err(err).Msg("cannot decode user")
 	}
 	return user, dto.ParseError(err)
 }
 
-func (r UserDaoImpl) Insert(user model.User) (id string, err error) {
-	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionUser).InsertOne(context.TODO(), user)
+func (r UserDaoImpl) GetByMobile(ctx context.Context, mobile string) (user model.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserDao.GetByMobile")
+	defer span.End()
+	defer metrics.ObserveDao("user", "get_by_mobile", time.Now())
+	result := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionUser).FindOne(ctx, bson.M{"mobile": mobile})
+	err = result.Decode(&user)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("mobile", mobile).Err(err).Msg("cannot decode user")
+	}
+	return user, dto.ParseError(err)
+}
+
+func (r UserDaoImpl) Insert(ctx context.Context, user model.User) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "UserDao.Insert")
+	defer span.End()
+	defer metrics.ObserveDao("user", "insert", time.Now())
+	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionUser).InsertOne(ctx, user)
 	if err != nil {
-		log.Warn().Err(err).Msg("cannot insert user")
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot insert user")
 		return "", dto.ParseError(err)
 	}
 	return one.InsertedID.(primitive.ObjectID).Hex(), nil
 }
+
+// SetRating saves the rating after one more ranked game, only if the user has still played ratedGames ranked games.
+// A rating that has changed since it was read, e.g. by another game that finished at the same time, is not overwritten.
+func (r UserDaoImpl) SetRating(ctx context.Context, userId primitive.ObjectID, ratedGames int, rating int) (updated bool, err error) {
+	ctx, span := tracing.Start(ctx, "UserDao.SetRating")
+	defer span.End()
+	defer metrics.ObserveDao("user", "set_rating", time.Now())
+	filter := bson.M{"_id": userId, "rated_games": ratedGames}
+	if ratedGames == 0 {
+		// rated_games is not saved before the first ranked game
+		filter["rated_games"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	result, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionUser).
+		UpdateOne(ctx, filter, bson.M{"$set": bson.M{"rating": rating, "rated_games": ratedGames + 1}})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", userId.Hex()).Err(err).Msg("cannot set user rating")
+		return false, dto.ParseError(err)
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (r UserDaoImpl) FindByIds(ctx context.Context, ids []primitive.ObjectID) (users []model.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserDao.FindByIds")
+	defer span.End()
+	defer metrics.ObserveDao("user", "find_by_ids", time.Now())
+	users = []model.User{}
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionUser).
+		Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot find users")
+		return users, dto.ParseError(err)
+	}
+	err = many.All(ctx, &users)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode users")
+	}
+	return users, dto.ParseError(err)
+}
+
+// SetBan saves the ban of the user, a nil ban removes it.
+func (r UserDaoImpl) SetBan(ctx context.Context, userId primitive.ObjectID, ban *model.UserBan) error {
+	ctx, span := tracing.Start(ctx, "UserDao.SetBan")
+	defer span.End()
+	defer metrics.ObserveDao("user", "set_ban", time.Now())
+	update := bson.M{"$unset": bson.M{"ban": ""}}
+	if ban != nil {
+		update = bson.M{"$set": bson.M{"ban": ban}}
+	}
+	_, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionUser).
+		UpdateOne(ctx, bson.M{"_id": userId}, update)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", userId.Hex()).Err(err).Msg("cannot set user ban")
+		return dto.ParseError(err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type UserReportDao interface {
+	Insert(ctx context.Context, report model.UserReport) (id string, inserted bool, err error)
+	GetOne(ctx context.Context, reportId string) (report model.UserReport, err error)
+	UpdateIfPending(ctx context.Context, report model.UserReport) (updated bool, err error)
+	FindByStatus(ctx context.Context, status model.ReportStatus, limit int64) (reports []model.UserReport, err error)
+	CountConfirmed(ctx context.Context, reportedUserId primitive.ObjectID, since time.Time) (count int64, err error)
+}
+
+type UserReportDaoImpl struct {
+}
+
+func NewUserReportDaoImpl() UserReportDaoImpl {
+	return UserReportDaoImpl{}
+}
+
+// Insert adds the report if the reporter has not reported the user in the game yet, inserted is false otherwise.
+func (r UserReportDaoImpl) Insert(ctx context.Context, report model.UserReport) (id string, inserted bool, err error) {
+	ctx, span := tracing.Start(ctx, "UserReportDao.Insert")
+	defer span.End()
+	defer metrics.ObserveDao("user_report", "insert", time.Now())
+	filter := bson.M{
+		"reporter_id":      report.ReporterId,
+		"game_id":          report.GameId,
+		"reported_user_id": report.ReportedUserId,
+	}
+	result, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionReport).
+		UpdateOne(ctx, filter, bson.M{"$setOnInsert": report}, options.Update().SetUpsert(true))
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("gameId", report.GameId.Hex()).Err(err).Msg("cannot insert user report")
+		return "", false, dto.ParseError(err)
+	}
+	if result.UpsertedID == nil {
+		return "", false, nil
+	}
+	return result.UpsertedID.(primitive.ObjectID).Hex(), true, nil
+}
+
+func (r UserReportDaoImpl) GetOne(ctx context.Context, reportId string) (report model.UserReport, err error) {
+	ctx, span := tracing.Start(ctx, "UserReportDao.GetOne")
+	defer span.End()
+	defer metrics.ObserveDao("user_report", "get_one", time.Now())
+	hex, err := primitive.ObjectIDFromHex(reportId)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("reportId", reportId).Err(err).Msg("cannot convert to objectId")
+		return report, dto.ParseError(err)
+	}
+	err = mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionReport).
+		FindOne(ctx, bson.M{"_id": hex}).Decode(&report)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("reportId", reportId).Err(err).Msg("cannot decode user report")
+	}
+	return report, dto.ParseError(err)
+}
+
+// UpdateIfPending saves a reviewed report only if it is still pending, so a report is not reviewed twice.
+func (r UserReportDaoImpl) UpdateIfPending(ctx context.Context, report model.UserReport) (updated bool, err error) {
+	ctx, span := tracing.Start(ctx, "UserReportDao.UpdateIfPending")
+	defer span.End()
+	defer metrics.ObserveDao("user_report", "update_if_pending", time.Now())
+	result, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionReport).
+		UpdateOne(ctx, bson.M{"_id": report.Id, "status": model.ReportPending}, bson.M{"$set": report})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("reportId", report.Id.Hex()).Err(err).Msg("cannot update user report")
+		return false, dto.ParseError(err)
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// FindByStatus returns the reports of the status from the oldest, so the moderation queue is worked in order.
+func (r UserReportDaoImpl) FindByStatus(ctx context.Context, status model.ReportStatus, limit int64) (reports []model.UserReport, err error) {
+	ctx, span := tracing.Start(ctx, "UserReportDao.FindByStatus")
+	defer span.End()
+	defer metrics.ObserveDao("user_report", "find_by_status", time.Now())
+	reports = []model.UserReport{}
+	opts := options.Find()
+	opts.SetSort(bson.M{"create_date": 1})
+	opts.SetLimit(limit)
+	many, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionReport).
+		Find(ctx, bson.M{"status": status}, opts)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot find user reports")
+		return reports, dto.ParseError(err)
+	}
+	err = many.All(ctx, &reports)
+	if err != nil {
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot decode user reports")
+	}
+	return reports, dto.ParseError(err)
+}
+
+// CountConfirmed counts the reports against the user that are confirmed since the date.
+func (r UserReportDaoImpl) CountConfirmed(ctx context.Context, reportedUserId primitive.ObjectID, since time.Time) (count int64, err error) {
+	ctx, span := tracing.Start(ctx, "UserReportDao.CountConfirmed")
+	defer span.End()
+	defer metrics.ObserveDao("user_report", "count_confirmed", time.Now())
+	count, err = mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionReport).
+		CountDocuments(ctx, bson.M{
+			"reported_user_id": reportedUserId,
+			"status":           model.ReportConfirmed,
+			"review_date":      bson.M{"$gte": since},
+		})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", reportedUserId.Hex()).Err(err).Msg("cannot count confirmed reports")
+		return 0, dto.ParseError(err)
+	}
+	return count, nil
+}
@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type UserStatsDao interface {
+	GetByUser(ctx context.Context, userId string) (stats model.UserStats, err error)
+	AddGame(ctx context.Context, game model.UserGameStats) error
+}
+
+type UserStatsDaoImpl struct {
+}
+
+func NewUserStatsDaoImpl() UserStatsDaoImpl {
+	return UserStatsDaoImpl{}
+}
+
+func (r UserStatsDaoImpl) GetByUser(ctx context.Context, userId string) (stats model.UserStats, err error) {
+	ctx, span := tracing.Start(ctx, "UserStatsDao.GetByUser")
+	defer span.End()
+	defer metrics.ObserveDao("user_stats", "get_by_user", time.Now())
+	hex, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", userId).Err(err).Msg("cannot convert to objectId")
+		return stats, dto.ParseError(err)
+	}
+	one := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionUserStats).
+		FindOne(ctx, bson.M{"user_id": hex})
+	err = one.Decode(&stats)
+	if err != nil {
+		tracing.Log(ctx).Debug().Str("userId", userId).Err(err).Msg("cannot decode user stats")
+	}
+	return stats, dto.ParseError(err)
+}
+
+// AddGame adds one finished game to the stats of the user with $inc, so games that finish at the same time are all
+// counted. The stats are created if not exists.
+func (r UserStatsDaoImpl) AddGame(ctx context.Context, game model.UserGameStats) error {
+	ctx, span := tracing.Start(ctx, "UserStatsDao.AddGame")
+	defer span.End()
+	defer metrics.ObserveDao("user_stats", "add_game", time.Now())
+	inc := bson.M{
+		"games_played":            1,
+		"total_game_duration_sec": game.DurationSec,
+		"hits":                    game.Hits,
+		"misses":                  game.Misses,
+		"reveals":                 game.Reveals,
+		"revealed_ships":          game.RevealedShips,
+		"ship_moves":              game.ShipMoves,
+	}
+	update := bson.M{"$inc": inc, "$max": bson.M{"last_game_date": game.EndDate}}
+	switch game.Result {
+	case model.WinResult:
+		inc["wins"] = 1
+		inc["current_win_streak"] = 1
+		if game.EndReason != "" {
+			inc["wins_by_end_reason."+string(game.EndReason)] = 1
+		}
+	case model.LossResult:
+		inc["losses"] = 1
+		if game.EndReason != "" {
+			inc["losses_by_end_reason."+string(game.EndReason)] = 1
+		}
+		update["$set"] = bson.M{"current_win_streak": 0}
+	default:
+		inc["no_results"] = 1
+	}
+
+	collection := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionUserStats)
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var stats model.UserStats
+	err := collection.FindOneAndUpdate(ctx, bson.M{"user_id": game.UserId}, update, opts).Decode(&stats)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", game.UserId.Hex()).Err(err).Msg("cannot add game to user stats")
+		return dto.ParseError(err)
+	}
+	if game.Result != model.WinResult {
+		return nil
+	}
+	// the streak is read back after the $inc, $max keeps the longest one when wins are added at the same time
+	_, err = collection.UpdateOne(ctx, bson.M{"user_id": game.UserId},
+		bson.M{"$max": bson.M{"longest_win_streak": stats.CurrentWinStreak}})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("userId", game.UserId.Hex()).Err(err).Msg("cannot update longest win streak")
+	}
+	return dto.ParseError(err)
+}
@@ -0,0 +1,126 @@
+package dao
+
+import (
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/metrics"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type WebhookDao interface {
+	Insert(ctx context.Context, delivery model.WebhookDelivery) (id string, err error)
+	GetOne(ctx context.Context, deliveryId string) (delivery model.WebhookDelivery, err error)
+	Update(ctx context.Context, delivery model.WebhookDelivery) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (delivery model.WebhookDelivery, found bool, err error)
+	ClaimForRedelivery(ctx context.Context, deliveryId primitive.ObjectID, now time.Time, lease time.Duration) (delivery model.WebhookDelivery, claimed bool, err error)
+}
+
+type WebhookDaoImpl struct {
+}
+
+func NewWebhookDaoImpl() WebhookDaoImpl {
+	return WebhookDaoImpl{}
+}
+
+func (r WebhookDaoImpl) Insert(ctx context.Context, delivery model.WebhookDelivery) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookDao.Insert")
+	defer span.End()
+	defer metrics.ObserveDao("webhook", "insert", time.Now())
+	one, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionWebhook).
+		InsertOne(ctx, delivery)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("gameId", delivery.GameId.Hex()).Err(err).Msg("cannot insert webhook delivery")
+		return "", dto.ParseError(err)
+	}
+	return one.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r WebhookDaoImpl) GetOne(ctx context.Context, deliveryId string) (delivery model.WebhookDelivery, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookDao.GetOne")
+	defer span.End()
+	defer metrics.ObserveDao("webhook", "get_one", time.Now())
+	hex, err := primitive.ObjectIDFromHex(deliveryId)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("deliveryId", deliveryId).Err(err).Msg("cannot convert to objectId")
+		return delivery, dto.ParseError(err)
+	}
+	one := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionWebhook).
+		FindOne(ctx, bson.M{"_id": hex})
+	err = one.Decode(&delivery)
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("deliveryId", deliveryId).Err(err).Msg("cannot decode webhook delivery")
+	}
+	return delivery, dto.ParseError(err)
+}
+
+func (r WebhookDaoImpl) Update(ctx context.Context, delivery model.WebhookDelivery) error {
+	ctx, span := tracing.Start(ctx, "WebhookDao.Update")
+	defer span.End()
+	defer metrics.ObserveDao("webhook", "update", time.Now())
+	_, err := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionWebhook).
+		UpdateOne(ctx, bson.M{"_id": delivery.Id}, bson.M{"$set": delivery})
+	if err != nil {
+		tracing.Log(ctx).Warn().Str("deliveryId", delivery.Id.Hex()).Err(err).Msg("cannot update webhook delivery")
+		return dto.ParseError(err)
+	}
+	return nil
+}
+
+// ClaimDue takes the oldest pending delivery that is due and moves its next attempt time forward by lease, so the
+// delivery is not attempted twice at the same time.
+func (r WebhookDaoImpl) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (delivery model.WebhookDelivery, found bool, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookDao.ClaimDue")
+	defer span.End()
+	defer metrics.ObserveDao("webhook", "claim_due", time.Now())
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"next_attempt_time": 1}).
+		SetReturnDocument(options.After)
+	one := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionWebhook).
+		FindOneAndUpdate(ctx,
+			bson.M{"status": model.DeliveryPending, "next_attempt_time": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"next_attempt_time": now.Add(lease), "lease_until": now.Add(lease)}}, opts)
+	err = one.Decode(&delivery)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return delivery, false, nil
+		}
+		tracing.Log(ctx).Warn().Err(err).Msg("cannot claim webhook delivery")
+		return delivery, false, dto.ParseError(err)
+	}
+	return delivery, true, nil
+}
+
+// ClaimForRedelivery resets the delivery to a pending one with new attempts and takes the same lease as ClaimDue.
+// claimed is false when an attempt of the delivery is in progress.
+func (r WebhookDaoImpl) ClaimForRedelivery(ctx context.Context, deliveryId primitive.ObjectID, now time.Time, lease time.Duration) (delivery model.WebhookDelivery, claimed bool, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookDao.ClaimForRedelivery")
+	defer span.End()
+	defer metrics.ObserveDao("webhook", "claim_for_redelivery", time.Now())
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	one := mongodb.DB.Client.Database(mongodb.BattleshipDb).Collection(mongodb.CollectionWebhook).
+		FindOneAndUpdate(ctx,
+			bson.M{"_id": deliveryId, "lease_until": bson.M{"$not": bson.M{"$gt": now}}},
+			bson.M{"$set": bson.M{
+				"status":            model.DeliveryPending,
+				"attempts":          0,
+				"next_attempt_time": now.Add(lease),
+				"lease_until":       now.Add(lease),
+			}}, opts)
+	err = one.Decode(&delivery)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return delivery, false, nil
+		}
+		tracing.Log(ctx).Warn().Str("deliveryId", deliveryId.Hex()).Err(err).Msg("cannot claim webhook delivery")
+		return delivery, false, dto.ParseError(err)
+	}
+	return delivery, true, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"time"
 )
 
@@ -14,6 +15,21 @@ const (
 	CollectionGame      = "game"
 	CollectionGameEvent = "game_event"
 	CollectionUser      = "user"
+	CollectionOtp       = "otp"
+	CollectionSession   = "session"
+	CollectionRating    = "rating_history"
+	CollectionBoard     = "leaderboard"
+	CollectionSeason    = "season_archive"
+	CollectionUserStats = "user_stats"
+	CollectionInvite    = "invite"
+	CollectionChat      = "chat_message"
+	CollectionChatMute  = "chat_mute"
+	CollectionWebhook   = "webhook_delivery"
+	CollectionSetting   = "server_setting"
+	CollectionAudit     = "admin_audit"
+	CollectionBlock     = "user_block"
+	CollectionReport    = "user_report"
+	CollectionCheatFlag = "cheat_flag"
 )
 
 var (
@@ -22,7 +38,6 @@ var (
 
 type Client struct {
 	Client *mongo.Client
-	ctx    context.Context
 }
 
 func CreateMongoClient() (*Client, error) {
@@ -43,11 +58,18 @@ func CreateMongoClient() (*Client, error) {
 
 	DB = Client{
 		Client: client,
-		ctx:    ctx,
 	}
 	return &DB, err
 }
 
-func (r *Client) Close() {
-	_ = r.Client.Disconnect(r.ctx)
+// Close disconnects the client, ctx bounds the wait for the operations in progress.
+func (r *Client) Close(ctx context.Context) {
+	_ = r.Client.Disconnect(ctx)
+}
+
+// Ping checks the connection to the primary in timeout.
+func (r *Client) Ping(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return r.Client.Ping(ctx, readpref.Primary())
 }
@@ -0,0 +1,153 @@
+package mongodb
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+var indexes = map[string][]mongo.IndexModel{
+	CollectionGame: {
+		{
+			Keys: bson.D{{Key: "side_1_user", Value: 1}, {Key: "_id", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "side_2_user", Value: 1}, {Key: "_id", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "public", Value: 1}, {Key: "create_date", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "last_move_time", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "pause_deadline", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "cheat_checked", Value: 1}, {Key: "end_date", Value: 1}},
+		},
+	},
+	CollectionGameEvent: {
+		{
+			Keys: bson.D{{Key: "game_id", Value: 1}, {Key: "time", Value: -1}},
+		},
+	},
+	CollectionBoard: {
+		{
+			Keys:    bson.D{{Key: "board", Value: 1}, {Key: "period", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "board", Value: 1}, {Key: "period", Value: 1}, {Key: "wins", Value: -1}, {Key: "losses", Value: 1}},
+		},
+	},
+	CollectionUserStats: {
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	},
+	CollectionWebhook: {
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_time", Value: 1}},
+		},
+	},
+	CollectionChat: {
+		{
+			Keys: bson.D{{Key: "game_id", Value: 1}, {Key: "time", Value: -1}},
+		},
+	},
+	CollectionChatMute: {
+		{
+			Keys:    bson.D{{Key: "game_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	},
+	CollectionInvite: {
+		{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	},
+	CollectionAudit: {
+		{
+			Keys: bson.D{{Key: "time", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "target_id", Value: 1}, {Key: "time", Value: -1}},
+		},
+	},
+	CollectionBlock: {
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "blocked_user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "blocked_user_id", Value: 1}},
+		},
+	},
+	CollectionReport: {
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "create_date", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "reported_user_id", Value: 1}, {Key: "status", Value: 1}, {Key: "review_date", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "reporter_id", Value: 1}, {Key: "game_id", Value: 1}, {Key: "reported_user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	},
+	CollectionCheatFlag: {
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "create_date", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "kind", Value: 1}, {Key: "other_user_id", Value: 1}, {Key: "status", Value: 1}},
+		},
+	},
+	CollectionOtp: {
+		{
+			Keys: bson.D{{Key: "mobile", Value: 1}, {Key: "create_date", Value: -1}},
+		},
+		{
+			// expired codes are kept an hour more, the hourly limit counts them
+			Keys:    bson.D{{Key: "expire_date", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(3600),
+		},
+	},
+	CollectionSession: {
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expire_date", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	},
+	CollectionSeason: {
+		{
+			Keys:    bson.D{{Key: "season_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	},
+}
+
+// EnsureIndexes creates the indexes of all collections, existing indexes are left untouched.
+func EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for collection, models := range indexes {
+		names, err := DB.Client.Database(BattleshipDb).Collection(collection).Indexes().CreateMany(ctx, models)
+		if err != nil {
+			log.Error().Err(err).Str("collection", collection).Msg("cannot create indexes")
+			return err
+		}
+		log.Debug().Str("collection", collection).Strs("indexes", names).Msg("indexes are ensured")
+	}
+	return nil
+}
@@ -7,7 +7,9 @@ import (
 	"battleship/db/dao"
 	"battleship/events/incoming_events"
 	"battleship/events/outgoing_events"
+	"battleship/profanity"
 	"battleship/service"
+	"battleship/sms"
 	"battleship/socket"
 	"github.com/google/wire"
 )
@@ -17,6 +19,7 @@ func CreateGameController() controllers.GameController {
 		controllers.NewGameControllerImpl,
 		wire.Bind(new(controllers.GameController), new(controllers.GameControllerImpl)),
 		CreateGameService,
+		CreateInviteService,
 	))
 }
 
@@ -25,6 +28,65 @@ func CreateUserController() controllers.UserController {
 		controllers.NewUserController,
 		wire.Bind(new(controllers.UserController), new(controllers.UserControllerImpl)),
 		CreateUserService,
+		CreateBlockService,
+		CreateReportService,
+	))
+}
+
+func CreateAuthController() controllers.AuthController {
+	panic(wire.Build(
+		controllers.NewAuthControllerImpl,
+		wire.Bind(new(controllers.AuthController), new(controllers.AuthControllerImpl)),
+		CreateAuthService,
+	))
+}
+
+func CreateMatchmakingController() controllers.MatchmakingController {
+	panic(wire.Build(
+		controllers.NewMatchmakingControllerImpl,
+		wire.Bind(new(controllers.MatchmakingController), new(controllers.MatchmakingControllerImpl)),
+		CreateMatchmakingService,
+	))
+}
+
+func CreateLeaderboardController() controllers.LeaderboardController {
+	panic(wire.Build(
+		controllers.NewLeaderboardControllerImpl,
+		wire.Bind(new(controllers.LeaderboardController), new(controllers.LeaderboardControllerImpl)),
+		CreateLeaderboardService,
+		CreateStatisticsService,
+	))
+}
+
+func CreateLobbyController() controllers.LobbyController {
+	panic(wire.Build(
+		controllers.NewLobbyControllerImpl,
+		wire.Bind(new(controllers.LobbyController), new(controllers.LobbyControllerImpl)),
+		CreateLobbyService,
+	))
+}
+
+func CreateHealthController() controllers.HealthController {
+	panic(wire.Build(
+		controllers.NewHealthControllerImpl,
+		wire.Bind(new(controllers.HealthController), new(controllers.HealthControllerImpl)),
+		CreateHealthService,
+	))
+}
+
+func CreateAdminController() controllers.AdminController {
+	panic(wire.Build(
+		controllers.NewAdminControllerImpl,
+		wire.Bind(new(controllers.AdminController), new(controllers.AdminControllerImpl)),
+		CreateAdminService,
+	))
+}
+
+func CreateStatisticsController() controllers.StatisticsController {
+	panic(wire.Build(
+		controllers.NewStatisticsControllerImpl,
+		wire.Bind(new(controllers.StatisticsController), new(controllers.StatisticsControllerImpl)),
+		CreateStatisticsService,
 	))
 }
 
@@ -38,6 +100,15 @@ func CreateGameService() service.GameService {
 		CreateUserDao,
 		CreateGameEventDao,
 		CreateOutgoingEventHandler,
+		CreateRatingService,
+		CreateLeaderboardService,
+		CreateStatisticsService,
+		CreateLobbyService,
+		CreateInviteService,
+		CreateGameStateMachine,
+		CreateChatService,
+		CreateWebhookService,
+		CreateBlockService,
 	))
 }
 
@@ -46,6 +117,173 @@ func CreateUserService() service.UserService {
 		service.NewUserServiceImpl,
 		wire.Bind(new(service.UserService), new(service.UserServiceImpl)),
 		CreateUserDao,
+		CreateRatingHistoryDao,
+	))
+}
+
+func CreateRatingService() service.RatingService {
+	panic(wire.Build(
+		service.NewRatingServiceImpl,
+		wire.Bind(new(service.RatingService), new(service.RatingServiceImpl)),
+		CreateUserDao,
+		CreateRatingHistoryDao,
+	))
+}
+
+func CreateAuthService() service.AuthService {
+	panic(wire.Build(
+		service.NewAuthServiceImpl,
+		wire.Bind(new(service.AuthService), new(service.AuthServiceImpl)),
+		CreateOtpDao,
+		CreateSessionDao,
+		CreateUserDao,
+		CreateSmsSender,
+	))
+}
+
+func CreateMatchmakingService() service.MatchmakingService {
+	panic(wire.Build(
+		service.NewMatchmakingServiceImpl,
+		wire.Bind(new(service.MatchmakingService), new(service.MatchmakingServiceImpl)),
+		CreateGameService,
+		CreateUserDao,
+		CreateBlockService,
+		CreateOutgoingEventHandler,
+	))
+}
+
+func CreateLeaderboardService() service.LeaderboardService {
+	panic(wire.Build(
+		service.NewLeaderboardServiceImpl,
+		wire.Bind(new(service.LeaderboardService), new(service.LeaderboardServiceImpl)),
+		CreateLeaderboardDao,
+		CreateSeasonArchiveDao,
+		CreateUserDao,
+	))
+}
+
+func CreateStatisticsService() service.StatisticsService {
+	panic(wire.Build(
+		service.NewStatisticsServiceImpl,
+		wire.Bind(new(service.StatisticsService), new(service.StatisticsServiceImpl)),
+		CreateUserStatsDao,
+		CreateGameEventDao,
+	))
+}
+
+func CreateLobbyService() service.LobbyService {
+	panic(wire.Build(
+		service.NewLobbyServiceImpl,
+		wire.Bind(new(service.LobbyService), new(service.LobbyServiceImpl)),
+		CreateGameDao,
+		CreateUserDao,
+		CreateBlockService,
+		CreateOutgoingEventHandler,
+	))
+}
+
+func CreateInviteService() service.InviteService {
+	panic(wire.Build(
+		service.NewInviteServiceImpl,
+		wire.Bind(new(service.InviteService), new(service.InviteServiceImpl)),
+		CreateInviteDao,
+		CreateGameDao,
+		CreateGameStateMachine,
+	))
+}
+
+func CreateGameStateMachine() service.GameStateMachine {
+	panic(wire.Build(
+		service.NewGameStateMachineImpl,
+		wire.Bind(new(service.GameStateMachine), new(service.GameStateMachineImpl)),
+		CreateGameDao,
+		CreateGameEventDao,
+	))
+}
+
+func CreateHealthService() service.HealthService {
+	panic(wire.Build(
+		service.NewHealthServiceImpl,
+		wire.Bind(new(service.HealthService), new(service.HealthServiceImpl)),
+	))
+}
+
+func CreateWebhookService() service.WebhookService {
+	panic(wire.Build(
+		service.NewWebhookServiceImpl,
+		wire.Bind(new(service.WebhookService), new(service.WebhookServiceImpl)),
+		CreateWebhookDao,
+	))
+}
+
+func CreateAdminService() service.AdminService {
+	panic(wire.Build(
+		service.NewAdminServiceImpl,
+		wire.Bind(new(service.AdminService), new(service.AdminServiceImpl)),
+		CreateServerSettingDao,
+		CreateAdminAuditDao,
+		CreateGameDao,
+		CreateGameEventDao,
+		CreateUserDao,
+		CreateGameService,
+		CreateWebhookService,
+		CreateReportService,
+		CreateAntiCheatService,
+		CreateOutgoingEventHandler,
+	))
+}
+
+func CreateBlockService() service.BlockService {
+	panic(wire.Build(
+		service.NewBlockServiceImpl,
+		wire.Bind(new(service.BlockService), new(service.BlockServiceImpl)),
+		CreateUserBlockDao,
+		CreateUserDao,
+	))
+}
+
+func CreateReportService() service.ReportService {
+	panic(wire.Build(
+		service.NewReportServiceImpl,
+		wire.Bind(new(service.ReportService), new(service.ReportServiceImpl)),
+		CreateUserReportDao,
+		CreateGameDao,
+		CreateChatDao,
+		CreateUserDao,
+	))
+}
+
+func CreateAntiCheatService() service.AntiCheatService {
+	panic(wire.Build(
+		service.NewAntiCheatServiceImpl,
+		wire.Bind(new(service.AntiCheatService), new(service.AntiCheatServiceImpl)),
+		CreateGameDao,
+		CreateGameEventDao,
+		CreateCheatFlagDao,
+	))
+}
+
+func CreateChatService() service.ChatService {
+	panic(wire.Build(
+		service.NewChatServiceImpl,
+		wire.Bind(new(service.ChatService), new(service.ChatServiceImpl)),
+		CreateChatDao,
+		CreateGameDao,
+		CreateGameStateMachine,
+		CreateOutgoingEventHandler,
+		CreateProfanityFilter,
+	))
+}
+
+func CreateProfanityFilter() profanity.Filter {
+	panic(wire.Build(
+		profanity.NewFilter,
+	))
+}
+
+func CreateSmsSender() sms.SmsSender {
+	panic(wire.Build(
+		sms.NewSmsSender,
 	))
 }
 
@@ -53,7 +291,8 @@ func CreateIncomingEventHandler() incoming_events.IncomingEventHandler {
 	panic(wire.Build(
 		incoming_events.NewIncomingEventHandlerImpl,
 		wire.Bind(new(incoming_events.IncomingEventHandler), new(incoming_events.IncomingEventHandlerImpl)),
-		CreateGameDao,
+		CreateChatService,
+		CreateOutgoingEventHandler,
 	))
 }
 
@@ -95,3 +334,101 @@ func CreateGameEventDao() dao.GameEventDao {
 		wire.Bind(new(dao.GameEventDao), new(dao.GameEventDaoImpl)),
 	))
 }
+
+func CreateOtpDao() dao.OtpDao {
+	panic(wire.Build(
+		dao.NewOtpDaoImpl,
+		wire.Bind(new(dao.OtpDao), new(dao.OtpDaoImpl)),
+	))
+}
+
+func CreateSessionDao() dao.SessionDao {
+	panic(wire.Build(
+		dao.NewSessionDaoImpl,
+		wire.Bind(new(dao.SessionDao), new(dao.SessionDaoImpl)),
+	))
+}
+
+func CreateRatingHistoryDao() dao.RatingHistoryDao {
+	panic(wire.Build(
+		dao.NewRatingHistoryDaoImpl,
+		wire.Bind(new(dao.RatingHistoryDao), new(dao.RatingHistoryDaoImpl)),
+	))
+}
+
+func CreateLeaderboardDao() dao.LeaderboardDao {
+	panic(wire.Build(
+		dao.NewLeaderboardDaoImpl,
+		wire.Bind(new(dao.LeaderboardDao), new(dao.LeaderboardDaoImpl)),
+	))
+}
+
+func CreateSeasonArchiveDao() dao.SeasonArchiveDao {
+	panic(wire.Build(
+		dao.NewSeasonArchiveDaoImpl,
+		wire.Bind(new(dao.SeasonArchiveDao), new(dao.SeasonArchiveDaoImpl)),
+	))
+}
+
+func CreateUserStatsDao() dao.UserStatsDao {
+	panic(wire.Build(
+		dao.NewUserStatsDaoImpl,
+		wire.Bind(new(dao.UserStatsDao), new(dao.UserStatsDaoImpl)),
+	))
+}
+
+func CreateServerSettingDao() dao.ServerSettingDao {
+	panic(wire.Build(
+		dao.NewServerSettingDaoImpl,
+		wire.Bind(new(dao.ServerSettingDao), new(dao.ServerSettingDaoImpl)),
+	))
+}
+
+func CreateAdminAuditDao() dao.AdminAuditDao {
+	panic(wire.Build(
+		dao.NewAdminAuditDaoImpl,
+		wire.Bind(new(dao.AdminAuditDao), new(dao.AdminAuditDaoImpl)),
+	))
+}
+
+func CreateUserBlockDao() dao.UserBlockDao {
+	panic(wire.Build(
+		dao.NewUserBlockDaoImpl,
+		wire.Bind(new(dao.UserBlockDao), new(dao.UserBlockDaoImpl)),
+	))
+}
+
+func CreateUserReportDao() dao.UserReportDao {
+	panic(wire.Build(
+		dao.NewUserReportDaoImpl,
+		wire.Bind(new(dao.UserReportDao), new(dao.UserReportDaoImpl)),
+	))
+}
+
+func CreateCheatFlagDao() dao.CheatFlagDao {
+	panic(wire.Build(
+		dao.NewCheatFlagDaoImpl,
+		wire.Bind(new(dao.CheatFlagDao), new(dao.CheatFlagDaoImpl)),
+	))
+}
+
+func CreateWebhookDao() dao.WebhookDao {
+	panic(wire.Build(
+		dao.NewWebhookDaoImpl,
+		wire.Bind(new(dao.WebhookDao), new(dao.WebhookDaoImpl)),
+	))
+}
+
+func CreateChatDao() dao.ChatDao {
+	panic(wire.Build(
+		dao.NewChatDaoImpl,
+		wire.Bind(new(dao.ChatDao), new(dao.ChatDaoImpl)),
+	))
+}
+
+func CreateInviteDao() dao.InviteDao {
+	panic(wire.Build(
+		dao.NewInviteDaoImpl,
+		wire.Bind(new(dao.InviteDao), new(dao.InviteDaoImpl)),
+	))
+}
@@ -10,7 +10,9 @@ import (
 	"battleship/db/dao"
 	"battleship/events/incoming_events"
 	"battleship/events/outgoing_events"
+	"battleship/profanity"
 	"battleship/service"
+	"battleship/sms"
 	"battleship/socket"
 )
 
@@ -18,34 +20,224 @@ import (
 
 func CreateGameController() controllers.GameController {
 	gameService := CreateGameService()
-	gameControllerImpl := controllers.NewGameControllerImpl(gameService)
+	inviteService := CreateInviteService()
+	gameControllerImpl := controllers.NewGameControllerImpl(gameService, inviteService)
 	return gameControllerImpl
 }
 
 func CreateUserController() controllers.UserController {
 	userService := CreateUserService()
-	userControllerImpl := controllers.NewUserController(userService)
+	blockService := CreateBlockService()
+	reportService := CreateReportService()
+	userControllerImpl := controllers.NewUserController(userService, blockService, reportService)
 	return userControllerImpl
 }
 
+func CreateAuthController() controllers.AuthController {
+	authService := CreateAuthService()
+	authControllerImpl := controllers.NewAuthControllerImpl(authService)
+	return authControllerImpl
+}
+
+func CreateMatchmakingController() controllers.MatchmakingController {
+	matchmakingService := CreateMatchmakingService()
+	matchmakingControllerImpl := controllers.NewMatchmakingControllerImpl(matchmakingService)
+	return matchmakingControllerImpl
+}
+
+func CreateLeaderboardController() controllers.LeaderboardController {
+	leaderboardService := CreateLeaderboardService()
+	leaderboardControllerImpl := controllers.NewLeaderboardControllerImpl(leaderboardService)
+	return leaderboardControllerImpl
+}
+
+func CreateLobbyController() controllers.LobbyController {
+	lobbyService := CreateLobbyService()
+	lobbyControllerImpl := controllers.NewLobbyControllerImpl(lobbyService)
+	return lobbyControllerImpl
+}
+
+func CreateHealthController() controllers.HealthController {
+	healthService := CreateHealthService()
+	healthControllerImpl := controllers.NewHealthControllerImpl(healthService)
+	return healthControllerImpl
+}
+
+func CreateAdminController() controllers.AdminController {
+	adminService := CreateAdminService()
+	adminControllerImpl := controllers.NewAdminControllerImpl(adminService)
+	return adminControllerImpl
+}
+
+func CreateStatisticsController() controllers.StatisticsController {
+	statisticsService := CreateStatisticsService()
+	statisticsControllerImpl := controllers.NewStatisticsControllerImpl(statisticsService)
+	return statisticsControllerImpl
+}
+
 func CreateGameService() service.GameService {
 	gameDao := CreateGameDao()
 	userDao := CreateUserDao()
 	gameEventDao := CreateGameEventDao()
 	outgoingEventHandler := CreateOutgoingEventHandler()
-	gameServiceImpl := service.NewGameServiceImpl(gameDao, userDao, gameEventDao, outgoingEventHandler)
+	ratingService := CreateRatingService()
+	leaderboardService := CreateLeaderboardService()
+	statisticsService := CreateStatisticsService()
+	lobbyService := CreateLobbyService()
+	inviteService := CreateInviteService()
+	gameStateMachine := CreateGameStateMachine()
+	chatService := CreateChatService()
+	webhookService := CreateWebhookService()
+	blockService := CreateBlockService()
+	gameServiceImpl := service.NewGameServiceImpl(gameDao, userDao, gameEventDao, outgoingEventHandler, ratingService, leaderboardService, statisticsService, lobbyService, inviteService, gameStateMachine, chatService, webhookService, blockService)
 	return gameServiceImpl
 }
 
 func CreateUserService() service.UserService {
 	userDao := CreateUserDao()
-	userServiceImpl := service.NewUserServiceImpl(userDao)
+	ratingHistoryDao := CreateRatingHistoryDao()
+	userServiceImpl := service.NewUserServiceImpl(userDao, ratingHistoryDao)
 	return userServiceImpl
 }
 
-func CreateIncomingEventHandler() incoming_events.IncomingEventHandler {
+func CreateRatingService() service.RatingService {
+	userDao := CreateUserDao()
+	ratingHistoryDao := CreateRatingHistoryDao()
+	ratingServiceImpl := service.NewRatingServiceImpl(userDao, ratingHistoryDao)
+	return ratingServiceImpl
+}
+
+func CreateAuthService() service.AuthService {
+	otpDao := CreateOtpDao()
+	sessionDao := CreateSessionDao()
+	userDao := CreateUserDao()
+	smsSender := CreateSmsSender()
+	authServiceImpl := service.NewAuthServiceImpl(otpDao, sessionDao, userDao, smsSender)
+	return authServiceImpl
+}
+
+func CreateMatchmakingService() service.MatchmakingService {
+	gameService := CreateGameService()
+	userDao := CreateUserDao()
+	blockService := CreateBlockService()
+	outgoingEventHandler := CreateOutgoingEventHandler()
+	matchmakingServiceImpl := service.NewMatchmakingServiceImpl(gameService, userDao, blockService, outgoingEventHandler)
+	return matchmakingServiceImpl
+}
+
+func CreateLeaderboardService() service.LeaderboardService {
+	leaderboardDao := CreateLeaderboardDao()
+	seasonArchiveDao := CreateSeasonArchiveDao()
+	userDao := CreateUserDao()
+	leaderboardServiceImpl := service.NewLeaderboardServiceImpl(leaderboardDao, seasonArchiveDao, userDao)
+	return leaderboardServiceImpl
+}
+
+func CreateStatisticsService() service.StatisticsService {
+	userStatsDao := CreateUserStatsDao()
+	gameEventDao := CreateGameEventDao()
+	statisticsServiceImpl := service.NewStatisticsServiceImpl(userStatsDao, gameEventDao)
+	return statisticsServiceImpl
+}
+
+func CreateLobbyService() service.LobbyService {
+	gameDao := CreateGameDao()
+	userDao := CreateUserDao()
+	blockService := CreateBlockService()
+	outgoingEventHandler := CreateOutgoingEventHandler()
+	lobbyServiceImpl := service.NewLobbyServiceImpl(gameDao, userDao, blockService, outgoingEventHandler)
+	return lobbyServiceImpl
+}
+
+func CreateInviteService() service.InviteService {
+	inviteDao := CreateInviteDao()
+	gameDao := CreateGameDao()
+	gameStateMachine := CreateGameStateMachine()
+	inviteServiceImpl := service.NewInviteServiceImpl(inviteDao, gameDao, gameStateMachine)
+	return inviteServiceImpl
+}
+
+func CreateGameStateMachine() service.GameStateMachine {
+	gameDao := CreateGameDao()
+	gameEventDao := CreateGameEventDao()
+	gameStateMachineImpl := service.NewGameStateMachineImpl(gameDao, gameEventDao)
+	return gameStateMachineImpl
+}
+
+func CreateHealthService() service.HealthService {
+	healthServiceImpl := service.NewHealthServiceImpl()
+	return healthServiceImpl
+}
+
+func CreateAdminService() service.AdminService {
+	serverSettingDao := CreateServerSettingDao()
+	adminAuditDao := CreateAdminAuditDao()
 	gameDao := CreateGameDao()
-	incomingEventHandlerImpl := incoming_events.NewIncomingEventHandlerImpl(gameDao)
+	gameEventDao := CreateGameEventDao()
+	userDao := CreateUserDao()
+	gameService := CreateGameService()
+	webhookService := CreateWebhookService()
+	reportService := CreateReportService()
+	antiCheatService := CreateAntiCheatService()
+	outgoingEventHandler := CreateOutgoingEventHandler()
+	adminServiceImpl := service.NewAdminServiceImpl(serverSettingDao, adminAuditDao, gameDao, gameEventDao, userDao, gameService, webhookService, reportService, antiCheatService, outgoingEventHandler)
+	return adminServiceImpl
+}
+
+func CreateWebhookService() service.WebhookService {
+	webhookDao := CreateWebhookDao()
+	webhookServiceImpl := service.NewWebhookServiceImpl(webhookDao)
+	return webhookServiceImpl
+}
+
+func CreateBlockService() service.BlockService {
+	userBlockDao := CreateUserBlockDao()
+	userDao := CreateUserDao()
+	blockServiceImpl := service.NewBlockServiceImpl(userBlockDao, userDao)
+	return blockServiceImpl
+}
+
+func CreateReportService() service.ReportService {
+	userReportDao := CreateUserReportDao()
+	gameDao := CreateGameDao()
+	chatDao := CreateChatDao()
+	userDao := CreateUserDao()
+	reportServiceImpl := service.NewReportServiceImpl(userReportDao, gameDao, chatDao, userDao)
+	return reportServiceImpl
+}
+
+func CreateAntiCheatService() service.AntiCheatService {
+	gameDao := CreateGameDao()
+	gameEventDao := CreateGameEventDao()
+	cheatFlagDao := CreateCheatFlagDao()
+	antiCheatServiceImpl := service.NewAntiCheatServiceImpl(gameDao, gameEventDao, cheatFlagDao)
+	return antiCheatServiceImpl
+}
+
+func CreateChatService() service.ChatService {
+	chatDao := CreateChatDao()
+	gameDao := CreateGameDao()
+	gameStateMachine := CreateGameStateMachine()
+	outgoingEventHandler := CreateOutgoingEventHandler()
+	filter := CreateProfanityFilter()
+	chatServiceImpl := service.NewChatServiceImpl(chatDao, gameDao, gameStateMachine, outgoingEventHandler, filter)
+	return chatServiceImpl
+}
+
+func CreateProfanityFilter() profanity.Filter {
+	filter := profanity.NewFilter()
+	return filter
+}
+
+func CreateSmsSender() sms.SmsSender {
+	smsSender := sms.NewSmsSender()
+	return smsSender
+}
+
+func CreateIncomingEventHandler() incoming_events.IncomingEventHandler {
+	chatService := CreateChatService()
+	outgoingEventHandler := CreateOutgoingEventHandler()
+	incomingEventHandlerImpl := incoming_events.NewIncomingEventHandlerImpl(chatService, outgoingEventHandler)
 	return incomingEventHandlerImpl
 }
 
@@ -75,3 +267,73 @@ func CreateGameEventDao() dao.GameEventDao {
 	gameEventDaoImpl := dao.NewEventGameDaoImpl()
 	return gameEventDaoImpl
 }
+
+func CreateOtpDao() dao.OtpDao {
+	otpDaoImpl := dao.NewOtpDaoImpl()
+	return otpDaoImpl
+}
+
+func CreateSessionDao() dao.SessionDao {
+	sessionDaoImpl := dao.NewSessionDaoImpl()
+	return sessionDaoImpl
+}
+
+func CreateRatingHistoryDao() dao.RatingHistoryDao {
+	ratingHistoryDaoImpl := dao.NewRatingHistoryDaoImpl()
+	return ratingHistoryDaoImpl
+}
+
+func CreateLeaderboardDao() dao.LeaderboardDao {
+	leaderboardDaoImpl := dao.NewLeaderboardDaoImpl()
+	return leaderboardDaoImpl
+}
+
+func CreateSeasonArchiveDao() dao.SeasonArchiveDao {
+	seasonArchiveDaoImpl := dao.NewSeasonArchiveDaoImpl()
+	return seasonArchiveDaoImpl
+}
+
+func CreateUserStatsDao() dao.UserStatsDao {
+	userStatsDaoImpl := dao.NewUserStatsDaoImpl()
+	return userStatsDaoImpl
+}
+
+func CreateServerSettingDao() dao.ServerSettingDao {
+	serverSettingDaoImpl := dao.NewServerSettingDaoImpl()
+	return serverSettingDaoImpl
+}
+
+func CreateAdminAuditDao() dao.AdminAuditDao {
+	adminAuditDaoImpl := dao.NewAdminAuditDaoImpl()
+	return adminAuditDaoImpl
+}
+
+func CreateUserBlockDao() dao.UserBlockDao {
+	userBlockDaoImpl := dao.NewUserBlockDaoImpl()
+	return userBlockDaoImpl
+}
+
+func CreateUserReportDao() dao.UserReportDao {
+	userReportDaoImpl := dao.NewUserReportDaoImpl()
+	return userReportDaoImpl
+}
+
+func CreateCheatFlagDao() dao.CheatFlagDao {
+	cheatFlagDaoImpl := dao.NewCheatFlagDaoImpl()
+	return cheatFlagDaoImpl
+}
+
+func CreateWebhookDao() dao.WebhookDao {
+	webhookDaoImpl := dao.NewWebhookDaoImpl()
+	return webhookDaoImpl
+}
+
+func CreateChatDao() dao.ChatDao {
+	chatDaoImpl := dao.NewChatDaoImpl()
+	return chatDaoImpl
+}
+
+func CreateInviteDao() dao.InviteDao {
+	inviteDaoImpl := dao.NewInviteDaoImpl()
+	return inviteDaoImpl
+}
@@ -32,43 +32,50 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/game": {
-            "post": {
-                "description": "create a new battleship game instance",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/admin/audit": {
+            "get": {
+                "description": "Get the admin actions from the newest, of one target or of all targets",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Game"
+                    "Admin"
                 ],
-                "summary": "Create game",
+                "summary": "Get admin audit",
                 "parameters": [
                     {
-                        "description": "Create Game Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateGameRequest"
-                        }
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game, user or webhook delivery id",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Create Game Response",
+                        "description": "Get Audit Response",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetGameResponse"
+                            "$ref": "#/definitions/dto.GetAuditResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/game/change-turn": {
+        "/api/admin/broadcast": {
             "post": {
-                "description": "Change turn",
+                "description": "Send a notice to all sockets connected to this server",
                 "consumes": [
                     "application/json"
                 ],
@@ -76,33 +83,87 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Game"
+                    "Admin"
                 ],
-                "summary": "Change turn",
+                "summary": "Broadcast notice",
                 "parameters": [
                     {
-                        "description": "Change Turn Request",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the admin, it is recorded in the audit",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Broadcast Request",
+                        "name": "BroadcastRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ChangeTurnRequest"
+                            "$ref": "#/definitions/dto.BroadcastRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Change Turn Response",
+                        "description": "Broadcast Response",
                         "schema": {
-                            "$ref": "#/definitions/dto.ChangeTurnResponse"
+                            "$ref": "#/definitions/dto.BroadcastResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/game/explode": {
+        "/api/admin/cheat-flag": {
+            "get": {
+                "description": "Get the flags of the anti-cheat analysis of a status from the oldest with their evidence, pending flags are the review queue",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get cheat flags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending (default), confirmed or dismissed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Cheat Flags Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetCheatFlagsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/cheat-flag/review": {
             "post": {
-                "description": "Explode a slot",
+                "description": "Confirm or dismiss a pending cheat flag, the user is not banned by the review",
                 "consumes": [
                     "application/json"
                 ],
@@ -110,33 +171,46 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Game"
+                    "Admin"
                 ],
-                "summary": "Explode a slot",
+                "summary": "Review cheat flag",
                 "parameters": [
                     {
-                        "description": "Explode request",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the admin, it is recorded in the audit",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Review Cheat Flag Request",
+                        "name": "ReviewCheatFlagRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ExplodeRequest"
+                            "$ref": "#/definitions/dto.ReviewCheatFlagRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Explode Response",
+                        "description": "Review Cheat Flag Response",
                         "schema": {
-                            "$ref": "#/definitions/dto.ExplodeResponse"
+                            "$ref": "#/definitions/dto.ReviewCheatFlagResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/game/join": {
+        "/api/admin/game/cancel": {
             "post": {
-                "description": "Join to a battleship game instance",
+                "description": "Cancel a game that is not started yet with a reason",
                 "consumes": [
                     "application/json"
                 ],
@@ -144,33 +218,46 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Game"
+                    "Admin"
                 ],
-                "summary": "Join game",
+                "summary": "Cancel game",
                 "parameters": [
                     {
-                        "description": "Join Game Request",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the admin, it is recorded in the audit",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Cancel Game Request",
+                        "name": "CancelGameAdminRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.JoinGameRequest"
+                            "$ref": "#/definitions/dto.CancelGameAdminRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Get Game response",
+                        "description": "Admin Game Response",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetGameResponse"
+                            "$ref": "#/definitions/dto.AdminGameResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/game/move-ship": {
+        "/api/admin/game/finish": {
             "post": {
-                "description": "Move ship to new location",
+                "description": "Finish a started or paused game with a reason, the game has no winner when winner_user_id is empty",
                 "consumes": [
                     "application/json"
                 ],
@@ -178,67 +265,144 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Game"
+                    "Admin"
                 ],
-                "summary": "Move ship",
+                "summary": "Finish game",
                 "parameters": [
                     {
-                        "description": "Move Ship Request",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the admin, it is recorded in the audit",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Finish Game Request",
+                        "name": "FinishGameRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.MoveShipRequest"
+                            "$ref": "#/definitions/dto.FinishGameRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Move Ship Response",
+                        "description": "Admin Game Response",
                         "schema": {
-                            "$ref": "#/definitions/dto.MoveShipResponse"
+                            "$ref": "#/definitions/dto.AdminGameResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/game/reveal": {
-            "post": {
-                "description": "Reveal enemy fields",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/admin/game/live": {
+            "get": {
+                "description": "Get joined, started and paused games with the socket status of their sides on this server",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Game"
+                    "Admin"
                 ],
-                "summary": "Reveal enemy fields",
+                "summary": "Get live games",
                 "parameters": [
                     {
-                        "description": "Reveal enemy fields request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Live Games Response",
                         "schema": {
-                            "$ref": "#/definitions/dto.RevealEnemyFieldsRequest"
+                            "$ref": "#/definitions/dto.GetLiveGamesResponse"
                         }
                     }
+                }
+            }
+        },
+        "/api/admin/game/{game_id}": {
+            "get": {
+                "description": "Get the game as it is stored, with the ships of both sides, and its event log",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get game as admin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Game Id",
+                        "name": "game_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Reveal Enemy Fields Response",
+                        "description": "Admin Game Response",
                         "schema": {
-                            "$ref": "#/definitions/dto.RevealEnemyFieldsResponse"
+                            "$ref": "#/definitions/dto.AdminGameResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/game/submit-ships": {
-            "post": {
-                "description": "submit ship locations",
+        "/api/admin/maintenance": {
+            "get": {
+                "description": "Get maintenance mode of the server",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get maintenance mode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Maintenance Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MaintenanceResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "In maintenance mode new games are rejected and games in progress go on, the mode is kept in the db",
                 "consumes": [
                     "application/json"
                 ],
@@ -246,72 +410,87 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Game"
+                    "Admin"
                 ],
-                "summary": "submit ship locations",
+                "summary": "Set maintenance mode",
                 "parameters": [
                     {
-                        "description": "Submit ships Request",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the admin, it is recorded in the audit",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Set Maintenance Request",
+                        "name": "SetMaintenanceRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.SubmitShipsLocationsRequest"
+                            "$ref": "#/definitions/dto.SetMaintenanceRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Submit Ships Locations Response",
+                        "description": "Maintenance Response",
                         "schema": {
-                            "$ref": "#/definitions/dto.SubmitShipsLocationsResponse"
+                            "$ref": "#/definitions/dto.MaintenanceResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/game/{game_id}": {
+        "/api/admin/report": {
             "get": {
-                "description": "Get game info",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get the user reports of a status from the oldest, pending reports are the moderation queue",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Game"
+                    "Admin"
                 ],
-                "summary": "Get game",
+                "summary": "Get reports",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Game Id",
-                        "name": "game_id",
-                        "in": "path",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "User Id",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
+                        "description": "pending (default), confirmed or dismissed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Get Game Response",
+                        "description": "Get Reports Response",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetGameResponse"
+                            "$ref": "#/definitions/dto.GetReportsResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/user": {
+        "/api/admin/report/review": {
             "post": {
-                "description": "create a new user",
+                "description": "Confirm or dismiss a pending report, the reported user is restricted after enough confirmed reports",
                 "consumes": [
                     "application/json"
                 ],
@@ -319,33 +498,46 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Admin"
                 ],
-                "summary": "Create user",
+                "summary": "Review report",
                 "parameters": [
                     {
-                        "description": "Create User Request",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the admin, it is recorded in the audit",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Review Report Request",
+                        "name": "ReviewReportRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateUserRequest"
+                            "$ref": "#/definitions/dto.ReviewReportRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Create User Response",
+                        "description": "Review Report Response",
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateUserResponse"
+                            "$ref": "#/definitions/dto.ReviewReportResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/user/{user_id}": {
-            "get": {
-                "description": "Get user info",
+        "/api/admin/user/ban": {
+            "post": {
+                "description": "Ban a user from logging in and starting games, the ban is permanent when duration_hours is 0",
                 "consumes": [
                     "application/json"
                 ],
@@ -353,93 +545,2855 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Admin"
                 ],
-                "summary": "Get user",
+                "summary": "Ban user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": " ",
-                        "name": "user_id",
-                        "in": "path",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the admin, it is recorded in the audit",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Ban User Request",
+                        "name": "BanUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BanUserRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "UserDto",
+                        "description": "User Ban Response",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserDto"
+                            "$ref": "#/definitions/dto.UserBanResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.BattleError": {
-            "type": "object",
-            "properties": {
-                "error_code": {
-                    "type": "integer"
-                },
-                "error_data": {
+        },
+        "/api/admin/user/unban": {
+            "post": {
+                "description": "Remove the ban of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Unban user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the admin, it is recorded in the audit",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Unban User Request",
+                        "name": "UnbanUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UnbanUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Ban Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserBanResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/webhook/redeliver": {
+            "post": {
+                "description": "Send a webhook delivery of the delivery log again, it gets a new set of retries. A delivery that is being attempted is refused with a conflict",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Redeliver webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the admin, it is recorded in the audit",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Redeliver Webhook Request",
+                        "name": "RedeliverWebhookRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RedeliverWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Redeliver Webhook Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RedeliverWebhookResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/otp": {
+            "post": {
+                "description": "send a one time password to the mobile number",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Request OTP",
+                "parameters": [
+                    {
+                        "description": "Request OTP Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RequestOtpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Request OTP Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RequestOtpResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/otp/verify": {
+            "post": {
+                "description": "verify the one time password and create a session, user is created if mobile is new",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Verify OTP",
+                "parameters": [
+                    {
+                        "description": "Verify OTP Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyOtpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Verify OTP Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyOtpResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game": {
+            "post": {
+                "description": "create a new battleship game instance",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Create game",
+                "parameters": [
+                    {
+                        "description": "Create Game Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateGameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Create Game Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetGameResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/cancel": {
+            "post": {
+                "description": "Cancel a game that is not started yet, only creator of the game can cancel it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Cancel game",
+                "parameters": [
+                    {
+                        "description": "Cancel Game Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancelGameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Game Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetGameResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/change-turn": {
+            "post": {
+                "description": "Change turn",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Change turn",
+                "parameters": [
+                    {
+                        "description": "Change Turn Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangeTurnRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Change Turn Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangeTurnResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/explode": {
+            "post": {
+                "description": "Explode a slot",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Explode a slot",
+                "parameters": [
+                    {
+                        "description": "Explode request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExplodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Explode Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExplodeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/invite": {
+            "post": {
+                "description": "Create a new invite code for a game that is waiting for the second side, only creator of the game can invite",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Create invite",
+                "parameters": [
+                    {
+                        "description": "Create Invite Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateInviteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invite Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.InviteResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/invite/revoke": {
+            "post": {
+                "description": "Revoke an invite code, only creator of the invite can revoke it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Revoke invite",
+                "parameters": [
+                    {
+                        "description": "Revoke Invite Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevokeInviteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revoke Invite Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevokeInviteResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/join": {
+            "post": {
+                "description": "Join to a battleship game instance by game id or invite code, private games need the invite code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Join game",
+                "parameters": [
+                    {
+                        "description": "Join Game Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.JoinGameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Game response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetGameResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/move-ship": {
+            "post": {
+                "description": "Move ship to new location",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Move ship",
+                "parameters": [
+                    {
+                        "description": "Move Ship Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MoveShipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Move Ship Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MoveShipResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/pause": {
+            "post": {
+                "description": "Ask the other side to pause a started game, turn timers are frozen while the game is paused",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Request pause",
+                "parameters": [
+                    {
+                        "description": "Pause Game Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PauseGameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pause Game Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PauseGameResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/pause/accept": {
+            "post": {
+                "description": "Accept the pause request of the other side, the game is resumed automatically after pause_deadline",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Accept pause",
+                "parameters": [
+                    {
+                        "description": "Accept Pause Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AcceptPauseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pause Game Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PauseGameResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/resume": {
+            "post": {
+                "description": "Ask to resume a paused game, the game is resumed when both sides have asked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Resume game",
+                "parameters": [
+                    {
+                        "description": "Resume Game Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResumeGameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Pause Game Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PauseGameResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/reveal": {
+            "post": {
+                "description": "Reveal enemy fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Reveal enemy fields",
+                "parameters": [
+                    {
+                        "description": "Reveal enemy fields request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevealEnemyFieldsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reveal Enemy Fields Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevealEnemyFieldsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/submit-ships": {
+            "post": {
+                "description": "submit ship locations. In commit-reveal games commitment is the hex sha256 of \"i1,i2,...,i10:salt\" with the indexes sorted ascending, and salt is revealed to both sides when the game is finished. toss_seed is the random value of the side for the first turn coin toss, the proof is in the toss field of the game",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "submit ship locations",
+                "parameters": [
+                    {
+                        "description": "Submit ships Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubmitShipsLocationsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Submit Ships Locations Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubmitShipsLocationsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/game/{game_id}": {
+            "get": {
+                "description": "Get game info",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Get game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game Id",
+                        "name": "game_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User Id",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Game Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetGameResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/leaderboard": {
+            "get": {
+                "description": "Get a page of all-time, seasonal or weekly leaderboard, my_position is filled when user_id is given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leaderboard"
+                ],
+                "summary": "Get leaderboard",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "all_time, season or weekly",
+                        "name": "board",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Season Id, current season if empty",
+                        "name": "season_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page, starts from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User Id",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Leaderboard Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetLeaderboardResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/leaderboard/seasons": {
+            "get": {
+                "description": "Get configured seasons, winners of archived seasons are included",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leaderboard"
+                ],
+                "summary": "Get seasons",
+                "responses": {
+                    "200": {
+                        "description": "Get Seasons Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetSeasonsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/lobby": {
+            "get": {
+                "description": "Get public games that are waiting for the second side, from the newest. Live updates are sent on /socket/lobby",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Get lobby",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of games",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User Id, games of users blocked with the user are left out",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Lobby Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetLobbyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/matchmaking/cancel": {
+            "post": {
+                "description": "leave the matchmaking queue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Matchmaking"
+                ],
+                "summary": "Cancel matchmaking",
+                "parameters": [
+                    {
+                        "description": "Cancel Matchmaking Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancelMatchmakingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cancel Matchmaking Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancelMatchmakingResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/matchmaking/enqueue": {
+            "post": {
+                "description": "wait for a random opponent with the same settings, match_found event is sent on /socket/user when paired",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Matchmaking"
+                ],
+                "summary": "Enqueue for matchmaking",
+                "parameters": [
+                    {
+                        "description": "Enqueue Matchmaking Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.EnqueueMatchmakingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Enqueue Matchmaking Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.EnqueueMatchmakingResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/report": {
+            "post": {
+                "description": "Report the opponent of a game, the chat of the game is attached to the report",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Report user",
+                "parameters": [
+                    {
+                        "description": "Report User Request",
+                        "name": "ReportUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReportUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Report User Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReportUserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user": {
+            "post": {
+                "description": "create a new user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Create user",
+                "parameters": [
+                    {
+                        "description": "Create User Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Create User Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateUserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{user_id}": {
+            "get": {
+                "description": "Get user info",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": " ",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "UserDto",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserDto"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{user_id}/block": {
+            "post": {
+                "description": "Block a user, the users are never paired by matchmaking and cannot join games of each other",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Block user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": " ",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Block User Request",
+                        "name": "BlockUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BlockUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Blocks Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetBlocksResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{user_id}/blocks": {
+            "get": {
+                "description": "Get the users blocked by the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get blocked users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": " ",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Blocks Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetBlocksResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{user_id}/games": {
+            "get": {
+                "description": "Get game history of user from the newest, next_cursor is empty on the last page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Get user games",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User Id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "init, joined, start or finished",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "win or loss",
+                        "name": "result",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "create date from, RFC3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "create date to, RFC3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opponent User Id",
+                        "name": "opponent_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 20 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get User Games Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetUserGamesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{user_id}/rating-history": {
+            "get": {
+                "description": "Get the latest rating changes of user in ranked games",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user rating history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": " ",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rating History Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RatingHistoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{user_id}/stats": {
+            "get": {
+                "description": "Get statistics of user finished games",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User Id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Stats Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserStatsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{user_id}/unblock": {
+            "post": {
+                "description": "Remove a block of the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Unblock user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": " ",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Block User Request",
+                        "name": "BlockUserRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BlockUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Blocks Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetBlocksResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/livez": {
+            "get": {
+                "description": "Returns ok while the process serves http, dependencies are not checked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Health Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Checks mongodb, the connection registry, scheduled jobs and the server mode. It returns 503 if any check fails",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Health Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Health Response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HealthResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AcceptPauseRequest": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AdminAuditDto": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "remote_ip": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AdminGameResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GameEvent"
+                    }
+                },
+                "game": {
+                    "type": "object",
+                    "$ref": "#/definitions/model.Game"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.BanUserRequest": {
+            "type": "object",
+            "properties": {
+                "duration_hours": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BattleError": {
+            "type": "object",
+            "properties": {
+                "error_code": {
+                    "type": "integer"
+                },
+                "error_data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "error_message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BlockUserRequest": {
+            "type": "object",
+            "properties": {
+                "blocked_user_id": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BroadcastRequest": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BroadcastResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "sockets": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CancelGameAdminRequest": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CancelGameRequest": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CancelMatchmakingRequest": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CancelMatchmakingResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ChangeTurnRequest": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ChangeTurnResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ChatDto": {
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ChatMessageDto"
+                    }
+                },
+                "muted": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ChatMessageDto": {
+            "type": "object",
+            "properties": {
+                "emote": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CheatEvidenceDto": {
+            "type": "object",
+            "properties": {
+                "blind_hits": {
+                    "type": "integer"
+                },
+                "blind_shots": {
+                    "type": "integer"
+                },
+                "expected_hits": {
+                    "type": "number"
+                },
+                "fast_shots": {
+                    "type": "integer"
+                },
+                "game_id": {
+                    "type": "string"
+                },
+                "games": {
+                    "type": "integer"
+                },
+                "median_reaction_ms": {
+                    "type": "integer"
+                },
+                "min_reaction_ms": {
+                    "type": "integer"
+                },
+                "quick_losses": {
+                    "type": "integer"
+                },
+                "shots": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "wins": {
+                    "type": "integer"
+                },
+                "z_score": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.CheatFlagDto": {
+            "type": "object",
+            "properties": {
+                "create_date": {
+                    "type": "string"
+                },
+                "evidence": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CheatEvidenceDto"
+                    }
+                },
+                "flag_id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "other_user_id": {
+                    "type": "string"
+                },
+                "review_date": {
+                    "type": "string"
+                },
+                "review_note": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "update_date": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CoinTossDto": {
+            "type": "object",
+            "properties": {
+                "first_turn_user_id": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "seeds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TossSeedDto"
+                    }
+                },
+                "server_commitment": {
+                    "type": "string"
+                },
+                "server_seed": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateGameRequest": {
+            "type": "object",
+            "properties": {
+                "commit_reveal": {
+                    "type": "boolean"
+                },
+                "move_timeout": {
+                    "type": "integer"
+                },
+                "passcode": {
+                    "type": "string"
+                },
+                "public": {
+                    "type": "boolean"
+                },
+                "ranked": {
+                    "type": "boolean"
+                },
+                "toss_commitment": {
+                    "description": "hex sha256 of the toss seed sent with the ships",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateInviteRequest": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateUserRequest": {
+            "type": "object",
+            "properties": {
+                "mobile": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateUserResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.EnqueueMatchmakingRequest": {
+            "type": "object",
+            "properties": {
+                "board_size": {
+                    "type": "integer"
+                },
+                "commit_reveal": {
+                    "type": "boolean"
+                },
+                "move_timeout": {
+                    "type": "integer"
+                },
+                "ranked": {
+                    "type": "boolean"
+                },
+                "ruleset": {
+                    "type": "string"
+                },
+                "toss_commitment": {
+                    "description": "hex sha256 of the toss seed sent with the ships",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.EnqueueMatchmakingResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "expire_date": {
+                    "type": "string"
+                },
+                "game": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.GameDto"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ExplodeRequest": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ExplodeResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "has_ship": {
+                    "type": "boolean"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.FinishGameRequest": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "winner_user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GameDto": {
+            "type": "object",
+            "properties": {
+                "board_size": {
+                    "type": "integer"
+                },
+                "commit_reveal": {
+                    "type": "boolean"
+                },
+                "create_date": {
+                    "type": "string"
+                },
+                "end_reason": {
+                    "type": "string"
+                },
+                "has_passcode": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "move_timeout_sec": {
+                    "type": "integer"
+                },
+                "other_side_joined": {
+                    "type": "boolean"
+                },
+                "pause_deadline": {
+                    "type": "string"
+                },
+                "placements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PlacementDto"
+                    }
+                },
+                "public": {
+                    "type": "boolean"
+                },
+                "ranked": {
+                    "type": "boolean"
+                },
+                "ruleset": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.GameState"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "toss": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.CoinTossDto"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "winner_user": {
+                    "type": "string"
+                },
+                "your_turn": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.GameState": {
+            "type": "object",
+            "properties": {
+                "enemy_ground": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "enemy_revealed_ships": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "own_ground": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "own_ships": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                }
+            }
+        },
+        "dto.GameSummaryDto": {
+            "type": "object",
+            "properties": {
+                "create_date": {
+                    "type": "string"
+                },
+                "duration_sec": {
+                    "type": "integer"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "end_reason": {
+                    "type": "string"
+                },
+                "game_id": {
+                    "type": "string"
+                },
+                "move_count": {
+                    "type": "integer"
+                },
+                "opponent_id": {
+                    "type": "string"
+                },
+                "opponent_name": {
+                    "type": "string"
+                },
+                "ranked": {
+                    "type": "boolean"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetAuditResponse": {
+            "type": "object",
+            "properties": {
+                "audits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AdminAuditDto"
+                    }
+                },
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.GetBlocksResponse": {
+            "type": "object",
+            "properties": {
+                "blocks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserBlockDto"
+                    }
+                },
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.GetCheatFlagsResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "flags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CheatFlagDto"
+                    }
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.GetGameResponse": {
+            "type": "object",
+            "properties": {
+                "chat": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.ChatDto"
+                },
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "game": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.GameDto"
+                },
+                "invite": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.InviteDto"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.GetLeaderboardResponse": {
+            "type": "object",
+            "properties": {
+                "board": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LeaderboardEntryDto"
+                    }
+                },
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "my_position": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.LeaderboardEntryDto"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GetLiveGamesResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "games": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LiveGameDto"
+                    }
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.GetLobbyResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "games": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LobbyGameDto"
+                    }
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.GetReportsResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserReportDto"
+                    }
+                }
+            }
+        },
+        "dto.GetSeasonsResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SeasonDto"
+                    }
+                }
+            }
+        },
+        "dto.GetUserGamesResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "games": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.GameSummaryDto"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.HealthCheck": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.HealthCheck"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.InviteDto": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "expire_date": {
+                    "type": "string"
+                },
+                "game_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.InviteResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "invite": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.InviteDto"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.JoinGameRequest": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "invite_code": {
+                    "type": "string"
+                },
+                "passcode": {
+                    "type": "string"
+                },
+                "toss_commitment": {
+                    "description": "hex sha256 of the toss seed sent with the ships",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LeaderboardEntryDto": {
+            "type": "object",
+            "properties": {
+                "losses": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "wins": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LiveGameDto": {
+            "type": "object",
+            "properties": {
+                "create_date": {
+                    "type": "string"
+                },
+                "game_id": {
+                    "type": "string"
+                },
+                "last_move_time": {
+                    "type": "string"
+                },
+                "move_count": {
+                    "type": "integer"
+                },
+                "ranked": {
+                    "type": "boolean"
+                },
+                "side_1_connected": {
+                    "type": "boolean"
+                },
+                "side_1_disconnected_since": {
+                    "type": "string"
+                },
+                "side_1_user_id": {
+                    "type": "string"
+                },
+                "side_2_connected": {
+                    "type": "boolean"
+                },
+                "side_2_disconnected_since": {
+                    "type": "string"
+                },
+                "side_2_user_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "turn": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LobbyGameDto": {
+            "type": "object",
+            "properties": {
+                "age_sec": {
+                    "type": "integer"
+                },
+                "board_size": {
+                    "type": "integer"
+                },
+                "create_date": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "string"
+                },
+                "creator_name": {
+                    "type": "string"
+                },
+                "creator_rating": {
+                    "type": "integer"
+                },
+                "game_id": {
+                    "type": "string"
+                },
+                "has_passcode": {
+                    "type": "boolean"
+                },
+                "move_timeout_sec": {
+                    "type": "integer"
+                },
+                "ranked": {
+                    "type": "boolean"
+                },
+                "ruleset": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MaintenanceResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "update_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MoveShipRequest": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "new_ship_index": {
+                    "type": "integer"
+                },
+                "old_ship_index": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MoveShipResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.PauseGameRequest": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PauseGameResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "pause_deadline": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PlacementDto": {
+            "type": "object",
+            "properties": {
+                "commitment": {
+                    "type": "string"
+                },
+                "log": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PlacementEventDto"
+                    }
+                },
+                "salt": {
+                    "type": "string"
+                },
+                "ships": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PlacementEventDto": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "integer"
+                },
+                "hit": {
+                    "type": "boolean"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "revealed_cells": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "revealed_ships": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "time": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RatingHistoryDto": {
+            "type": "object",
+            "properties": {
+                "end_reason": {
+                    "type": "string"
+                },
+                "game_id": {
+                    "type": "string"
+                },
+                "new_rating": {
+                    "type": "integer"
+                },
+                "old_rating": {
+                    "type": "integer"
+                },
+                "opponent_user_id": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "won": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.RatingHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RatingHistoryDto"
+                    }
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.RedeliverWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "delivery_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RedeliverWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "delivery": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.WebhookDeliveryDto"
+                },
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ReportUserRequest": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "game_id": {
+                    "type": "string"
+                },
+                "reported_user_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReportUserResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "report_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RequestOtpRequest": {
+            "type": "object",
+            "properties": {
+                "mobile": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RequestOtpResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "expire_sec": {
+                    "type": "integer"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "resend_interval_sec": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ResumeGameRequest": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RevealEnemyFieldsRequest": {
+            "type": "object",
+            "properties": {
+                "game_id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RevealEnemyFieldsResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "revealed_ship_indexes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.ReviewCheatFlagRequest": {
+            "type": "object",
+            "properties": {
+                "confirmed": {
+                    "type": "boolean"
+                },
+                "flag_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReviewCheatFlagResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "flag": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.CheatFlagDto"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ReviewReportRequest": {
+            "type": "object",
+            "properties": {
+                "confirmed": {
+                    "type": "boolean"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "report_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReviewReportResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "report": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.UserReportDto"
+                },
+                "restricted": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.RevokeInviteRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RevokeInviteResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.SeasonDto": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "archived": {
+                    "type": "boolean"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "winners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LeaderboardEntryDto"
                     }
+                }
+            }
+        },
+        "dto.SetMaintenanceRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
                 },
-                "error_message": {
+                "message": {
                     "type": "string"
                 }
             }
         },
-        "dto.ChangeTurnRequest": {
+        "dto.SubmitShipsLocationsRequest": {
             "type": "object",
             "properties": {
+                "commitment": {
+                    "type": "string"
+                },
                 "game_id": {
                     "type": "string"
                 },
+                "salt": {
+                    "type": "string"
+                },
+                "ships_indexes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "toss_seed": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.ChangeTurnResponse": {
+        "dto.SubmitShipsLocationsResponse": {
             "type": "object",
             "properties": {
                 "error": {
                     "type": "object",
                     "$ref": "#/definitions/dto.BattleError"
                 },
+                "game_status": {
+                    "type": "string"
+                },
                 "ok": {
                     "type": "boolean"
+                },
+                "turn": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.CreateGameRequest": {
+        "dto.TossSeedDto": {
             "type": "object",
             "properties": {
-                "move_timeout": {
-                    "type": "integer"
+                "commitment": {
+                    "type": "string"
+                },
+                "seed": {
+                    "type": "string"
                 },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.CreateUserRequest": {
+        "dto.UnbanUserRequest": {
             "type": "object",
             "properties": {
-                "mobile": {
+                "reason": {
                     "type": "string"
                 },
-                "name": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.CreateUserResponse": {
+        "dto.UserBanDto": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "until": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserBanResponse": {
+            "type": "object",
+            "properties": {
+                "ban": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.UserBanDto"
+                },
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserBlockDto": {
+            "type": "object",
+            "properties": {
+                "blocked_user_id": {
+                    "type": "string"
+                },
+                "create_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserDto": {
             "type": "object",
             "properties": {
                 "error": {
@@ -449,243 +3403,458 @@ var doc = `{
                 "id": {
                     "type": "string"
                 },
+                "mobile": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "provisional": {
+                    "type": "boolean"
+                },
+                "rated_games": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.UserReportDto": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "chat_log": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ChatMessageDto"
+                    }
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "create_date": {
+                    "type": "string"
+                },
+                "game_id": {
+                    "type": "string"
+                },
+                "report_id": {
+                    "type": "string"
+                },
+                "reported_user_id": {
+                    "type": "string"
+                },
+                "reporter_id": {
+                    "type": "string"
+                },
+                "review_date": {
+                    "type": "string"
+                },
+                "review_note": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserStatsResponse": {
+            "type": "object",
+            "properties": {
+                "accuracy": {
+                    "type": "number"
+                },
+                "average_game_duration_sec": {
+                    "type": "number"
+                },
+                "current_win_streak": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "object",
+                    "$ref": "#/definitions/dto.BattleError"
+                },
+                "games_played": {
+                    "type": "integer"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "longest_win_streak": {
+                    "type": "integer"
+                },
+                "losses": {
+                    "type": "integer"
+                },
+                "losses_by_end_reason": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "no_results": {
+                    "type": "integer"
+                },
                 "ok": {
                     "type": "boolean"
+                },
+                "reveal_efficiency": {
+                    "description": "ships found per reveal",
+                    "type": "number"
+                },
+                "revealed_ships": {
+                    "type": "integer"
+                },
+                "reveals": {
+                    "type": "integer"
+                },
+                "ship_moves": {
+                    "type": "integer"
+                },
+                "ship_moves_per_game": {
+                    "type": "number"
+                },
+                "shots": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "wins": {
+                    "type": "integer"
+                },
+                "wins_by_end_reason": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "dto.ExplodeRequest": {
+        "dto.VerifyOtpRequest": {
             "type": "object",
             "properties": {
-                "game_id": {
+                "code": {
                     "type": "string"
                 },
-                "index": {
-                    "type": "integer"
-                },
-                "user_id": {
+                "mobile": {
                     "type": "string"
                 }
             }
         },
-        "dto.ExplodeResponse": {
+        "dto.VerifyOtpResponse": {
             "type": "object",
             "properties": {
                 "error": {
                     "type": "object",
                     "$ref": "#/definitions/dto.BattleError"
                 },
-                "has_ship": {
+                "expire_date": {
+                    "type": "string"
+                },
+                "new_user": {
                     "type": "boolean"
                 },
                 "ok": {
                     "type": "boolean"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "dto.GameDto": {
+        "dto.WebhookDeliveryDto": {
             "type": "object",
             "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
                 "create_date": {
                     "type": "string"
                 },
-                "id": {
+                "endpoint": {
                     "type": "string"
                 },
-                "move_timeout_sec": {
-                    "type": "integer"
-                },
-                "other_side_joined": {
-                    "type": "boolean"
+                "event": {
+                    "type": "string"
                 },
-                "state": {
-                    "type": "object",
-                    "$ref": "#/definitions/dto.GameState"
+                "id": {
+                    "type": "string"
                 },
-                "status": {
+                "last_attempt_time": {
                     "type": "string"
                 },
-                "user_id": {
+                "last_error": {
                     "type": "string"
                 },
-                "winner_user": {
+                "next_attempt_time": {
                     "type": "string"
                 },
-                "your_turn": {
-                    "type": "boolean"
+                "response_code": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "dto.GameState": {
+        "model.CoinToss": {
             "type": "object",
             "properties": {
-                "enemy_ground": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "boolean"
-                    }
+                "result": {
+                    "type": "string"
                 },
-                "enemy_revealed_ships": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "boolean"
-                    }
+                "serverCommitment": {
+                    "type": "string"
                 },
-                "own_ground": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "boolean"
-                    }
+                "serverSeed": {
+                    "type": "string"
                 },
-                "own_ships": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "boolean"
-                    }
+                "side1Commitment": {
+                    "type": "string"
+                },
+                "side1Seed": {
+                    "type": "string"
+                },
+                "side2Commitment": {
+                    "type": "string"
+                },
+                "side2Seed": {
+                    "type": "string"
                 }
             }
         },
-        "dto.GetGameResponse": {
+        "model.Game": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "object",
-                    "$ref": "#/definitions/dto.BattleError"
+                "boardSize": {
+                    "type": "integer"
                 },
-                "game": {
-                    "type": "object",
-                    "$ref": "#/definitions/dto.GameDto"
+                "cheatChecked": {
+                    "type": "boolean"
                 },
-                "ok": {
+                "commitReveal": {
                     "type": "boolean"
-                }
-            }
-        },
-        "dto.JoinGameRequest": {
-            "type": "object",
-            "properties": {
-                "game_id": {
+                },
+                "createDate": {
                     "type": "string"
                 },
-                "user_id": {
+                "endDate": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.MoveShipRequest": {
-            "type": "object",
-            "properties": {
-                "game_id": {
+                },
+                "endReason": {
                     "type": "string"
                 },
-                "new_ship_index": {
+                "id": {
+                    "type": "string"
+                },
+                "lastMoveTime": {
+                    "type": "string"
+                },
+                "moveCount": {
                     "type": "integer"
                 },
-                "old_ship_index": {
+                "moveTimeoutSec": {
                     "type": "integer"
                 },
-                "user_id": {
+                "passcodeFails": {
+                    "type": "integer"
+                },
+                "passcodeHash": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.MoveShipResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "object",
-                    "$ref": "#/definitions/dto.BattleError"
                 },
-                "ok": {
+                "pauseBy": {
+                    "type": "string"
+                },
+                "pauseDeadline": {
+                    "type": "string"
+                },
+                "pausedAt": {
+                    "type": "string"
+                },
+                "public": {
                     "type": "boolean"
-                }
-            }
-        },
-        "dto.RevealEnemyFieldsRequest": {
-            "type": "object",
-            "properties": {
-                "game_id": {
+                },
+                "ranked": {
+                    "type": "boolean"
+                },
+                "ratingApplied": {
+                    "type": "boolean"
+                },
+                "resumeBy": {
                     "type": "string"
                 },
-                "index": {
+                "ruleset": {
+                    "type": "string"
+                },
+                "side1Missed": {
                     "type": "integer"
                 },
-                "user_id": {
+                "side1Placement": {
+                    "type": "object",
+                    "$ref": "#/definitions/model.PlacementCommitment"
+                },
+                "side1User": {
+                    "type": "string"
+                },
+                "side2Missed": {
+                    "type": "integer"
+                },
+                "side2Placement": {
+                    "type": "object",
+                    "$ref": "#/definitions/model.PlacementCommitment"
+                },
+                "side2User": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "object",
+                    "$ref": "#/definitions/model.GameState"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "toss": {
+                    "type": "object",
+                    "$ref": "#/definitions/model.CoinToss"
+                },
+                "turn": {
+                    "type": "integer"
+                },
+                "winnerUser": {
                     "type": "string"
                 }
             }
         },
-        "dto.RevealEnemyFieldsResponse": {
+        "model.GameEvent": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "object",
-                    "$ref": "#/definitions/dto.BattleError"
-                },
-                "ok": {
-                    "type": "boolean"
+                "discoverEnemy": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "revealed_ship_indexes": {
+                "discoverEnemyShips": {
                     "type": "array",
                     "items": {
                         "type": "integer"
                     }
-                }
-            }
-        },
-        "dto.SubmitShipsLocationsRequest": {
-            "type": "object",
-            "properties": {
-                "game_id": {
+                },
+                "emptyExplosion": {
+                    "type": "integer"
+                },
+                "explosion": {
+                    "type": "integer"
+                },
+                "fromStatus": {
                     "type": "string"
                 },
-                "ships_indexes": {
+                "gameId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "initialShipsLocations": {
                     "type": "array",
                     "items": {
                         "type": "integer"
                     }
                 },
-                "user_id": {
+                "moveShipFrom": {
+                    "type": "integer"
+                },
+                "moveShipTo": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "toStatus": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "userId": {
                     "type": "string"
                 }
             }
         },
-        "dto.SubmitShipsLocationsResponse": {
+        "model.GameState": {
             "type": "object",
             "properties": {
-                "error": {
+                "side1Ground": {
+                    "description": "map index -\u003e is hidden",
                     "type": "object",
-                    "$ref": "#/definitions/dto.BattleError"
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
                 },
-                "game_status": {
-                    "type": "string"
+                "side1RevealedShips": {
+                    "description": "map index -\u003e is ship exploded",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
                 },
-                "ok": {
-                    "type": "boolean"
+                "side1Ships": {
+                    "description": "map index -\u003e is ship exist",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
                 },
-                "turn": {
-                    "type": "integer"
+                "side2Ground": {
+                    "description": "map index -\u003e is hidden",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "side2RevealedShips": {
+                    "description": "map index -\u003e is ship exploded",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "side2Ships": {
+                    "description": "map index -\u003e is ship exist",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
                 }
             }
         },
-        "dto.UserDto": {
+        "model.PlacementCommitment": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "object",
-                    "$ref": "#/definitions/dto.BattleError"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "mobile": {
+                "commitment": {
                     "type": "string"
                 },
-                "name": {
+                "salt": {
                     "type": "string"
                 },
-                "ok": {
-                    "type": "boolean"
+                "ships": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         }
@@ -1,5 +1,9 @@
 package dto
 
+import (
+	"battleship/utils"
+)
+
 type BaseRequest struct {
 }
 
@@ -22,6 +26,35 @@ func (r UserGameRequest) GetGameId() string {
 	return r.GameId
 }
 
+func (r *UserGameRequest) ValidateAndUnmask() error {
+	if r.UserId == "" {
+		return BadRequest1("user id is not correct")
+	}
+	if r.GameId == "" {
+		return BadRequest1("game id is not correct")
+	}
+	userId, err := UnmaskId(r.UserId)
+	if err != nil {
+		return BadRequest1("user id is not correct")
+	}
+	gameId, err := UnmaskId(r.GameId)
+	if err != nil {
+		return BadRequest1("game id is not correct")
+	}
+	r.UserId = userId
+	r.GameId = gameId
+	return nil
+}
+
+// UnmaskId decodes a public id received from clients into a hex ObjectID.
+func UnmaskId(publicId string) (string, error) {
+	id, err := utils.DecodeId(publicId)
+	if err != nil {
+		return "", BadRequest1("invalid id")
+	}
+	return id, nil
+}
+
 type BaseResponse struct {
 	Ok    bool         `json:"ok"`
 	Error *BattleError `json:"error,omitempty"`
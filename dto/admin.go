@@ -0,0 +1,286 @@
+package dto
+
+import (
+	"battleship/config"
+	"battleship/model"
+	"battleship/utils"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SetMaintenanceRequest turns maintenance mode on or off, Message is shown to users whose new games are rejected.
+type SetMaintenanceRequest struct {
+	AdminActor
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+}
+
+func (r *SetMaintenanceRequest) Validate() error {
+	r.Message = strings.TrimSpace(r.Message)
+	if utf8.RuneCountInString(r.Message) > config.C.Admin.NoticeMaxLength {
+		return BadRequest1("message is too long")
+	}
+	return nil
+}
+
+type MaintenanceResponse struct {
+	BaseResponse
+	Enabled    bool      `json:"enabled"`
+	Message    string    `json:"message,omitempty"`
+	UpdateDate time.Time `json:"update_date"`
+}
+
+func (r *MaintenanceResponse) FromSetting(setting model.ServerSetting) {
+	r.Enabled = setting.Maintenance
+	r.Message = setting.MaintenanceMessage
+	r.UpdateDate = setting.UpdateDate
+}
+
+type BroadcastRequest struct {
+	AdminActor
+	Message string `json:"message"`
+}
+
+func (r *BroadcastRequest) Validate() error {
+	r.Message = strings.TrimSpace(r.Message)
+	if r.Message == "" {
+		return BadRequest1("message is empty")
+	}
+	if utf8.RuneCountInString(r.Message) > config.C.Admin.NoticeMaxLength {
+		return BadRequest1("message is too long")
+	}
+	return nil
+}
+
+// BroadcastResponse has the number of sockets the notice is sent to.
+type BroadcastResponse struct {
+	BaseResponse
+	Sockets int `json:"sockets"`
+}
+
+// AdminActor is who made an admin request, it is filled from the headers and recorded in the audit.
+type AdminActor struct {
+	Actor    string `json:"-" query:"-"`
+	RemoteIp string `json:"-" query:"-"`
+}
+
+type GetLiveGamesRequest struct {
+	Limit int `query:"limit"`
+}
+
+func (r *GetLiveGamesRequest) Validate() error {
+	if r.Limit <= 0 {
+		r.Limit = 50
+	}
+	if r.Limit > 500 {
+		r.Limit = 500
+	}
+	return nil
+}
+
+// LiveGameDto is a game in progress with the socket status of its sides on this server.
+type LiveGameDto struct {
+	GameId                 string           `json:"game_id"`
+	Status                 model.GameStatus `json:"status"`
+	Side1UserId            string           `json:"side_1_user_id,omitempty"`
+	Side2UserId            string           `json:"side_2_user_id,omitempty"`
+	Side1Connected         bool             `json:"side_1_connected"`
+	Side2Connected         bool             `json:"side_2_connected"`
+	Side1DisconnectedSince *time.Time       `json:"side_1_disconnected_since,omitempty"`
+	Side2DisconnectedSince *time.Time       `json:"side_2_disconnected_since,omitempty"`
+	Turn                   int              `json:"turn"`
+	MoveCount              int              `json:"move_count"`
+	Ranked                 bool             `json:"ranked"`
+	LastMoveTime           time.Time        `json:"last_move_time"`
+	CreateDate             time.Time        `json:"create_date"`
+}
+
+func (r *LiveGameDto) FromGame(game model.Game) {
+	r.GameId = utils.EncodeId(game.Id.Hex())
+	r.Status = game.Status
+	if game.Side1User != nil {
+		r.Side1UserId = utils.EncodeId(game.Side1User.Hex())
+	}
+	if game.Side2User != nil {
+		r.Side2UserId = utils.EncodeId(game.Side2User.Hex())
+	}
+	r.Turn = game.Turn
+	r.MoveCount = game.MoveCount
+	r.Ranked = game.Ranked
+	r.LastMoveTime = game.LastMoveTime
+	r.CreateDate = game.CreateDate
+}
+
+type GetLiveGamesResponse struct {
+	BaseResponse
+	Games []LiveGameDto `json:"games"`
+}
+
+type AdminGameRequest struct {
+	GameId string `param:"game_id"`
+}
+
+func (r *AdminGameRequest) ValidateAndUnmask() error {
+	var err error
+	if r.GameId, err = UnmaskId(r.GameId); err != nil {
+		return BadRequest1("game id is not correct")
+	}
+	return nil
+}
+
+// AdminGameResponse has the game as it is stored, with the ship locations of both sides, and its events.
+type AdminGameResponse struct {
+	BaseResponse
+	Game   model.Game        `json:"game"`
+	Events []model.GameEvent `json:"events"`
+}
+
+// FinishGameRequest finishes a started or paused game, the game has no winner when WinnerUserId is empty.
+type FinishGameRequest struct {
+	AdminActor
+	GameId       string `json:"game_id"`
+	WinnerUserId string `json:"winner_user_id"`
+	Reason       string `json:"reason"`
+}
+
+func (r *FinishGameRequest) ValidateAndUnmask() error {
+	var err error
+	if r.GameId, err = UnmaskId(r.GameId); err != nil {
+		return BadRequest1("game id is not correct")
+	}
+	if r.WinnerUserId != "" {
+		if r.WinnerUserId, err = UnmaskId(r.WinnerUserId); err != nil {
+			return BadRequest1("winner user id is not correct")
+		}
+	}
+	return validateReason(&r.Reason)
+}
+
+type CancelGameAdminRequest struct {
+	AdminActor
+	GameId string `json:"game_id"`
+	Reason string `json:"reason"`
+}
+
+func (r *CancelGameAdminRequest) ValidateAndUnmask() error {
+	var err error
+	if r.GameId, err = UnmaskId(r.GameId); err != nil {
+		return BadRequest1("game id is not correct")
+	}
+	return validateReason(&r.Reason)
+}
+
+// BanUserRequest bans a user for DurationHours, the ban is permanent when DurationHours is 0.
+type BanUserRequest struct {
+	AdminActor
+	UserId        string `json:"user_id"`
+	Reason        string `json:"reason"`
+	DurationHours int    `json:"duration_hours"`
+}
+
+func (r *BanUserRequest) ValidateAndUnmask() error {
+	var err error
+	if r.UserId, err = UnmaskId(r.UserId); err != nil {
+		return BadRequest1("user id is not correct")
+	}
+	if r.DurationHours < 0 {
+		return BadRequest1("duration_hours cannot be negative")
+	}
+	return validateReason(&r.Reason)
+}
+
+type UnbanUserRequest struct {
+	AdminActor
+	UserId string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+func (r *UnbanUserRequest) ValidateAndUnmask() error {
+	var err error
+	if r.UserId, err = UnmaskId(r.UserId); err != nil {
+		return BadRequest1("user id is not correct")
+	}
+	return validateReason(&r.Reason)
+}
+
+type UserBanDto struct {
+	Reason string     `json:"reason"`
+	Date   time.Time  `json:"date"`
+	Until  *time.Time `json:"until,omitempty"`
+}
+
+type UserBanResponse struct {
+	BaseResponse
+	UserId string      `json:"user_id"`
+	Ban    *UserBanDto `json:"ban,omitempty"`
+}
+
+func (r *UserBanResponse) FromUser(user model.User) {
+	r.UserId = user.GetMaskedUserId()
+	r.Ban = nil
+	if user.Ban != nil {
+		r.Ban = &UserBanDto{
+			Reason: user.Ban.Reason,
+			Date:   user.Ban.Date,
+			Until:  user.Ban.Until,
+		}
+	}
+}
+
+// GetAuditRequest lists the audit of a target, or of all targets when TargetId is empty. Targets have the ids used by
+// the admin endpoints, masked game and user ids and delivery ids.
+type GetAuditRequest struct {
+	TargetId string `query:"target_id"`
+	Limit    int    `query:"limit"`
+}
+
+func (r *GetAuditRequest) Validate() error {
+	if r.Limit <= 0 {
+		r.Limit = 50
+	}
+	if r.Limit > 500 {
+		r.Limit = 500
+	}
+	return nil
+}
+
+type AdminAuditDto struct {
+	Id       string            `json:"id"`
+	Action   model.AdminAction `json:"action"`
+	TargetId string            `json:"target_id,omitempty"`
+	Reason   string            `json:"reason,omitempty"`
+	Data     map[string]string `json:"data,omitempty"`
+	Actor    string            `json:"actor,omitempty"`
+	RemoteIp string            `json:"remote_ip,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Time     time.Time         `json:"time"`
+}
+
+func (r *AdminAuditDto) FromAudit(audit model.AdminAudit) {
+	r.Id = audit.Id.Hex()
+	r.Action = audit.Action
+	r.TargetId = audit.TargetId
+	r.Reason = audit.Reason
+	r.Data = audit.Data
+	r.Actor = audit.Actor
+	r.RemoteIp = audit.RemoteIp
+	r.Error = audit.Error
+	r.Time = audit.Time
+}
+
+type GetAuditResponse struct {
+	BaseResponse
+	Audits []AdminAuditDto `json:"audits"`
+}
+
+func validateReason(reason *string) error {
+	*reason = strings.TrimSpace(*reason)
+	if *reason == "" {
+		return BadRequest1("reason is empty")
+	}
+	if utf8.RuneCountInString(*reason) > config.C.Admin.NoticeMaxLength {
+		return BadRequest1("reason is too long")
+	}
+	return nil
+}
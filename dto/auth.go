@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"battleship/error_codes"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var mobileRegex = regexp.MustCompile(`^\+?[0-9]{8,15}$`)
+
+type RequestOtpRequest struct {
+	Mobile string `json:"mobile"`
+}
+
+func (r *RequestOtpRequest) Validate() error {
+	r.Mobile = normalizeMobile(r.Mobile)
+	if !mobileRegex.MatchString(r.Mobile) {
+		return BadRequest2("mobile is not correct", error_codes.InvalidMobile)
+	}
+	return nil
+}
+
+type RequestOtpResponse struct {
+	BaseResponse
+	ExpireSec         int `json:"expire_sec"`
+	ResendIntervalSec int `json:"resend_interval_sec"`
+}
+
+///////////////
+
+type VerifyOtpRequest struct {
+	Mobile string `json:"mobile"`
+	Code   string `json:"code"`
+}
+
+func (r *VerifyOtpRequest) Validate() error {
+	r.Mobile = normalizeMobile(r.Mobile)
+	if !mobileRegex.MatchString(r.Mobile) {
+		return BadRequest2("mobile is not correct", error_codes.InvalidMobile)
+	}
+	r.Code = strings.TrimSpace(r.Code)
+	if r.Code == "" {
+		return BadRequest2("code is not correct", error_codes.OtpInvalid)
+	}
+	return nil
+}
+
+type VerifyOtpResponse struct {
+	BaseResponse
+	Token      string    `json:"token"`
+	ExpireDate time.Time `json:"expire_date"`
+	UserId     string    `json:"user_id"`
+	NewUser    bool      `json:"new_user"`
+}
+
+func normalizeMobile(mobile string) string {
+	return strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(strings.TrimSpace(mobile))
+}
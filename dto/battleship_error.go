@@ -28,6 +28,10 @@ func (e *BattleError) Error() string {
 	}
 }
 
+func (e *BattleError) Unwrap() error {
+	return e.ErrorCause
+}
+
 //////////////////////////////////////////////////////////
 // Define your custom error HERE:
 
@@ -187,6 +191,22 @@ func Duplicate2(message string, code error_codes.ErrorCode) error {
 	}
 }
 
+func TooManyRequests2(message string, code error_codes.ErrorCode) error {
+	return &BattleError{
+		HttpErrorCode: http.StatusTooManyRequests,
+		ErrorMessage:  message,
+		ErrorCode:     code,
+	}
+}
+
+func ServiceUnavailable2(message string, code error_codes.ErrorCode) error {
+	return &BattleError{
+		HttpErrorCode: http.StatusServiceUnavailable,
+		ErrorMessage:  message,
+		ErrorCode:     code,
+	}
+}
+
 /////////////////////////////////////////////////////////
 func CustomHTTPErrorHandler(err error, c echo.Context) {
 	if e, ok := err.(*BattleError); ok {
@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"battleship/model"
+	"battleship/utils"
+	"time"
+)
+
+type BlockUserRequest struct {
+	UserId        string `param:"user_id"`
+	BlockedUserId string `json:"blocked_user_id"`
+}
+
+func (r *BlockUserRequest) ValidateAndUnmask() error {
+	var err error
+	if r.UserId, err = UnmaskId(r.UserId); err != nil {
+		return BadRequest1("user id is not correct")
+	}
+	if r.BlockedUserId, err = UnmaskId(r.BlockedUserId); err != nil {
+		return BadRequest1("blocked user id is not correct")
+	}
+	if r.UserId == r.BlockedUserId {
+		return BadRequest1("user cannot block itself")
+	}
+	return nil
+}
+
+type UserBlockDto struct {
+	BlockedUserId string    `json:"blocked_user_id"`
+	CreateDate    time.Time `json:"create_date"`
+}
+
+func (r *UserBlockDto) FromBlock(block model.UserBlock) {
+	r.BlockedUserId = utils.EncodeId(block.BlockedUserId.Hex())
+	r.CreateDate = block.CreateDate
+}
+
+type GetBlocksResponse struct {
+	BaseResponse
+	Blocks []UserBlockDto `json:"blocks"`
+}
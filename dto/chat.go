@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"battleship/model"
+	"battleship/utils"
+	"time"
+)
+
+type ChatMessageDto struct {
+	Id     string    `json:"id"`
+	UserId string    `json:"user_id"`
+	Text   string    `json:"text,omitempty"`
+	Emote  string    `json:"emote,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+func (r *ChatMessageDto) FromMessage(message model.ChatMessage) {
+	r.Id = utils.EncodeId(message.Id.Hex())
+	r.UserId = utils.EncodeId(message.UserId.Hex())
+	r.Text = message.Text
+	r.Emote = message.Emote
+	r.Time = message.Time
+}
+
+type ChatDto struct {
+	Muted    bool             `json:"muted"`
+	Messages []ChatMessageDto `json:"messages"`
+}
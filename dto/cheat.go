@@ -0,0 +1,128 @@
+package dto
+
+import (
+	"battleship/config"
+	"battleship/model"
+	"battleship/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type GetCheatFlagsRequest struct {
+	Status string `query:"status"`
+	Limit  int    `query:"limit"`
+}
+
+func (r *GetCheatFlagsRequest) Validate() error {
+	switch model.ReportStatus(r.Status) {
+	case "":
+		r.Status = string(model.ReportPending)
+	case model.ReportPending, model.ReportConfirmed, model.ReportDismissed:
+	default:
+		return BadRequest1("status is not correct")
+	}
+	if r.Limit <= 0 {
+		r.Limit = 50
+	}
+	if r.Limit > 500 {
+		r.Limit = 500
+	}
+	return nil
+}
+
+// CheatFlagDto is a flag in the anti-cheat review queue, FlagId is the raw id as it is only used by admins.
+type CheatFlagDto struct {
+	FlagId      string             `json:"flag_id"`
+	UserId      string             `json:"user_id"`
+	OtherUserId string             `json:"other_user_id,omitempty"`
+	Kind        model.CheatKind    `json:"kind"`
+	Evidence    []CheatEvidenceDto `json:"evidence"`
+	Status      model.ReportStatus `json:"status"`
+	CreateDate  time.Time          `json:"create_date"`
+	UpdateDate  time.Time          `json:"update_date"`
+	ReviewNote  string             `json:"review_note,omitempty"`
+	ReviewedBy  string             `json:"reviewed_by,omitempty"`
+	ReviewDate  *time.Time         `json:"review_date,omitempty"`
+}
+
+type CheatEvidenceDto struct {
+	GameId           string    `json:"game_id"`
+	Time             time.Time `json:"time"`
+	Shots            int       `json:"shots,omitempty"`
+	FastShots        int       `json:"fast_shots,omitempty"`
+	MinReactionMs    int64     `json:"min_reaction_ms,omitempty"`
+	MedianReactionMs int64     `json:"median_reaction_ms,omitempty"`
+	BlindShots       int       `json:"blind_shots,omitempty"`
+	BlindHits        int       `json:"blind_hits,omitempty"`
+	ExpectedHits     float64   `json:"expected_hits,omitempty"`
+	ZScore           float64   `json:"z_score,omitempty"`
+	Games            int       `json:"games,omitempty"`
+	Wins             int       `json:"wins,omitempty"`
+	QuickLosses      int       `json:"quick_losses,omitempty"`
+}
+
+func (r *CheatFlagDto) FromFlag(flag model.CheatFlag) {
+	r.FlagId = flag.Id.Hex()
+	r.UserId = utils.EncodeId(flag.UserId.Hex())
+	r.OtherUserId = ""
+	if flag.OtherUserId != nil {
+		r.OtherUserId = utils.EncodeId(flag.OtherUserId.Hex())
+	}
+	r.Kind = flag.Kind
+	r.Evidence = []CheatEvidenceDto{}
+	for _, evidence := range flag.Evidence {
+		r.Evidence = append(r.Evidence, CheatEvidenceDto{
+			GameId:           utils.EncodeId(evidence.GameId.Hex()),
+			Time:             evidence.Time,
+			Shots:            evidence.Shots,
+			FastShots:        evidence.FastShots,
+			MinReactionMs:    evidence.MinReactionMs,
+			MedianReactionMs: evidence.MedianReactionMs,
+			BlindShots:       evidence.BlindShots,
+			BlindHits:        evidence.BlindHits,
+			ExpectedHits:     evidence.ExpectedHits,
+			ZScore:           evidence.ZScore,
+			Games:            evidence.Games,
+			Wins:             evidence.Wins,
+			QuickLosses:      evidence.QuickLosses,
+		})
+	}
+	r.Status = flag.Status
+	r.CreateDate = flag.CreateDate
+	r.UpdateDate = flag.UpdateDate
+	r.ReviewNote = flag.ReviewNote
+	r.ReviewedBy = flag.ReviewedBy
+	r.ReviewDate = flag.ReviewDate
+}
+
+type GetCheatFlagsResponse struct {
+	BaseResponse
+	Flags []CheatFlagDto `json:"flags"`
+}
+
+// ReviewCheatFlagRequest confirms or dismisses a pending flag. A confirmed flag does not ban the user, admins ban it
+// with the ban endpoint.
+type ReviewCheatFlagRequest struct {
+	AdminActor
+	FlagId    string `json:"flag_id"`
+	Confirmed bool   `json:"confirmed"`
+	Note      string `json:"note"`
+}
+
+func (r *ReviewCheatFlagRequest) Validate() error {
+	if _, err := primitive.ObjectIDFromHex(r.FlagId); err != nil {
+		return BadRequest1("flag_id is not valid")
+	}
+	r.Note = strings.TrimSpace(r.Note)
+	if utf8.RuneCountInString(r.Note) > config.C.Report.MaxCommentLength {
+		return BadRequest1("note is too long")
+	}
+	return nil
+}
+
+type ReviewCheatFlagResponse struct {
+	BaseResponse
+	Flag CheatFlagDto `json:"flag"`
+}
@@ -1,19 +1,37 @@
 package dto
 
 import (
-	"battleship/utils"
+	"battleship/config"
+	"battleship/error_codes"
+	"battleship/model"
 	"encoding/json"
 	"github.com/rs/zerolog/log"
+	"strings"
+	"time"
 )
 
 const (
-	Connect    SocketEventType = "connect"
-	GameStart                  = "game_start"
-	ChangeTurn                 = "change_turn"
-	ShipMoved                  = "ship_moved"
-	Reveal                     = "reveal"
-	Explosion                  = "explosion"
-	EndGame                    = "end_game"
+	Connect            SocketEventType = "connect"
+	GameStart                          = "game_start"
+	ChangeTurn                         = "change_turn"
+	ShipMoved                          = "ship_moved"
+	Reveal                             = "reveal"
+	Explosion                          = "explosion"
+	EndGame                            = "end_game"
+	MatchFound                         = "match_found"
+	MatchmakingTimeout                 = "matchmaking_timeout"
+	LobbyUpdate                        = "lobby_update"
+	GameCancelled                      = "game_cancelled"
+	PauseRequested                     = "pause_requested"
+	Paused                             = "paused"
+	ResumeRequested                    = "resume_requested"
+	Resumed                            = "resumed"
+	ChatMessage                        = "chat_message"
+	ChatMute                           = "chat_mute"
+	ChatHistory                        = "chat_history"
+	ChatError                          = "chat_error"
+	ServerRestarting                   = "server_restarting"
+	ServerNotice                       = "server_notice"
 )
 
 type SocketEventType string
@@ -48,15 +66,7 @@ type UserConnectEvent struct {
 }
 
 func (r *UserConnectEvent) ValidateAndUnmask() error {
-	if r.UserId == "" {
-		return BadRequest1("user id is not correct")
-	}
-	if r.GameId == "" {
-		return BadRequest1("game id is not correct")
-	}
-	r.UserId = utils.MaskId(r.UserId)
-	r.GameId = utils.MaskId(r.GameId)
-	return nil
+	return r.UserGameRequest.ValidateAndUnmask()
 }
 
 //////////////
@@ -108,3 +118,102 @@ type EndGameEvent struct {
 	GameId       string `json:"game_id"`
 	WinnerUserId string `json:"winner_user_id"`
 }
+
+////////////
+type MatchFoundEvent struct {
+	UserId string  `json:"user_id"`
+	Game   GameDto `json:"game"`
+}
+
+////////////
+type MatchmakingTimeoutEvent struct {
+	UserId string `json:"user_id"`
+}
+
+////////////
+const (
+	LobbyGameAdded   = "added"
+	LobbyGameRemoved = "removed"
+)
+
+type LobbyUpdateEvent struct {
+	Action string        `json:"action"`
+	GameId string        `json:"game_id"`
+	Game   *LobbyGameDto `json:"game,omitempty"`
+}
+
+////////////
+type GameCancelledEvent struct {
+	GameId string          `json:"game_id"`
+	Reason model.EndReason `json:"reason"`
+}
+
+////////////
+// PauseEvent is sent with PauseRequested, Paused, ResumeRequested and Resumed types. UserId is the side that made
+// the request, it is empty when the game is resumed automatically.
+type PauseEvent struct {
+	GameId        string     `json:"game_id"`
+	UserId        string     `json:"user_id,omitempty"`
+	PauseDeadline *time.Time `json:"pause_deadline,omitempty"`
+}
+
+////////////
+// ChatMessageEvent is sent by a side with either Text or Emote, it is sent back to both sides as ChatMessageDto.
+type ChatMessageEvent struct {
+	Text  string `json:"text,omitempty"`
+	Emote string `json:"emote,omitempty"`
+}
+
+func (r *ChatMessageEvent) Validate() error {
+	r.Text = strings.TrimSpace(r.Text)
+	if (r.Text == "") == (r.Emote == "") {
+		return BadRequest2("either text or emote is required", error_codes.ChatMessageInvalid)
+	}
+	if len([]rune(r.Text)) > config.C.Chat.MaxLength {
+		return BadRequest2("chat message is too long", error_codes.ChatMessageInvalid)
+	}
+	if r.Emote != "" && !validEmote(r.Emote) {
+		return BadRequest2("emote is not valid", error_codes.ChatMessageInvalid)
+	}
+	return nil
+}
+
+func validEmote(emote string) bool {
+	for _, e := range config.C.Chat.Emotes {
+		if e == emote {
+			return true
+		}
+	}
+	return false
+}
+
+// ChatMuteEvent mutes or unmutes the opponent for the sender only.
+type ChatMuteEvent struct {
+	Muted bool `json:"muted"`
+}
+
+type ChatHistoryEvent struct {
+	GameId   string           `json:"game_id"`
+	Muted    bool             `json:"muted"`
+	Messages []ChatMessageDto `json:"messages"`
+}
+
+// ChatErrorEvent is sent to the sender only, a rejected chat message does not close the socket.
+type ChatErrorEvent struct {
+	GameId       string                `json:"game_id"`
+	ErrorCode    error_codes.ErrorCode `json:"error_code,omitempty"`
+	ErrorMessage string                `json:"error_message,omitempty"`
+}
+
+////////////
+// ServerRestartingEvent is sent to all sockets on shutdown, clients reconnect after RetryAfterSec.
+type ServerRestartingEvent struct {
+	RetryAfterSec int `json:"retry_after_sec"`
+}
+
+////////////
+// ServerNoticeEvent is a message of admins broadcast to all sockets.
+type ServerNoticeEvent struct {
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
@@ -1,65 +1,175 @@
 package dto
 
 import (
+	"battleship/config"
+	"battleship/error_codes"
 	"battleship/model"
 	"battleship/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"time"
 )
 
+const (
+	minPlacementSaltLength = 16
+	maxPlacementSaltLength = 128
+	maxTossSeedLength      = 128
+)
+
 type CreateGameRequest struct {
-	UserId      string `json:"user_id,omitempty"`
-	MoveTimeout int    `json:"move_timeout"`
+	UserId         string `json:"user_id,omitempty"`
+	MoveTimeout    int    `json:"move_timeout"`
+	Ranked         bool   `json:"ranked"`
+	Public         bool   `json:"public"`
+	Passcode       string `json:"passcode,omitempty"`
+	CommitReveal   bool   `json:"commit_reveal"`
+	TossCommitment string `json:"toss_commitment,omitempty"` //hex sha256 of the toss seed sent with the ships
 }
 
 func (r *CreateGameRequest) ValidateAndUnmask() error {
 	if r.UserId == "" {
 		return BadRequest1("user id is not correct")
 	}
+	if r.Passcode != "" && (len(r.Passcode) < config.C.Invite.MinPasscodeLength ||
+		len(r.Passcode) > config.C.Invite.MaxPasscodeLength) {
+		return BadRequest1(fmt.Sprintf("passcode length is between %d and %d",
+			config.C.Invite.MinPasscodeLength, config.C.Invite.MaxPasscodeLength))
+	}
 	if r.MoveTimeout < 5 && r.MoveTimeout > 30 {
 		return BadRequest1("move timeout is between 5 and 30")
 	}
-	r.UserId = utils.MaskId(r.UserId)
+	if err := validateTossCommitment(r.TossCommitment); err != nil {
+		return err
+	}
+	userId, err := UnmaskId(r.UserId)
+	if err != nil {
+		return BadRequest1("user id is not correct")
+	}
+	r.UserId = userId
 	return nil
 }
 
 ///////////////
 
+type CancelGameRequest struct {
+	UserGameRequest
+}
+
+func (r *CancelGameRequest) ValidateAndUnmask() error {
+	return r.UserGameRequest.ValidateAndUnmask()
+}
+
+///////////////
+
+type PauseGameRequest struct {
+	UserGameRequest
+}
+
+func (r *PauseGameRequest) ValidateAndUnmask() error {
+	return r.UserGameRequest.ValidateAndUnmask()
+}
+
+type AcceptPauseRequest struct {
+	UserGameRequest
+}
+
+func (r *AcceptPauseRequest) ValidateAndUnmask() error {
+	return r.UserGameRequest.ValidateAndUnmask()
+}
+
+type ResumeGameRequest struct {
+	UserGameRequest
+}
+
+func (r *ResumeGameRequest) ValidateAndUnmask() error {
+	return r.UserGameRequest.ValidateAndUnmask()
+}
+
+type PauseGameResponse struct {
+	BaseResponse
+	Status        model.GameStatus `json:"status"`
+	PauseDeadline *time.Time       `json:"pause_deadline,omitempty"`
+}
+
+///////////////
+
+// JoinGameRequest joins by game id or by invite code, private games need the invite code.
 type JoinGameRequest struct {
 	UserGameRequest
+	InviteCode     string `json:"invite_code,omitempty"`
+	Passcode       string `json:"passcode,omitempty"`
+	TossCommitment string `json:"toss_commitment,omitempty"` //hex sha256 of the toss seed sent with the ships
 }
 
 func (r *JoinGameRequest) ValidateAndUnmask() error {
-	if r.UserId == "" {
-		return BadRequest1("user id is not correct")
+	if err := validateTossCommitment(r.TossCommitment); err != nil {
+		return err
 	}
-	if r.GameId == "" {
-		return BadRequest1("game id is not correct")
+	if r.InviteCode != "" {
+		code, ok := NormalizeInviteCode(r.InviteCode)
+		if !ok {
+			return BadRequest2("invite code is not correct", error_codes.InviteInvalid)
+		}
+		r.InviteCode = code
+		if r.GameId == "" {
+			if r.UserId == "" {
+				return BadRequest1("user id is not correct")
+			}
+			userId, err := UnmaskId(r.UserId)
+			if err != nil {
+				return BadRequest1("user id is not correct")
+			}
+			r.UserId = userId
+			return nil
+		}
 	}
-	r.UserId = utils.MaskId(r.UserId)
-	r.GameId = utils.MaskId(r.GameId)
-	return nil
+	return r.UserGameRequest.ValidateAndUnmask()
 }
 
 ///////////////
+// SubmitShipsLocationsRequest has the initial ships of a side. Commit-reveal games need Commitment and Salt, where
+// Commitment is the hex sha256 of the ship indexes sorted ascending and joined by commas, a colon and the salt.
+// TossSeed is the random value of the side for the first turn coin toss, its sha256 is the toss commitment the side
+// has sent when it joined the game.
 type SubmitShipsLocationsRequest struct {
 	UserGameRequest
-	ShipsIndexes []int `json:"ships_indexes"`
+	ShipsIndexes []int  `json:"ships_indexes"`
+	Commitment   string `json:"commitment,omitempty"`
+	Salt         string `json:"salt,omitempty"`
+	TossSeed     string `json:"toss_seed,omitempty"`
 }
 
 func (r *SubmitShipsLocationsRequest) ValidateAndUnmask() error {
-	if r.UserId == "" {
-		return BadRequest1("user id is not correct")
-	}
-	if r.GameId == "" {
-		return BadRequest1("game id is not correct")
-	}
 	if len(r.ShipsIndexes) != 10 {
 		log.Error().Msg("ship index size must be 10")
 		return BadRequest1("ship index size must be 10")
 	}
-	r.UserId = utils.MaskId(r.UserId)
-	r.GameId = utils.MaskId(r.GameId)
+	if r.Commitment != "" || r.Salt != "" {
+		if len(r.Salt) < minPlacementSaltLength || len(r.Salt) > maxPlacementSaltLength {
+			return BadRequest2(fmt.Sprintf("salt length is between %d and %d", minPlacementSaltLength,
+				maxPlacementSaltLength), error_codes.PlacementCommitmentInvalid)
+		}
+		if len(r.Commitment) != sha256.Size*2 {
+			return BadRequest2("commitment must be a hex sha256", error_codes.PlacementCommitmentInvalid)
+		}
+	}
+	if len(r.TossSeed) > maxTossSeedLength {
+		return BadRequest1(fmt.Sprintf("toss seed length is at most %d", maxTossSeedLength))
+	}
+	return r.UserGameRequest.ValidateAndUnmask()
+}
+
+// validateTossCommitment checks the commitment of a side to its coin toss seed, it is optional.
+func validateTossCommitment(commitment string) error {
+	if commitment == "" {
+		return nil
+	}
+	if _, err := hex.DecodeString(commitment); err != nil || len(commitment) != sha256.Size*2 {
+		return BadRequest2("toss commitment must be a hex sha256", error_codes.TossSeedInvalid)
+	}
 	return nil
 }
 
@@ -78,15 +188,7 @@ type MoveShipRequest struct {
 }
 
 func (r *MoveShipRequest) ValidateAndUnmask() error {
-	if r.UserId == "" {
-		return BadRequest1("user id is not correct")
-	}
-	if r.GameId == "" {
-		return BadRequest1("game id is not correct")
-	}
-	r.UserId = utils.MaskId(r.UserId)
-	r.GameId = utils.MaskId(r.GameId)
-	return nil
+	return r.UserGameRequest.ValidateAndUnmask()
 }
 
 type MoveShipResponse struct {
@@ -100,15 +202,7 @@ type ChangeTurnRequest struct {
 }
 
 func (r *ChangeTurnRequest) ValidateAndUnmask() error {
-	if r.UserId == "" {
-		return BadRequest1("user id is not correct")
-	}
-	if r.GameId == "" {
-		return BadRequest1("game id is not correct")
-	}
-	r.UserId = utils.MaskId(r.UserId)
-	r.GameId = utils.MaskId(r.GameId)
-	return nil
+	return r.UserGameRequest.ValidateAndUnmask()
 }
 
 type ChangeTurnResponse struct {
@@ -123,15 +217,7 @@ type RevealEnemyFieldsRequest struct {
 }
 
 func (r *RevealEnemyFieldsRequest) ValidateAndUnmask() error {
-	if r.UserId == "" {
-		return BadRequest1("user id is not correct")
-	}
-	if r.GameId == "" {
-		return BadRequest1("game id is not correct")
-	}
-	r.UserId = utils.MaskId(r.UserId)
-	r.GameId = utils.MaskId(r.GameId)
-	return nil
+	return r.UserGameRequest.ValidateAndUnmask()
 }
 
 type RevealEnemyFieldsResponse struct {
@@ -147,15 +233,7 @@ type ExplodeRequest struct {
 }
 
 func (r *ExplodeRequest) ValidateAndUnmask() error {
-	if r.UserId == "" {
-		return BadRequest1("user id is not correct")
-	}
-	if r.GameId == "" {
-		return BadRequest1("game id is not correct")
-	}
-	r.UserId = utils.MaskId(r.UserId)
-	r.GameId = utils.MaskId(r.GameId)
-	return nil
+	return r.UserGameRequest.ValidateAndUnmask()
 }
 
 type ExplodeResponse struct {
@@ -171,7 +249,9 @@ type GetGameRequest struct {
 
 type GetGameResponse struct {
 	BaseResponse
-	Game *GameDto `json:"game"`
+	Game   *GameDto   `json:"game"`
+	Invite *InviteDto `json:"invite,omitempty"`
+	Chat   *ChatDto   `json:"chat,omitempty"`
 }
 
 type GameDto struct {
@@ -184,6 +264,56 @@ type GameDto struct {
 	MoveTimeoutSec  int              `json:"move_timeout_sec,omitempty"`
 	CreateDate      time.Time        `json:"create_date,omitempty"`
 	WinnerUser      *string          `json:"winner_user,omitempty"`
+	BoardSize       int              `json:"board_size,omitempty"`
+	Ruleset         string           `json:"ruleset,omitempty"`
+	Ranked          bool             `json:"ranked"`
+	Public          bool             `json:"public"`
+	HasPasscode     bool             `json:"has_passcode"`
+	PauseDeadline   *time.Time       `json:"pause_deadline,omitempty"`
+	EndReason       model.EndReason  `json:"end_reason,omitempty"`
+	CommitReveal    bool             `json:"commit_reveal"`
+	Placements      []PlacementDto   `json:"placements,omitempty"`
+	Toss            *CoinTossDto     `json:"toss,omitempty"`
+}
+
+// CoinTossDto is the proof of the first turn. Until the game starts only the commitments are set, Seeds has the
+// commitments of the sides that have joined. Then Result is the hex sha256 of "server_seed:side_1_seed:side_2_seed",
+// where side 1 is the first of Seeds and a side without a seed adds an empty one, and the first turn is of side 1 when
+// the first byte of Result is even, otherwise of side 2.
+type CoinTossDto struct {
+	ServerCommitment string        `json:"server_commitment"`
+	ServerSeed       string        `json:"server_seed,omitempty"`
+	Seeds            []TossSeedDto `json:"seeds,omitempty"`
+	Result           string        `json:"result,omitempty"`
+	FirstTurnUserId  string        `json:"first_turn_user_id,omitempty"`
+}
+
+type TossSeedDto struct {
+	UserId     string `json:"user_id"`
+	Commitment string `json:"commitment,omitempty"`
+	Seed       string `json:"seed,omitempty"`
+}
+
+// PlacementDto is the placement commitment of a side in a commit-reveal game. Salt, Ships and Log are set when the
+// game is finished. Log has the moves of the side ships and the shots of the other side on them, from the oldest, so
+// every reported hit and miss can be checked against the committed placement.
+type PlacementDto struct {
+	UserId     string              `json:"user_id"`
+	Commitment string              `json:"commitment"`
+	Salt       string              `json:"salt,omitempty"`
+	Ships      []int               `json:"ships,omitempty"`
+	Log        []PlacementEventDto `json:"log,omitempty"`
+}
+
+type PlacementEventDto struct {
+	Type          model.GameEventType `json:"type"`
+	From          *int                `json:"from,omitempty"`
+	To            *int                `json:"to,omitempty"`
+	Index         *int                `json:"index,omitempty"`
+	Hit           bool                `json:"hit"`
+	RevealedCells []int               `json:"revealed_cells,omitempty"`
+	RevealedShips []int               `json:"revealed_ships,omitempty"`
+	Time          time.Time           `json:"time"`
 }
 
 type GameState struct {
@@ -194,17 +324,70 @@ type GameState struct {
 }
 
 func (r *GameDto) FromGame(game model.Game, requesterUserId string) {
-	r.Id = utils.MaskId(game.Id.Hex())
+	r.Id = utils.EncodeId(game.Id.Hex())
 	r.Status = game.Status
 	r.MoveTimeoutSec = game.MoveTimeoutSec
 	r.CreateDate = game.CreateDate
+	r.BoardSize = game.BoardSize
+	r.Ruleset = game.Ruleset
+	r.Ranked = game.Ranked
+	r.Public = game.Public
+	r.HasPasscode = game.PasscodeHash != ""
+	r.PauseDeadline = game.PauseDeadline
+	r.EndReason = game.EndReason
+	r.CommitReveal = game.CommitReveal
+	r.Placements = nil
+	for _, side := range []struct {
+		userId    *primitive.ObjectID
+		placement *model.PlacementCommitment
+	}{{game.Side1User, game.Side1Placement}, {game.Side2User, game.Side2Placement}} {
+		if side.userId == nil || side.placement == nil {
+			continue
+		}
+		placement := PlacementDto{
+			UserId:     utils.EncodeId(side.userId.Hex()),
+			Commitment: side.placement.Commitment,
+		}
+		if game.Status == model.Finished {
+			placement.Salt = side.placement.Salt
+			placement.Ships = side.placement.Ships
+		}
+		r.Placements = append(r.Placements, placement)
+	}
+	r.Toss = nil
+	if game.Toss != nil {
+		r.Toss = &CoinTossDto{ServerCommitment: game.Toss.ServerCommitment}
+		for _, side := range []struct {
+			userId     *primitive.ObjectID
+			commitment string
+			seed       string
+		}{{game.Side1User, game.Toss.Side1Commitment, game.Toss.Side1Seed},
+			{game.Side2User, game.Toss.Side2Commitment, game.Toss.Side2Seed}} {
+			if side.userId == nil {
+				continue
+			}
+			seed := TossSeedDto{UserId: utils.EncodeId(side.userId.Hex()), Commitment: side.commitment}
+			if game.Toss.Done() {
+				seed.Seed = side.seed
+			}
+			r.Toss.Seeds = append(r.Toss.Seeds, seed)
+		}
+		if game.Toss.Done() && len(r.Toss.Seeds) == 2 {
+			r.Toss.ServerSeed = game.Toss.ServerSeed
+			r.Toss.Result = game.Toss.Result
+			r.Toss.FirstTurnUserId = r.Toss.Seeds[0].UserId
+			if model.CoinTossTurn(game.Toss.Result) == 2 {
+				r.Toss.FirstTurnUserId = r.Toss.Seeds[1].UserId
+			}
+		}
+	}
 	if game.WinnerUser != nil {
-		winnerId := utils.MaskId(game.WinnerUser.Hex())
+		winnerId := utils.EncodeId(game.WinnerUser.Hex())
 		r.WinnerUser = &winnerId
 	}
 
 	if game.Side1User != nil && requesterUserId == game.Side1User.Hex() {
-		r.UserId = utils.MaskId(game.Side1User.Hex())
+		r.UserId = utils.EncodeId(game.Side1User.Hex())
 		if game.Turn == 1 {
 			r.YourTurn = true
 		} else {
@@ -222,7 +405,7 @@ func (r *GameDto) FromGame(game model.Game, requesterUserId string) {
 			r.OtherSideJoined = true
 		}
 	} else if game.Side2User != nil && requesterUserId == game.Side2User.Hex() {
-		r.UserId = utils.MaskId(game.Side2User.Hex())
+		r.UserId = utils.EncodeId(game.Side2User.Hex())
 		if game.Turn == 2 {
 			r.YourTurn = true
 		} else {
@@ -243,3 +426,121 @@ func (r *GameDto) FromGame(game model.Game, requesterUserId string) {
 		log.Error().Str("user_id", requesterUserId).Str("game_id", game.Id.Hex()).Msg("user does not belong to this game")
 	}
 }
+
+// AddPlacementLog adds the moves and the received shots of each side to the placements of a finished game. events
+// are from the newest, as the game event dao returns them.
+func (r *GameDto) AddPlacementLog(events []model.GameEvent) {
+	if r.Status != model.Finished {
+		return
+	}
+	for i := range r.Placements {
+		placement := &r.Placements[i]
+		for j := len(events) - 1; j >= 0; j-- {
+			e := events[j]
+			if e.UserId == nil {
+				continue
+			}
+			own := utils.EncodeId(e.UserId.Hex()) == placement.UserId
+			event := PlacementEventDto{Type: e.Type, Time: e.Time}
+			switch {
+			case own && e.Type == model.MoveShip:
+				event.From = e.MoveShipFrom
+				event.To = e.MoveShipTo
+			case !own && e.Type == model.Explosion:
+				event.Index = e.Explosion
+				event.Hit = true
+			case !own && e.Type == model.EmptyExplosion:
+				event.Index = e.EmptyExplosion
+			case !own && e.Type == model.Reveal:
+				event.RevealedCells = e.DiscoverEnemy
+				event.RevealedShips = e.DiscoverEnemyShips
+				event.Hit = len(e.DiscoverEnemyShips) > 0
+			default:
+				continue
+			}
+			placement.Log = append(placement.Log, event)
+		}
+	}
+}
+
+////////////
+
+type GetUserGamesRequest struct {
+	UserId     string `param:"user_id"`
+	Status     string `query:"status"`
+	Result     string `query:"result"`
+	From       string `query:"from"`
+	To         string `query:"to"`
+	OpponentId string `query:"opponent_id"`
+	Cursor     string `query:"cursor"`
+	Limit      int    `query:"limit"`
+
+	FromDate *time.Time `query:"-"`
+	ToDate   *time.Time `query:"-"`
+}
+
+func (r *GetUserGamesRequest) ValidateAndUnmask() error {
+	var err error
+	if r.UserId, err = UnmaskId(r.UserId); err != nil {
+		return BadRequest1("user id is not correct")
+	}
+	switch model.GameStatus(r.Status) {
+	case "", model.Init, model.Joined, model.Start, model.Paused, model.Finished, model.Cancelled:
+	default:
+		return BadRequest1("status is not correct")
+	}
+	if r.Result != "" && r.Result != "win" && r.Result != "loss" {
+		return BadRequest1("result must be win or loss")
+	}
+	if r.From != "" {
+		from, err := time.Parse(time.RFC3339, r.From)
+		if err != nil {
+			return BadRequest1("from must be RFC3339 date")
+		}
+		r.FromDate = &from
+	}
+	if r.To != "" {
+		to, err := time.Parse(time.RFC3339, r.To)
+		if err != nil {
+			return BadRequest1("to must be RFC3339 date")
+		}
+		r.ToDate = &to
+	}
+	if r.OpponentId != "" {
+		if r.OpponentId, err = UnmaskId(r.OpponentId); err != nil {
+			return BadRequest1("opponent id is not correct")
+		}
+	}
+	if r.Cursor != "" {
+		if r.Cursor, err = UnmaskId(r.Cursor); err != nil {
+			return BadRequest1("cursor is not correct")
+		}
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		return BadRequest1("limit must not be more than 100")
+	}
+	return nil
+}
+
+type GameSummaryDto struct {
+	GameId       string           `json:"game_id"`
+	Status       model.GameStatus `json:"status"`
+	OpponentId   string           `json:"opponent_id,omitempty"`
+	OpponentName *string          `json:"opponent_name,omitempty"`
+	Result       string           `json:"result,omitempty"`
+	EndReason    model.EndReason  `json:"end_reason,omitempty"`
+	Ranked       bool             `json:"ranked"`
+	CreateDate   time.Time        `json:"create_date"`
+	EndDate      *time.Time       `json:"end_date,omitempty"`
+	DurationSec  int64            `json:"duration_sec"`
+	MoveCount    int              `json:"move_count"`
+}
+
+type GetUserGamesResponse struct {
+	BaseResponse
+	Games      []GameSummaryDto `json:"games"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
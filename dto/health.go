@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"time"
+)
+
+type HealthStatus string
+
+const (
+	HealthOk   HealthStatus = "ok"
+	HealthWarn HealthStatus = "warn"
+	HealthFail HealthStatus = "fail"
+)
+
+// HealthCheck is the result of one check, a warn check does not make the server unready.
+type HealthCheck struct {
+	Name      string            `json:"name"`
+	Status    HealthStatus      `json:"status"`
+	Message   string            `json:"message,omitempty"`
+	LatencyMs int64             `json:"latency_ms"`
+	Data      map[string]string `json:"data,omitempty"`
+}
+
+type HealthResponse struct {
+	Status HealthStatus  `json:"status"`
+	Time   time.Time     `json:"time"`
+	Checks []HealthCheck `json:"checks,omitempty"`
+}
@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"battleship/error_codes"
+	"strings"
+	"time"
+)
+
+// InviteCodeAlphabet has no look-alike characters like 0/O and 1/I, so codes can be read out or typed by hand.
+const InviteCodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+const InviteCodeLength = 6
+
+// NormalizeInviteCode accepts codes in any case, with or without the dash, and returns them as XXX-XXX.
+func NormalizeInviteCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != InviteCodeLength {
+		return "", false
+	}
+	for _, c := range code {
+		if !strings.ContainsRune(InviteCodeAlphabet, c) {
+			return "", false
+		}
+	}
+	return FormatInviteCode(code), true
+}
+
+func FormatInviteCode(code string) string {
+	return code[:InviteCodeLength/2] + "-" + code[InviteCodeLength/2:]
+}
+
+type InviteDto struct {
+	Code       string    `json:"code"`
+	GameId     string    `json:"game_id"`
+	ExpireDate time.Time `json:"expire_date"`
+}
+
+//////////////
+
+type CreateInviteRequest struct {
+	UserGameRequest
+}
+
+func (r *CreateInviteRequest) ValidateAndUnmask() error {
+	return r.UserGameRequest.ValidateAndUnmask()
+}
+
+type InviteResponse struct {
+	BaseResponse
+	Invite *InviteDto `json:"invite"`
+}
+
+//////////////
+
+type RevokeInviteRequest struct {
+	UserId string `json:"user_id"`
+	Code   string `json:"code"`
+}
+
+func (r *RevokeInviteRequest) ValidateAndUnmask() error {
+	if r.UserId == "" {
+		return BadRequest1("user id is not correct")
+	}
+	code, ok := NormalizeInviteCode(r.Code)
+	if !ok {
+		return BadRequest2("invite code is not correct", error_codes.InviteInvalid)
+	}
+	r.Code = code
+	userId, err := UnmaskId(r.UserId)
+	if err != nil {
+		return BadRequest1("user id is not correct")
+	}
+	r.UserId = userId
+	return nil
+}
+
+type RevokeInviteResponse struct {
+	BaseResponse
+}
@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"battleship/config"
+	"battleship/model"
+	"time"
+)
+
+type GetLeaderboardRequest struct {
+	Board    model.LeaderboardType `query:"board"`
+	SeasonId string                `query:"season_id"`
+	Page     int                   `query:"page"`
+	PageSize int                   `query:"page_size"`
+	UserId   string                `query:"user_id"`
+}
+
+func (r *GetLeaderboardRequest) ValidateAndUnmask() error {
+	if r.Board == "" {
+		r.Board = model.AllTimeBoard
+	}
+	if r.Board != model.AllTimeBoard && r.Board != model.SeasonBoard && r.Board != model.WeeklyBoard {
+		return BadRequest1("board must be all_time, season or weekly")
+	}
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.PageSize <= 0 {
+		r.PageSize = config.C.Leaderboard.PageSize
+	}
+	if r.PageSize > config.C.Leaderboard.MaxPageSize {
+		return BadRequest1("page size is too big")
+	}
+	if r.UserId != "" {
+		userId, err := UnmaskId(r.UserId)
+		if err != nil {
+			return BadRequest1("user id is not correct")
+		}
+		r.UserId = userId
+	}
+	return nil
+}
+
+type LeaderboardEntryDto struct {
+	Rank   int64   `json:"rank"`
+	UserId string  `json:"user_id"`
+	Name   *string `json:"name,omitempty"`
+	Rating int     `json:"rating"`
+	Wins   int     `json:"wins"`
+	Losses int     `json:"losses"`
+}
+
+type GetLeaderboardResponse struct {
+	BaseResponse
+	Board      model.LeaderboardType `json:"board"`
+	Period     string                `json:"period"`
+	Page       int                   `json:"page"`
+	PageSize   int                   `json:"page_size"`
+	Total      int64                 `json:"total"`
+	Entries    []LeaderboardEntryDto `json:"entries"`
+	MyPosition *LeaderboardEntryDto  `json:"my_position,omitempty"`
+}
+
+//////////////
+
+type SeasonDto struct {
+	Id       string                `json:"id"`
+	Name     string                `json:"name"`
+	Start    time.Time             `json:"start"`
+	End      time.Time             `json:"end"`
+	Active   bool                  `json:"active"`
+	Archived bool                  `json:"archived"`
+	Winners  []LeaderboardEntryDto `json:"winners,omitempty"`
+}
+
+type GetSeasonsResponse struct {
+	BaseResponse
+	Seasons []SeasonDto `json:"seasons"`
+}
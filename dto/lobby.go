@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"battleship/config"
+	"time"
+)
+
+// GetLobbyRequest lists open games, games of users blocked with UserId are left out when it is given.
+type GetLobbyRequest struct {
+	Limit  int    `query:"limit"`
+	UserId string `query:"user_id"`
+}
+
+func (r *GetLobbyRequest) Validate() error {
+	if r.UserId != "" {
+		var err error
+		if r.UserId, err = UnmaskId(r.UserId); err != nil {
+			return BadRequest1("user id is not correct")
+		}
+	}
+	if r.Limit <= 0 {
+		r.Limit = config.C.Lobby.PageSize
+	}
+	if r.Limit > config.C.Lobby.MaxPageSize {
+		return BadRequest1("limit is too big")
+	}
+	return nil
+}
+
+type LobbyGameDto struct {
+	GameId         string    `json:"game_id"`
+	CreatorId      string    `json:"creator_id"`
+	CreatorName    *string   `json:"creator_name,omitempty"`
+	CreatorRating  int       `json:"creator_rating"`
+	BoardSize      int       `json:"board_size,omitempty"`
+	MoveTimeoutSec int       `json:"move_timeout_sec,omitempty"`
+	Ruleset        string    `json:"ruleset,omitempty"`
+	Ranked         bool      `json:"ranked"`
+	HasPasscode    bool      `json:"has_passcode"`
+	CreateDate     time.Time `json:"create_date"`
+	AgeSec         int64     `json:"age_sec"`
+}
+
+type GetLobbyResponse struct {
+	BaseResponse
+	Games []LobbyGameDto `json:"games"`
+}
@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"battleship/model"
+	"time"
+)
+
+const (
+	MatchmakingQueued  = "queued"
+	MatchmakingMatched = "matched"
+)
+
+type EnqueueMatchmakingRequest struct {
+	UserId         string `json:"user_id"`
+	BoardSize      int    `json:"board_size,omitempty"`
+	MoveTimeout    int    `json:"move_timeout"`
+	Ruleset        string `json:"ruleset,omitempty"`
+	Ranked         bool   `json:"ranked"`
+	CommitReveal   bool   `json:"commit_reveal"`
+	TossCommitment string `json:"toss_commitment,omitempty"` //hex sha256 of the toss seed sent with the ships
+}
+
+func (r *EnqueueMatchmakingRequest) ValidateAndUnmask() error {
+	if r.UserId == "" {
+		return BadRequest1("user id is not correct")
+	}
+	if r.BoardSize == 0 {
+		r.BoardSize = model.DefaultBoardSize
+	}
+	if r.BoardSize != model.DefaultBoardSize {
+		return BadRequest1("board size is not supported")
+	}
+	if r.Ruleset == "" {
+		r.Ruleset = model.ClassicRuleset
+	}
+	if r.Ruleset != model.ClassicRuleset {
+		return BadRequest1("ruleset is not supported")
+	}
+	if r.MoveTimeout < 5 || r.MoveTimeout > 30 {
+		return BadRequest1("move timeout is between 5 and 30")
+	}
+	if err := validateTossCommitment(r.TossCommitment); err != nil {
+		return err
+	}
+	userId, err := UnmaskId(r.UserId)
+	if err != nil {
+		return BadRequest1("user id is not correct")
+	}
+	r.UserId = userId
+	return nil
+}
+
+func (r EnqueueMatchmakingRequest) Settings() model.GameSettings {
+	return model.GameSettings{
+		BoardSize:      r.BoardSize,
+		MoveTimeoutSec: r.MoveTimeout,
+		Ruleset:        r.Ruleset,
+		Ranked:         r.Ranked,
+		CommitReveal:   r.CommitReveal,
+	}
+}
+
+type EnqueueMatchmakingResponse struct {
+	BaseResponse
+	Status     string    `json:"status"`
+	ExpireDate time.Time `json:"expire_date,omitempty"`
+	Game       *GameDto  `json:"game,omitempty"`
+}
+
+//////////////
+
+type CancelMatchmakingRequest struct {
+	UserId string `json:"user_id"`
+}
+
+func (r *CancelMatchmakingRequest) ValidateAndUnmask() error {
+	if r.UserId == "" {
+		return BadRequest1("user id is not correct")
+	}
+	userId, err := UnmaskId(r.UserId)
+	if err != nil {
+		return BadRequest1("user id is not correct")
+	}
+	r.UserId = userId
+	return nil
+}
+
+type CancelMatchmakingResponse struct {
+	BaseResponse
+}
@@ -0,0 +1,147 @@
+package dto
+
+import (
+	"battleship/config"
+	"battleship/error_codes"
+	"battleship/model"
+	"battleship/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type ReportUserRequest struct {
+	UserId         string `json:"user_id"`
+	ReportedUserId string `json:"reported_user_id"`
+	GameId         string `json:"game_id"`
+	Category       string `json:"category"`
+	Comment        string `json:"comment"`
+}
+
+func (r *ReportUserRequest) ValidateAndUnmask() error {
+	var err error
+	if r.UserId, err = UnmaskId(r.UserId); err != nil {
+		return BadRequest1("user id is not correct")
+	}
+	if r.ReportedUserId, err = UnmaskId(r.ReportedUserId); err != nil {
+		return BadRequest1("reported user id is not correct")
+	}
+	if r.GameId, err = UnmaskId(r.GameId); err != nil {
+		return BadRequest1("game id is not correct")
+	}
+	if r.UserId == r.ReportedUserId {
+		return BadRequest2("user cannot report itself", error_codes.ReportInvalid)
+	}
+	if !isReportCategory(r.Category) {
+		return BadRequest2("category is not correct", error_codes.ReportInvalid)
+	}
+	r.Comment = strings.TrimSpace(r.Comment)
+	if utf8.RuneCountInString(r.Comment) > config.C.Report.MaxCommentLength {
+		return BadRequest2("comment is too long", error_codes.ReportInvalid)
+	}
+	return nil
+}
+
+func isReportCategory(category string) bool {
+	for _, c := range config.C.Report.Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+type ReportUserResponse struct {
+	BaseResponse
+	ReportId string `json:"report_id"`
+}
+
+type GetReportsRequest struct {
+	Status string `query:"status"`
+	Limit  int    `query:"limit"`
+}
+
+func (r *GetReportsRequest) Validate() error {
+	switch model.ReportStatus(r.Status) {
+	case "":
+		r.Status = string(model.ReportPending)
+	case model.ReportPending, model.ReportConfirmed, model.ReportDismissed:
+	default:
+		return BadRequest1("status is not correct")
+	}
+	if r.Limit <= 0 {
+		r.Limit = 50
+	}
+	if r.Limit > 500 {
+		r.Limit = 500
+	}
+	return nil
+}
+
+// UserReportDto is a report in the moderation queue, ReportId is the raw id as it is only used by admins.
+type UserReportDto struct {
+	ReportId       string             `json:"report_id"`
+	ReporterId     string             `json:"reporter_id"`
+	ReportedUserId string             `json:"reported_user_id"`
+	GameId         string             `json:"game_id"`
+	Category       string             `json:"category"`
+	Comment        string             `json:"comment,omitempty"`
+	ChatLog        []ChatMessageDto   `json:"chat_log,omitempty"`
+	Status         model.ReportStatus `json:"status"`
+	CreateDate     time.Time          `json:"create_date"`
+	ReviewNote     string             `json:"review_note,omitempty"`
+	ReviewedBy     string             `json:"reviewed_by,omitempty"`
+	ReviewDate     *time.Time         `json:"review_date,omitempty"`
+}
+
+func (r *UserReportDto) FromReport(report model.UserReport) {
+	r.ReportId = report.Id.Hex()
+	r.ReporterId = utils.EncodeId(report.ReporterId.Hex())
+	r.ReportedUserId = utils.EncodeId(report.ReportedUserId.Hex())
+	r.GameId = utils.EncodeId(report.GameId.Hex())
+	r.Category = report.Category
+	r.Comment = report.Comment
+	r.ChatLog = nil
+	for _, message := range report.ChatLog {
+		messageDto := ChatMessageDto{}
+		messageDto.FromMessage(message)
+		r.ChatLog = append(r.ChatLog, messageDto)
+	}
+	r.Status = report.Status
+	r.CreateDate = report.CreateDate
+	r.ReviewNote = report.ReviewNote
+	r.ReviewedBy = report.ReviewedBy
+	r.ReviewDate = report.ReviewDate
+}
+
+type GetReportsResponse struct {
+	BaseResponse
+	Reports []UserReportDto `json:"reports"`
+}
+
+// ReviewReportRequest confirms or dismisses a pending report.
+type ReviewReportRequest struct {
+	AdminActor
+	ReportId  string `json:"report_id"`
+	Confirmed bool   `json:"confirmed"`
+	Note      string `json:"note"`
+}
+
+func (r *ReviewReportRequest) Validate() error {
+	if _, err := primitive.ObjectIDFromHex(r.ReportId); err != nil {
+		return BadRequest1("report_id is not valid")
+	}
+	r.Note = strings.TrimSpace(r.Note)
+	if utf8.RuneCountInString(r.Note) > config.C.Report.MaxCommentLength {
+		return BadRequest1("note is too long")
+	}
+	return nil
+}
+
+// ReviewReportResponse has the reviewed report, Restricted is set when the review has restricted the reported user.
+type ReviewReportResponse struct {
+	BaseResponse
+	Report     UserReportDto `json:"report"`
+	Restricted bool          `json:"restricted"`
+}
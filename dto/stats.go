@@ -0,0 +1,23 @@
+package dto
+
+type UserStatsResponse struct {
+	BaseResponse
+	UserId                 string         `json:"user_id"`
+	GamesPlayed            int            `json:"games_played"`
+	Wins                   int            `json:"wins"`
+	Losses                 int            `json:"losses"`
+	NoResults              int            `json:"no_results"`
+	WinsByEndReason        map[string]int `json:"wins_by_end_reason"`
+	LossesByEndReason      map[string]int `json:"losses_by_end_reason"`
+	AverageGameDurationSec float64        `json:"average_game_duration_sec"`
+	Shots                  int            `json:"shots"`
+	Hits                   int            `json:"hits"`
+	Accuracy               float64        `json:"accuracy"`
+	Reveals                int            `json:"reveals"`
+	RevealedShips          int            `json:"revealed_ships"`
+	RevealEfficiency       float64        `json:"reveal_efficiency"` //ships found per reveal
+	ShipMoves              int            `json:"ship_moves"`
+	ShipMovesPerGame       float64        `json:"ship_moves_per_game"`
+	CurrentWinStreak       int            `json:"current_win_streak"`
+	LongestWinStreak       int            `json:"longest_win_streak"`
+}
@@ -1,10 +1,18 @@
 package dto
 
+import (
+	"battleship/model"
+	"time"
+)
+
 type UserDto struct {
 	BaseResponse
-	Id     string  `json:"id,omitempty"`
-	Name   *string `json:"name,omitempty"`
-	Mobile *string `json:"mobile,omitempty"`
+	Id          string  `json:"id,omitempty"`
+	Name        *string `json:"name,omitempty"`
+	Mobile      *string `json:"mobile,omitempty"`
+	Rating      int     `json:"rating"`
+	RatedGames  int     `json:"rated_games"`
+	Provisional bool    `json:"provisional"`
 }
 
 type CreateUserRequest struct {
@@ -16,3 +24,18 @@ type CreateUserResponse struct {
 	BaseResponse
 	Id string `json:"id,omitempty"`
 }
+
+type RatingHistoryDto struct {
+	GameId         string          `json:"game_id"`
+	OpponentUserId string          `json:"opponent_user_id"`
+	OldRating      int             `json:"old_rating"`
+	NewRating      int             `json:"new_rating"`
+	Won            bool            `json:"won"`
+	EndReason      model.EndReason `json:"end_reason"`
+	Time           time.Time       `json:"time"`
+}
+
+type RatingHistoryResponse struct {
+	BaseResponse
+	History []RatingHistoryDto `json:"history"`
+}
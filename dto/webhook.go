@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"battleship/model"
+	"battleship/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// WebhookPayload is the JSON body of a webhook delivery.
+type WebhookPayload struct {
+	Event model.WebhookEvent `json:"event"`
+	Time  time.Time          `json:"time"`
+	Game  WebhookGameDto     `json:"game"`
+}
+
+type WebhookGameDto struct {
+	GameId       string           `json:"game_id"`
+	Status       model.GameStatus `json:"status"`
+	Side1UserId  string           `json:"side_1_user_id,omitempty"`
+	Side2UserId  string           `json:"side_2_user_id,omitempty"`
+	WinnerUserId string           `json:"winner_user_id,omitempty"`
+	EndReason    model.EndReason  `json:"end_reason,omitempty"`
+	BoardSize    int              `json:"board_size,omitempty"`
+	Ranked       bool             `json:"ranked"`
+	Public       bool             `json:"public"`
+	CreateDate   time.Time        `json:"create_date"`
+	EndDate      *time.Time       `json:"end_date,omitempty"`
+}
+
+func (r *WebhookGameDto) FromGame(game model.Game) {
+	r.GameId = utils.EncodeId(game.Id.Hex())
+	r.Status = game.Status
+	if game.Side1User != nil {
+		r.Side1UserId = utils.EncodeId(game.Side1User.Hex())
+	}
+	if game.Side2User != nil {
+		r.Side2UserId = utils.EncodeId(game.Side2User.Hex())
+	}
+	if game.WinnerUser != nil {
+		r.WinnerUserId = utils.EncodeId(game.WinnerUser.Hex())
+	}
+	r.EndReason = game.EndReason
+	r.BoardSize = game.BoardSize
+	r.Ranked = game.Ranked
+	r.Public = game.Public
+	r.CreateDate = game.CreateDate
+	r.EndDate = game.EndDate
+}
+
+type WebhookDeliveryDto struct {
+	Id              string                      `json:"id"`
+	Event           model.WebhookEvent          `json:"event"`
+	Endpoint        string                      `json:"endpoint"`
+	Status          model.WebhookDeliveryStatus `json:"status"`
+	Attempts        int                         `json:"attempts"`
+	ResponseCode    int                         `json:"response_code,omitempty"`
+	LastError       string                      `json:"last_error,omitempty"`
+	CreateDate      time.Time                   `json:"create_date"`
+	LastAttemptTime *time.Time                  `json:"last_attempt_time,omitempty"`
+	NextAttemptTime time.Time                   `json:"next_attempt_time"`
+}
+
+func (r *WebhookDeliveryDto) FromDelivery(delivery model.WebhookDelivery) {
+	r.Id = delivery.Id.Hex()
+	r.Event = delivery.Event
+	r.Endpoint = delivery.Endpoint
+	r.Status = delivery.Status
+	r.Attempts = delivery.Attempts
+	r.ResponseCode = delivery.ResponseCode
+	r.LastError = delivery.LastError
+	r.CreateDate = delivery.CreateDate
+	r.LastAttemptTime = delivery.LastAttemptTime
+	r.NextAttemptTime = delivery.NextAttemptTime
+}
+
+// RedeliverWebhookRequest has the id of the delivery log document, it is not masked as it is only used by admins.
+type RedeliverWebhookRequest struct {
+	AdminActor
+	DeliveryId string `json:"delivery_id"`
+}
+
+func (r *RedeliverWebhookRequest) Validate() error {
+	if _, err := primitive.ObjectIDFromHex(r.DeliveryId); err != nil {
+		return BadRequest1("delivery_id is not valid")
+	}
+	return nil
+}
+
+type RedeliverWebhookResponse struct {
+	BaseResponse
+	Delivery WebhookDeliveryDto `json:"delivery"`
+}
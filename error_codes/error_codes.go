@@ -7,6 +7,27 @@ const (
 	InvalidGameStatus
 	InvalidShipIndexValue
 	GameIsFinished
+	OtpRateLimited
+	OtpInvalid
+	OtpExpired
+	OtpTooManyAttempts
+	InvalidMobile
+	AlreadyInMatchmaking
+	InviteInvalid
+	InviteExpired
+	PasscodeInvalid
+	ChatRateLimited
+	ChatMessageInvalid
+	ServerDraining
+	ServerMaintenance
+	UserBanned
+	UserBlocked
+	ReportInvalid
+	ReportDuplicate
+	PlacementCommitmentInvalid
+	CheatFlagReviewed
+	TossSeedInvalid
+	WebhookDeliveryBusy
 )
 
 type ErrorCode int
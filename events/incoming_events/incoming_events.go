@@ -1,24 +1,69 @@
 package incoming_events
 
 import (
-	"battleship/db/dao"
 	"battleship/dto"
+	"battleship/events/outgoing_events"
+	"battleship/service"
+	"battleship/tracing"
+	"battleship/utils"
+	"context"
+	"encoding/json"
+	"errors"
 )
 
 type IncomingEventHandler interface {
-	HandleEvent(event dto.Event) error
+	HandleEvent(ctx context.Context, gameId string, userId string, event dto.Event) error
 }
 
 type IncomingEventHandlerImpl struct {
-	gameDao dao.GameDao
+	chatService  service.ChatService
+	eventHandler outgoing_events.OutgoingEventHandler
 }
 
-func NewIncomingEventHandlerImpl(gameDao dao.GameDao) IncomingEventHandlerImpl {
+func NewIncomingEventHandlerImpl(chatService service.ChatService,
+	eventHandler outgoing_events.OutgoingEventHandler) IncomingEventHandlerImpl {
 	return IncomingEventHandlerImpl{
-		gameDao: gameDao,
+		chatService:  chatService,
+		eventHandler: eventHandler,
 	}
 }
 
-func (r IncomingEventHandlerImpl) HandleEvent(event dto.Event) error {
+// HandleEvent handles an event that a side sends on the game socket, gameId and userId are unmasked ids of the
+// socket. A rejected chat event is answered with a ChatError event and does not close the socket.
+func (r IncomingEventHandlerImpl) HandleEvent(ctx context.Context, gameId string, userId string, event dto.Event) error {
+	var err error
+	switch event.Type {
+	case dto.ChatMessage:
+		request := dto.ChatMessageEvent{}
+		if err = json.Unmarshal([]byte(event.Payload), &request); err == nil {
+			err = r.chatService.SendMessage(ctx, gameId, userId, request)
+		}
+	case dto.ChatMute:
+		request := dto.ChatMuteEvent{}
+		if err = json.Unmarshal([]byte(event.Payload), &request); err == nil {
+			err = r.chatService.SetMute(ctx, gameId, userId, request)
+		}
+	default:
+		tracing.Log(ctx).Debug().Str("event_type", string(event.Type)).Str("game_id", gameId).Msg("incoming event is ignored")
+		return nil
+	}
+	if err != nil {
+		r.chatError(ctx, gameId, userId, err)
+	}
 	return nil
 }
+
+func (r IncomingEventHandlerImpl) chatError(ctx context.Context, gameId string, userId string, err error) {
+	chatErrorEvent := dto.ChatErrorEvent{
+		GameId:       utils.EncodeId(gameId),
+		ErrorMessage: "cannot handle chat event",
+	}
+	var battleError *dto.BattleError
+	if errors.As(err, &battleError) {
+		chatErrorEvent.ErrorCode = battleError.ErrorCode
+		chatErrorEvent.ErrorMessage = battleError.ErrorMessage
+	}
+	if err := r.eventHandler.ChatError(ctx, utils.EncodeId(userId), chatErrorEvent); err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("game_id", gameId).Msg("cannot send chat error")
+	}
+}
@@ -3,19 +3,32 @@ package outgoing_events
 import (
 	"battleship/cache"
 	"battleship/dto"
+	"battleship/metrics"
+	"battleship/tracing"
 	"battleship/utils"
+	"context"
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
 )
 
 type OutgoingEventHandler interface {
-	GameConnect(gameConnectEvent dto.GameConnect) error
-	GameStart(gameStartEvent dto.GameStartEvent) error
-	ChangeTurn(changeTurn dto.GameChangeTurnEvent) error
-	MoveShip(shipMovedEvent dto.ShipMovedEvent) error
-	Reveal(revealEvent dto.RevealEvent) error
-	Explosion(explosionEvent dto.ExplosionEvent) error
-	EndGame(endGameEvent dto.EndGameEvent) error
+	GameConnect(ctx context.Context, gameConnectEvent dto.GameConnect) error
+	GameStart(ctx context.Context, gameStartEvent dto.GameStartEvent) error
+	ChangeTurn(ctx context.Context, changeTurn dto.GameChangeTurnEvent) error
+	MoveShip(ctx context.Context, shipMovedEvent dto.ShipMovedEvent) error
+	Reveal(ctx context.Context, revealEvent dto.RevealEvent) error
+	Explosion(ctx context.Context, explosionEvent dto.ExplosionEvent) error
+	EndGame(ctx context.Context, endGameEvent dto.EndGameEvent) error
+	MatchFound(ctx context.Context, matchFoundEvent dto.MatchFoundEvent) error
+	MatchmakingTimeout(ctx context.Context, matchmakingTimeoutEvent dto.MatchmakingTimeoutEvent) error
+	LobbyUpdate(ctx context.Context, lobbyUpdateEvent dto.LobbyUpdateEvent) error
+	GameCancelled(ctx context.Context, gameCancelledEvent dto.GameCancelledEvent) error
+	Pause(ctx context.Context, eventType dto.SocketEventType, pauseEvent dto.PauseEvent) error
+	ChatMessage(ctx context.Context, gameId string, userId string, chatMessage dto.ChatMessageDto) error
+	ChatHistory(ctx context.Context, userId string, chatHistoryEvent dto.ChatHistoryEvent) error
+	ChatError(ctx context.Context, userId string, chatErrorEvent dto.ChatErrorEvent) error
+	ServerRestarting(ctx context.Context, serverRestartingEvent dto.ServerRestartingEvent) error
+	ServerNotice(ctx context.Context, serverNoticeEvent dto.ServerNoticeEvent) (sockets int, err error)
 }
 
 type OutgoingEventHandlerImpl struct {
@@ -25,170 +38,456 @@ func NewOutgoingEventHandlerImpl() OutgoingEventHandlerImpl {
 	return OutgoingEventHandlerImpl{}
 }
 
-func (r OutgoingEventHandlerImpl) GameConnect(gameConnectEvent dto.GameConnect) error {
-	if gameData, ok := cache.GameCache.Cache[utils.MaskId(gameConnectEvent.GameId)]; ok {
+func (r OutgoingEventHandlerImpl) GameConnect(ctx context.Context, gameConnectEvent dto.GameConnect) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.GameConnect")
+	defer span.End()
+	gameId, userId, err := unmaskIds(gameConnectEvent.GameId, gameConnectEvent.UserId)
+	if err != nil {
+		return err
+	}
+	if gameData, ok := cache.GetGameData(gameId); ok {
 		eventBytes, err := dto.MarshalEvent(gameConnectEvent, dto.Connect)
 		if err != nil {
-			log.Error().Err(err).Msg("cannot marshal GameStartEvent")
+			tracing.Log(ctx).Error().Err(err).Msg("cannot marshal GameStartEvent")
 			return err
 		}
 
-		if gameData.Side1UserId == utils.MaskId(gameConnectEvent.UserId) {
-			err = gameData.Side1Socket.WriteMessage(websocket.TextMessage, eventBytes)
-		} else if gameData.Side2UserId == utils.MaskId(gameConnectEvent.UserId) {
-			err = gameData.Side2Socket.WriteMessage(websocket.TextMessage, eventBytes)
+		if gameData.Side1UserId == userId {
+			err = cache.WriteMessage(gameData.Side1Socket, eventBytes)
+		} else if gameData.Side2UserId == userId {
+			err = cache.WriteMessage(gameData.Side2Socket, eventBytes)
 		} else {
 			return dto.Forbidden1("user does not belong to game!")
 		}
 
 		if err != nil {
-			log.Error().Err(err).Msg("cannot send GameStartEvent")
+			tracing.Log(ctx).Error().Err(err).Msg("cannot send GameStartEvent")
+			metrics.OutgoingEventFailed(string(dto.Connect))
 			return err
 		}
 	}
 	return nil
 }
 
-func (r OutgoingEventHandlerImpl) GameStart(gameStartEvent dto.GameStartEvent) error {
-	if gameData, ok := cache.GameCache.Cache[utils.MaskId(gameStartEvent.Game.Id)]; ok {
+func (r OutgoingEventHandlerImpl) GameStart(ctx context.Context, gameStartEvent dto.GameStartEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.GameStart")
+	defer span.End()
+	gameId, userId, err := unmaskIds(gameStartEvent.Game.Id, gameStartEvent.Game.UserId)
+	if err != nil {
+		return err
+	}
+	if gameData, ok := cache.GetGameData(gameId); ok {
 		eventBytes, err := dto.MarshalEvent(gameStartEvent, dto.GameStart)
 		if err != nil {
-			log.Error().Err(err).Msg("cannot marshal GameStartEvent")
+			tracing.Log(ctx).Error().Err(err).Msg("cannot marshal GameStartEvent")
 			return err
 		}
 
-		if gameData.Side1UserId == utils.MaskId(gameStartEvent.Game.UserId) {
-			err = gameData.Side1Socket.WriteMessage(websocket.TextMessage, eventBytes)
-		} else if gameData.Side2UserId == utils.MaskId(gameStartEvent.Game.UserId) {
-			err = gameData.Side2Socket.WriteMessage(websocket.TextMessage, eventBytes)
+		if gameData.Side1UserId == userId {
+			err = cache.WriteMessage(gameData.Side1Socket, eventBytes)
+		} else if gameData.Side2UserId == userId {
+			err = cache.WriteMessage(gameData.Side2Socket, eventBytes)
 		} else {
 			return dto.Forbidden1("user does not belong to game!")
 		}
 
 		if err != nil {
-			log.Error().Err(err).Msg("cannot send GameStartEvent")
+			tracing.Log(ctx).Error().Err(err).Msg("cannot send GameStartEvent")
+			metrics.OutgoingEventFailed(string(dto.GameStart))
 			return err
 		}
 	}
 	return nil
 }
 
-func (r OutgoingEventHandlerImpl) ChangeTurn(changeTurnEvent dto.GameChangeTurnEvent) error {
-	if gameData, ok := cache.GameCache.Cache[utils.MaskId(changeTurnEvent.GameId)]; ok {
+func (r OutgoingEventHandlerImpl) ChangeTurn(ctx context.Context, changeTurnEvent dto.GameChangeTurnEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.ChangeTurn")
+	defer span.End()
+	gameId, userId, err := unmaskIds(changeTurnEvent.GameId, changeTurnEvent.UserId)
+	if err != nil {
+		return err
+	}
+	if gameData, ok := cache.GetGameData(gameId); ok {
 
 		eventBytes, err := dto.MarshalEvent(changeTurnEvent, dto.ChangeTurn)
 		if err != nil {
-			log.Error().Err(err).Msg("cannot marshal GameChangeTurnEvent")
+			tracing.Log(ctx).Error().Err(err).Msg("cannot marshal GameChangeTurnEvent")
 			return err
 		}
 
-		if gameData.Side1UserId == utils.MaskId(changeTurnEvent.UserId) {
-			err = gameData.Side1Socket.WriteMessage(websocket.TextMessage, eventBytes)
-		} else if gameData.Side2UserId == utils.MaskId(changeTurnEvent.UserId) {
-			err = gameData.Side2Socket.WriteMessage(websocket.TextMessage, eventBytes)
+		if gameData.Side1UserId == userId {
+			err = cache.WriteMessage(gameData.Side1Socket, eventBytes)
+		} else if gameData.Side2UserId == userId {
+			err = cache.WriteMessage(gameData.Side2Socket, eventBytes)
 		} else {
-			log.Err(err).Msg("user does not belong to game!")
+			tracing.Log(ctx).Err(err).Msg("user does not belong to game!")
 			return dto.Forbidden1("user does not belong to game!")
 		}
 
 		if err != nil {
-			log.Err(err).Msg("cannot send GameChangeTurnEvent")
+			tracing.Log(ctx).Err(err).Msg("cannot send GameChangeTurnEvent")
+			metrics.OutgoingEventFailed(string(dto.ChangeTurn))
 			return err
 		}
 	}
 	return nil
 }
 
-func (r OutgoingEventHandlerImpl) MoveShip(shipMovedEvent dto.ShipMovedEvent) error {
-	if gameData, ok := cache.GameCache.Cache[utils.MaskId(shipMovedEvent.GameId)]; ok {
+func (r OutgoingEventHandlerImpl) MoveShip(ctx context.Context, shipMovedEvent dto.ShipMovedEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.MoveShip")
+	defer span.End()
+	gameId, userId, err := unmaskIds(shipMovedEvent.GameId, shipMovedEvent.UserId)
+	if err != nil {
+		return err
+	}
+	if gameData, ok := cache.GetGameData(gameId); ok {
 
 		eventBytes, err := dto.MarshalEvent(shipMovedEvent, dto.ShipMoved)
 		if err != nil {
-			log.Error().Err(err).Msg("cannot marshal ShipMovedEvent")
+			tracing.Log(ctx).Error().Err(err).Msg("cannot marshal ShipMovedEvent")
 			return err
 		}
 
-		if gameData.Side1UserId == utils.MaskId(shipMovedEvent.UserId) {
-			err = gameData.Side1Socket.WriteMessage(websocket.TextMessage, eventBytes)
-		} else if gameData.Side2UserId == utils.MaskId(shipMovedEvent.UserId) {
-			err = gameData.Side2Socket.WriteMessage(websocket.TextMessage, eventBytes)
+		if gameData.Side1UserId == userId {
+			err = cache.WriteMessage(gameData.Side1Socket, eventBytes)
+		} else if gameData.Side2UserId == userId {
+			err = cache.WriteMessage(gameData.Side2Socket, eventBytes)
 		} else {
 			return dto.Forbidden1("user does not belong to game!")
 		}
 
 		if err != nil {
-			log.Err(err).Msg("cannot send ShipMovedEvent")
+			tracing.Log(ctx).Err(err).Msg("cannot send ShipMovedEvent")
+			metrics.OutgoingEventFailed(string(dto.ShipMoved))
 		}
 	}
 	return nil
 }
 
-func (r OutgoingEventHandlerImpl) Reveal(revealEvent dto.RevealEvent) error {
-	if gameData, ok := cache.GameCache.Cache[utils.MaskId(revealEvent.GameId)]; ok {
+func (r OutgoingEventHandlerImpl) Reveal(ctx context.Context, revealEvent dto.RevealEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.Reveal")
+	defer span.End()
+	gameId, userId, err := unmaskIds(revealEvent.GameId, revealEvent.UserId)
+	if err != nil {
+		return err
+	}
+	if gameData, ok := cache.GetGameData(gameId); ok {
 
 		eventBytes, err := dto.MarshalEvent(revealEvent, dto.Reveal)
 		if err != nil {
-			log.Error().Err(err).Msg("cannot marshal RevealEvent")
+			tracing.Log(ctx).Error().Err(err).Msg("cannot marshal RevealEvent")
 			return err
 		}
 
-		if gameData.Side1UserId == utils.MaskId(revealEvent.UserId) {
-			err = gameData.Side1Socket.WriteMessage(websocket.TextMessage, eventBytes)
-		} else if gameData.Side2UserId == utils.MaskId(revealEvent.UserId) {
-			err = gameData.Side2Socket.WriteMessage(websocket.TextMessage, eventBytes)
+		if gameData.Side1UserId == userId {
+			err = cache.WriteMessage(gameData.Side1Socket, eventBytes)
+		} else if gameData.Side2UserId == userId {
+			err = cache.WriteMessage(gameData.Side2Socket, eventBytes)
 		} else {
 			return dto.Forbidden1("user does not belong to game!")
 		}
 
 		if err != nil {
-			log.Err(err).Msg("cannot send RevealEvent")
+			tracing.Log(ctx).Err(err).Msg("cannot send RevealEvent")
+			metrics.OutgoingEventFailed(string(dto.Reveal))
 		}
 	}
 	return nil
 }
 
-func (r OutgoingEventHandlerImpl) Explosion(explosionEvent dto.ExplosionEvent) error {
-	if gameData, ok := cache.GameCache.Cache[utils.MaskId(explosionEvent.GameId)]; ok {
+func (r OutgoingEventHandlerImpl) Explosion(ctx context.Context, explosionEvent dto.ExplosionEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.Explosion")
+	defer span.End()
+	gameId, userId, err := unmaskIds(explosionEvent.GameId, explosionEvent.UserId)
+	if err != nil {
+		return err
+	}
+	if gameData, ok := cache.GetGameData(gameId); ok {
 
 		eventBytes, err := dto.MarshalEvent(explosionEvent, dto.Explosion)
 		if err != nil {
-			log.Error().Err(err).Msg("cannot marshal ExplosionEvent")
+			tracing.Log(ctx).Error().Err(err).Msg("cannot marshal ExplosionEvent")
 			return err
 		}
 
-		if gameData.Side1UserId == utils.MaskId(explosionEvent.UserId) {
-			err = gameData.Side1Socket.WriteMessage(websocket.TextMessage, eventBytes)
-		} else if gameData.Side2UserId == utils.MaskId(explosionEvent.UserId) {
-			err = gameData.Side2Socket.WriteMessage(websocket.TextMessage, eventBytes)
+		if gameData.Side1UserId == userId {
+			err = cache.WriteMessage(gameData.Side1Socket, eventBytes)
+		} else if gameData.Side2UserId == userId {
+			err = cache.WriteMessage(gameData.Side2Socket, eventBytes)
 		} else {
 			return dto.Forbidden1("user does not belong to game!")
 		}
 
 		if err != nil {
-			log.Err(err).Msg("cannot send ExplosionEvent")
+			tracing.Log(ctx).Err(err).Msg("cannot send ExplosionEvent")
+			metrics.OutgoingEventFailed(string(dto.Explosion))
 		}
 	}
 	return nil
 }
 
-func (r OutgoingEventHandlerImpl) EndGame(endGameEvent dto.EndGameEvent) error {
-	if gameData, ok := cache.GameCache.Cache[utils.MaskId(endGameEvent.GameId)]; ok {
+// EndGame is sent to the sides that are connected, an admin may finish a game that one or both sides have left.
+func (r OutgoingEventHandlerImpl) EndGame(ctx context.Context, endGameEvent dto.EndGameEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.EndGame")
+	defer span.End()
+	gameId, err := utils.DecodeId(endGameEvent.GameId)
+	if err != nil {
+		tracing.Log(ctx).Error().Str("game_id", endGameEvent.GameId).Msg("invalid game id in EndGameEvent")
+		return err
+	}
+	if gameData, ok := cache.GetGameData(gameId); ok {
 
 		eventBytes, err := dto.MarshalEvent(endGameEvent, dto.EndGame)
 		if err != nil {
-			log.Error().Err(err).Msg("cannot marshal EndGameEvent")
+			tracing.Log(ctx).Error().Err(err).Msg("cannot marshal EndGameEvent")
 			return err
 		}
 
-		err = gameData.Side1Socket.WriteMessage(websocket.TextMessage, eventBytes)
+		for _, conn := range []*websocket.Conn{gameData.Side1Socket, gameData.Side2Socket} {
+			if conn == nil {
+				continue
+			}
+			err = cache.WriteMessage(conn, eventBytes)
+			if err != nil {
+				tracing.Log(ctx).Err(err).Msg("cannot send EndGameEvent")
+				metrics.OutgoingEventFailed(string(dto.EndGame))
+			}
+		}
+	}
+	return nil
+}
+
+func (r OutgoingEventHandlerImpl) MatchFound(ctx context.Context, matchFoundEvent dto.MatchFoundEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.MatchFound")
+	defer span.End()
+	eventBytes, err := dto.MarshalEvent(matchFoundEvent, dto.MatchFound)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot marshal MatchFoundEvent")
+		return err
+	}
+	return sendToUser(matchFoundEvent.UserId, dto.MatchFound, eventBytes)
+}
+
+func (r OutgoingEventHandlerImpl) MatchmakingTimeout(ctx context.Context, matchmakingTimeoutEvent dto.MatchmakingTimeoutEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.MatchmakingTimeout")
+	defer span.End()
+	eventBytes, err := dto.MarshalEvent(matchmakingTimeoutEvent, dto.MatchmakingTimeout)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot marshal MatchmakingTimeoutEvent")
+		return err
+	}
+	return sendToUser(matchmakingTimeoutEvent.UserId, dto.MatchmakingTimeout, eventBytes)
+}
+
+// GameCancelled is sent to the sides that are connected, a game may be cancelled before the second side joins.
+func (r OutgoingEventHandlerImpl) GameCancelled(ctx context.Context, gameCancelledEvent dto.GameCancelledEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.GameCancelled")
+	defer span.End()
+	gameId, err := utils.DecodeId(gameCancelledEvent.GameId)
+	if err != nil {
+		tracing.Log(ctx).Error().Str("game_id", gameCancelledEvent.GameId).Msg("invalid game id in GameCancelledEvent")
+		return err
+	}
+	if gameData, ok := cache.GetGameData(gameId); ok {
+		eventBytes, err := dto.MarshalEvent(gameCancelledEvent, dto.GameCancelled)
+		if err != nil {
+			tracing.Log(ctx).Error().Err(err).Msg("cannot marshal GameCancelledEvent")
+			return err
+		}
+
+		for _, conn := range []*websocket.Conn{gameData.Side1Socket, gameData.Side2Socket} {
+			if conn == nil {
+				continue
+			}
+			err = cache.WriteMessage(conn, eventBytes)
+			if err != nil {
+				tracing.Log(ctx).Err(err).Msg("cannot send GameCancelledEvent")
+				metrics.OutgoingEventFailed(string(dto.GameCancelled))
+			}
+		}
+	}
+	return nil
+}
+
+// Pause sends pause and resume events to both sides of the game.
+func (r OutgoingEventHandlerImpl) Pause(ctx context.Context, eventType dto.SocketEventType, pauseEvent dto.PauseEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.Pause")
+	defer span.End()
+	gameId, err := utils.DecodeId(pauseEvent.GameId)
+	if err != nil {
+		tracing.Log(ctx).Error().Str("game_id", pauseEvent.GameId).Msg("invalid game id in PauseEvent")
+		return err
+	}
+	if gameData, ok := cache.GetGameData(gameId); ok {
+		eventBytes, err := dto.MarshalEvent(pauseEvent, eventType)
+		if err != nil {
+			tracing.Log(ctx).Error().Err(err).Msg("cannot marshal PauseEvent")
+			return err
+		}
+
+		for _, conn := range []*websocket.Conn{gameData.Side1Socket, gameData.Side2Socket} {
+			if conn == nil {
+				continue
+			}
+			err = cache.WriteMessage(conn, eventBytes)
+			if err != nil {
+				tracing.Log(ctx).Err(err).Str("event_type", string(eventType)).Msg("cannot send PauseEvent")
+				metrics.OutgoingEventFailed(string(eventType))
+			}
+		}
+	}
+	return nil
+}
+
+// ChatMessage is sent to the socket of one side, the chat service decides who receives it.
+func (r OutgoingEventHandlerImpl) ChatMessage(ctx context.Context, gameId string, userId string, chatMessage dto.ChatMessageDto) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.ChatMessage")
+	defer span.End()
+	eventBytes, err := dto.MarshalEvent(chatMessage, dto.ChatMessage)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot marshal ChatMessageDto")
+		return err
+	}
+	return sendToSide(gameId, userId, dto.ChatMessage, eventBytes)
+}
+
+func (r OutgoingEventHandlerImpl) ChatHistory(ctx context.Context, userId string, chatHistoryEvent dto.ChatHistoryEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.ChatHistory")
+	defer span.End()
+	eventBytes, err := dto.MarshalEvent(chatHistoryEvent, dto.ChatHistory)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot marshal ChatHistoryEvent")
+		return err
+	}
+	return sendToSide(chatHistoryEvent.GameId, userId, dto.ChatHistory, eventBytes)
+}
+
+func (r OutgoingEventHandlerImpl) ChatError(ctx context.Context, userId string, chatErrorEvent dto.ChatErrorEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.ChatError")
+	defer span.End()
+	eventBytes, err := dto.MarshalEvent(chatErrorEvent, dto.ChatError)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot marshal ChatErrorEvent")
+		return err
+	}
+	return sendToSide(chatErrorEvent.GameId, userId, dto.ChatError, eventBytes)
+}
+
+// LobbyUpdate is broadcast to all lobby sockets, a failed socket does not stop the others.
+func (r OutgoingEventHandlerImpl) LobbyUpdate(ctx context.Context, lobbyUpdateEvent dto.LobbyUpdateEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.LobbyUpdate")
+	defer span.End()
+	eventBytes, err := dto.MarshalEvent(lobbyUpdateEvent, dto.LobbyUpdate)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot marshal LobbyUpdateEvent")
+		return err
+	}
+	for _, conn := range cache.GetLobbySockets() {
+		err = cache.WriteMessage(conn, eventBytes)
+		if err != nil {
+			tracing.Log(ctx).Err(err).Msg("cannot send LobbyUpdateEvent")
+			metrics.OutgoingEventFailed(string(dto.LobbyUpdate))
+		}
+	}
+	return nil
+}
+
+// ServerRestarting is broadcast to game, user and lobby sockets on shutdown.
+func (r OutgoingEventHandlerImpl) ServerRestarting(ctx context.Context, serverRestartingEvent dto.ServerRestartingEvent) error {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.ServerRestarting")
+	defer span.End()
+	eventBytes, err := dto.MarshalEvent(serverRestartingEvent, dto.ServerRestarting)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot marshal ServerRestartingEvent")
+		return err
+	}
+	for _, conn := range cache.AllSockets() {
+		err = cache.WriteMessage(conn, eventBytes)
 		if err != nil {
-			log.Err(err).Msg("cannot send EndGameEvent")
+			tracing.Log(ctx).Err(err).Msg("cannot send ServerRestartingEvent")
+			metrics.OutgoingEventFailed(string(dto.ServerRestarting))
 		}
+	}
+	return nil
+}
 
-		err = gameData.Side2Socket.WriteMessage(websocket.TextMessage, eventBytes)
+// ServerNotice is broadcast to game, user and lobby sockets, it returns the number of sockets it is sent to.
+func (r OutgoingEventHandlerImpl) ServerNotice(ctx context.Context, serverNoticeEvent dto.ServerNoticeEvent) (sockets int, err error) {
+	ctx, span := tracing.Start(ctx, "OutgoingEventHandler.ServerNotice")
+	defer span.End()
+	eventBytes, err := dto.MarshalEvent(serverNoticeEvent, dto.ServerNotice)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot marshal ServerNoticeEvent")
+		return 0, err
+	}
+	for _, conn := range cache.AllSockets() {
+		err = cache.WriteMessage(conn, eventBytes)
 		if err != nil {
-			log.Err(err).Msg("cannot send EndGameEvent")
+			tracing.Log(ctx).Err(err).Msg("cannot send ServerNoticeEvent")
+			metrics.OutgoingEventFailed(string(dto.ServerNotice))
+			continue
+		}
+		sockets++
+	}
+	return sockets, nil
+}
+
+func sendToUser(publicUserId string, eventType dto.SocketEventType, eventBytes []byte) error {
+	userId, err := utils.DecodeId(publicUserId)
+	if err != nil {
+		log.Error().Str("user_id", publicUserId).Msg("invalid user id in outgoing event")
+		return err
+	}
+	if conn, ok := cache.GetUserSocket(userId); ok {
+		err = cache.WriteMessage(conn, eventBytes)
+		if err != nil {
+			log.Err(err).Str("user_id", userId).Msg("cannot send event to user socket")
+			metrics.OutgoingEventFailed(string(eventType))
+			return err
 		}
 	}
 	return nil
 }
+
+// sendToSide writes the event to the game socket of one side, nothing is sent if the side is not connected.
+func sendToSide(publicGameId string, publicUserId string, eventType dto.SocketEventType, eventBytes []byte) error {
+	gameId, userId, err := unmaskIds(publicGameId, publicUserId)
+	if err != nil {
+		return err
+	}
+	gameData, ok := cache.GetGameData(gameId)
+	if !ok {
+		return nil
+	}
+	var conn *websocket.Conn
+	switch userId {
+	case gameData.Side1UserId:
+		conn = gameData.Side1Socket
+	case gameData.Side2UserId:
+		conn = gameData.Side2Socket
+	}
+	if conn == nil {
+		return nil
+	}
+	err = cache.WriteMessage(conn, eventBytes)
+	if err != nil {
+		log.Err(err).Str("game_id", gameId).Str("user_id", userId).Msg("cannot send event to game socket")
+		metrics.OutgoingEventFailed(string(eventType))
+		return err
+	}
+	return nil
+}
+
+func unmaskIds(publicGameId string, publicUserId string) (gameId string, userId string, err error) {
+	gameId, err = utils.DecodeId(publicGameId)
+	if err != nil {
+		log.Error().Str("game_id", publicGameId).Msg("invalid game id in outgoing event")
+		return "", "", err
+	}
+	userId, err = utils.DecodeId(publicUserId)
+	if err != nil {
+		log.Error().Str("user_id", publicUserId).Msg("invalid user id in outgoing event")
+		return "", "", err
+	}
+	return gameId, userId, nil
+}
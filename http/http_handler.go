@@ -8,13 +8,15 @@ import (
 	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog/log"
 	echoSwagger "github.com/swaggo/echo-swagger"
 	"github.com/ziflex/lecho/v2"
 	"net/http"
 )
 
-func StartHttpServer() {
+// StartHttpServer starts serving in the background, the returned echo is used to shut the server down.
+func StartHttpServer() *echo.Echo {
 	e := echo.New()
 	e.HideBanner = true
 	setHttpMiddlewares(e)
@@ -24,11 +26,18 @@ func StartHttpServer() {
 	httpConfig := &http.Server{
 		Addr: fmt.Sprintf(":%s", config.C.HttpPort),
 	}
-	log.Fatal().Err(e.StartServer(httpConfig)).Msg("")
+	go func() {
+		err := e.StartServer(httpConfig)
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal().Err(err).Msg("")
+		}
+	}()
+	return e
 }
 
 func setHttpMiddlewares(e *echo.Echo) {
 	e.Use(middleware.BodyDump(middlewares.BodyDumper))
+	e.Use(middlewares.TraceMiddleware())
 	e.Use(middlewares.LogMiddleware())
 	if config.C.Cors.Domain == "*" {
 		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
@@ -46,24 +55,72 @@ func setHttpMiddlewares(e *echo.Echo) {
 }
 
 var (
-	gameController = di.CreateGameController()
-	userController = di.CreateUserController()
-	socketHandler  = di.CreateSocketHandler()
+	gameController        = di.CreateGameController()
+	userController        = di.CreateUserController()
+	authController        = di.CreateAuthController()
+	matchmakingController = di.CreateMatchmakingController()
+	leaderboardController = di.CreateLeaderboardController()
+	statisticsController  = di.CreateStatisticsController()
+	lobbyController       = di.CreateLobbyController()
+	adminController       = di.CreateAdminController()
+	healthController      = di.CreateHealthController()
+	socketHandler         = di.CreateSocketHandler()
 )
 
 func setHttpEndpoints(e *echo.Echo) {
 	e.GET("/api/v1/check-health", controllers.CheckHealth)
+	e.GET("/livez", healthController.Livez)
+	e.GET("/readyz", healthController.Readyz)
 	e.GET("/api/v1/error", controllers.Error)
 	e.POST("/api/v1/game", gameController.CreateGame)
 	e.POST("/api/v1/game/join", gameController.JoinGame)
+	e.POST("/api/v1/game/cancel", gameController.CancelGame)
+	e.POST("/api/v1/game/pause", gameController.RequestPause)
+	e.POST("/api/v1/game/pause/accept", gameController.AcceptPause)
+	e.POST("/api/v1/game/resume", gameController.Resume)
+	e.POST("/api/v1/game/invite", gameController.CreateInvite)
+	e.POST("/api/v1/game/invite/revoke", gameController.RevokeInvite)
 	e.POST("/api/v1/game/submit-ships", gameController.SubmitShipsLocations)
 	e.POST("/api/v1/game/change-turn", gameController.ChangeTurn)
 	e.POST("/api/v1/game/move-ship", gameController.MoveShip)
 	e.POST("/api/v1/game/reveal", gameController.RevealEnemyFields)
 	e.POST("/api/v1/game/explode", gameController.Explode)
 	e.GET("/api/v1/game/:game_id", gameController.GetGame)
+	e.GET("/api/v1/lobby", lobbyController.GetLobby)
 	e.POST("/api/v1/user", userController.CreateUser)
 	e.GET("/api/v1/user/:user_id", userController.GetUser)
+	e.GET("/api/v1/user/:user_id/rating-history", userController.GetRatingHistory)
+	e.GET("/api/v1/user/:user_id/stats", statisticsController.GetUserStats)
+	e.GET("/api/v1/user/:user_id/games", gameController.GetUserGames)
+	e.POST("/api/v1/user/:user_id/block", userController.BlockUser)
+	e.POST("/api/v1/user/:user_id/unblock", userController.UnblockUser)
+	e.GET("/api/v1/user/:user_id/blocks", userController.GetBlocks)
+	e.POST("/api/v1/report", userController.ReportUser)
+	e.POST("/api/v1/auth/otp", authController.RequestOtp)
+	e.POST("/api/v1/auth/otp/verify", authController.VerifyOtp)
+	e.POST("/api/v1/matchmaking/enqueue", matchmakingController.Enqueue)
+	e.POST("/api/v1/matchmaking/cancel", matchmakingController.Cancel)
+	e.GET("/api/v1/leaderboard", leaderboardController.GetLeaderboard)
+	e.GET("/api/v1/leaderboard/seasons", leaderboardController.GetSeasons)
+	admin := e.Group("/api/admin", middlewares.AdminAuth())
+	admin.POST("/webhook/redeliver", adminController.RedeliverWebhook)
+	admin.GET("/maintenance", adminController.GetMaintenance)
+	admin.PUT("/maintenance", adminController.SetMaintenance)
+	admin.POST("/broadcast", adminController.Broadcast)
+	admin.GET("/game/live", adminController.GetLiveGames)
+	admin.GET("/game/:game_id", adminController.GetGame)
+	admin.POST("/game/finish", adminController.FinishGame)
+	admin.POST("/game/cancel", adminController.CancelGame)
+	admin.POST("/user/ban", adminController.BanUser)
+	admin.POST("/user/unban", adminController.UnbanUser)
+	admin.GET("/audit", adminController.GetAudit)
+	admin.GET("/report", adminController.GetReports)
+	admin.POST("/report/review", adminController.ReviewReport)
+	admin.GET("/cheat-flag", adminController.GetCheatFlags)
+	admin.POST("/cheat-flag/review", adminController.ReviewCheatFlag)
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 	e.GET("/socket", socketHandler.CreateSocket)
+	e.GET("/socket/user", socketHandler.CreateUserSocket)
+	e.GET("/socket/lobby", socketHandler.CreateLobbySocket)
 }
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"battleship/cache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"strconv"
+	"time"
+)
+
+const namespace = "battleship"
+
+const (
+	GameSocket  = "game"
+	UserSocket  = "user"
+	LobbySocket = "lobby"
+)
+
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of http requests by route, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	socketConnections = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "socket_connections",
+		Help:      "Open websocket connections by socket type.",
+	}, []string{"socket"})
+
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "connected_games",
+		Help:      "Games in the connection registry.",
+	}, func() float64 {
+		return float64(cache.GameCount())
+	})
+
+	gamesCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "games_created_total",
+		Help:      "Created games.",
+	})
+
+	gamesStarted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "games_started_total",
+		Help:      "Started games.",
+	})
+
+	gamesFinished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "games_finished_total",
+		Help:      "Finished games by end reason.",
+	}, []string{"end_reason"})
+
+	gameActions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "game_actions_total",
+		Help:      "Game actions by type.",
+	}, []string{"action"})
+
+	daoDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "dao_duration_seconds",
+		Help:      "Latency of dao operations.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"dao", "operation"})
+
+	outgoingEventFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outgoing_event_failures_total",
+		Help:      "Outgoing socket events that could not be sent, by event type.",
+	}, []string{"event_type"})
+)
+
+// ObserveHttpRequest records a request by its route pattern, e.g. /api/v1/game/:game_id, so ids do not make new series.
+func ObserveHttpRequest(route string, method string, status int, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(route, method, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+func SocketOpened(socket string) {
+	socketConnections.WithLabelValues(socket).Inc()
+}
+
+func SocketClosed(socket string) {
+	socketConnections.WithLabelValues(socket).Dec()
+}
+
+func GameCreated() {
+	gamesCreated.Inc()
+}
+
+func GameStarted() {
+	gamesStarted.Inc()
+}
+
+func GameFinished(endReason string) {
+	gamesFinished.WithLabelValues(endReason).Inc()
+}
+
+func GameAction(action string) {
+	gameActions.WithLabelValues(action).Inc()
+}
+
+// ObserveDao is deferred at the start of a dao operation: defer metrics.ObserveDao("game", "get_one", time.Now())
+func ObserveDao(dao string, operation string, start time.Time) {
+	daoDuration.WithLabelValues(dao, operation).Observe(time.Since(start).Seconds())
+}
+
+func OutgoingEventFailed(eventType string) {
+	outgoingEventFailures.WithLabelValues(eventType).Inc()
+}
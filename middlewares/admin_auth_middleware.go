@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"battleship/config"
+	"battleship/dto"
+	"crypto/subtle"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	AdminTokenHeader = "X-Admin-Token"
+	AdminActorHeader = "X-Admin-Actor"
+)
+
+// AdminAuth lets a request in only if it has the admin token of the config. Admin endpoints are closed when no token
+// is configured.
+func AdminAuth() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token := c.Request().Header.Get(AdminTokenHeader)
+			if config.C.Admin.Token == "" ||
+				subtle.ConstantTimeCompare([]byte(token), []byte(config.C.Admin.Token)) != 1 {
+				log.Warn().Str("uri", c.Request().RequestURI).Str("real_ip", c.RealIP()).Msg("admin token is not valid")
+				return dto.Unauthorized("admin token is not valid")
+			}
+			return next(c)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package middlewares
 
 import (
 	"battleship/dto"
+	"battleship/metrics"
+	"battleship/tracing"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -22,6 +24,11 @@ func LogMiddleware() echo.MiddlewareFunc {
 					dto.CustomHTTPErrorHandler(err, c)
 				}
 				stop := time.Now()
+				route := c.Path()
+				if route == "" {
+					route = "unmatched"
+				}
+				metrics.ObserveHttpRequest(route, req.Method, res.Status, stop.Sub(start))
 
 				bytesIn := req.Header.Get(echo.HeaderContentLength)
 				if bytesIn == "" {
@@ -32,13 +39,13 @@ func LogMiddleware() echo.MiddlewareFunc {
 
 				switch {
 				case 200 <= res.Status && res.Status < 300:
-					logg = log.Debug()
+					logg = tracing.Log(req.Context()).Debug()
 				case 300 <= res.Status && res.Status < 400, 0 < res.Status && res.Status < 200:
-					logg = log.Info()
+					logg = tracing.Log(req.Context()).Info()
 				case 500 <= res.Status:
-					logg = log.Error()
+					logg = tracing.Log(req.Context()).Error()
 				default:
-					logg = log.Info()
+					logg = tracing.Log(req.Context()).Info()
 				}
 
 				event := logg.
@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"battleship/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"strings"
+)
+
+// TraceMiddleware starts a span for each api request and keeps it in the request context, so services and daos add
+// their spans under it. Sockets are skipped, their messages have their own spans.
+func TraceMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !strings.HasPrefix(c.Request().RequestURI, "/api/v") {
+				return next(c)
+			}
+			req := c.Request()
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := tracing.Start(ctx, req.Method+" "+c.Path(),
+				semconv.HTTPMethodKey.String(req.Method),
+				semconv.HTTPRouteKey.String(c.Path()),
+				semconv.HTTPTargetKey.String(req.RequestURI))
+			defer span.End()
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			status := c.Response().Status
+			span.SetAttributes(attribute.Int(string(semconv.HTTPStatusCodeKey), status))
+			if err != nil || status >= 500 {
+				if err != nil {
+					span.RecordError(err)
+				}
+				span.SetStatus(codes.Error, "")
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// AbandonmentPolicy decides if a side of a started game has left it. A zero limit disables that rule.
+type AbandonmentPolicy struct {
+	MaxMissedTurns     int
+	MaxDisconnectedSec int
+}
+
+// SidePresence is the state of a side that the policy is applied to. DisconnectedSince is meaningful only when
+// Connected is false.
+type SidePresence struct {
+	MissedTurns       int
+	Connected         bool
+	DisconnectedSince time.Time
+}
+
+// IsAbandoned depends only on its arguments, so the same presence and now always give the same decision.
+func (p AbandonmentPolicy) IsAbandoned(side SidePresence, now time.Time) bool {
+	if p.MaxMissedTurns > 0 && side.MissedTurns >= p.MaxMissedTurns {
+		return true
+	}
+	if p.MaxDisconnectedSec > 0 && !side.Connected &&
+		!now.Before(side.DisconnectedSince.Add(time.Duration(p.MaxDisconnectedSec)*time.Second)) {
+		return true
+	}
+	return false
+}
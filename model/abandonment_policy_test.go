@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAbandonmentPolicyIsAbandoned(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := AbandonmentPolicy{MaxMissedTurns: 3, MaxDisconnectedSec: 60}
+	tests := []struct {
+		name   string
+		policy AbandonmentPolicy
+		side   SidePresence
+		want   bool
+	}{
+		{"connected without missed turns", policy, SidePresence{Connected: true}, false},
+		{"missed turns below limit", policy, SidePresence{MissedTurns: 2, Connected: true}, false},
+		{"missed turns at limit", policy, SidePresence{MissedTurns: 3, Connected: true}, true},
+		{"missed turns above limit", policy, SidePresence{MissedTurns: 4, Connected: true}, true},
+		{"disconnected shorter than limit", policy,
+			SidePresence{DisconnectedSince: now.Add(-59 * time.Second)}, false},
+		{"disconnected exactly the limit", policy,
+			SidePresence{DisconnectedSince: now.Add(-60 * time.Second)}, true},
+		{"disconnected longer than limit", policy,
+			SidePresence{DisconnectedSince: now.Add(-time.Hour)}, true},
+		{"old disconnect time of a connected side", policy,
+			SidePresence{Connected: true, DisconnectedSince: now.Add(-time.Hour)}, false},
+		{"missed turns rule disabled", AbandonmentPolicy{MaxDisconnectedSec: 60},
+			SidePresence{MissedTurns: 100, Connected: true}, false},
+		{"disconnect rule disabled", AbandonmentPolicy{MaxMissedTurns: 3},
+			SidePresence{DisconnectedSince: now.Add(-time.Hour)}, false},
+		{"both rules disabled", AbandonmentPolicy{},
+			SidePresence{MissedTurns: 100, DisconnectedSince: now.Add(-time.Hour)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.IsAbandoned(tt.side, now); got != tt.want {
+				t.Errorf("IsAbandoned() = %v, want %v", got, tt.want)
+			}
+			// the decision depends only on the arguments
+			if got := tt.policy.IsAbandoned(tt.side, now); got != tt.want {
+				t.Errorf("second IsAbandoned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
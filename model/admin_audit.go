@@ -0,0 +1,34 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type AdminAction string
+
+const (
+	SetMaintenanceAdminAction   AdminAction = "set_maintenance"
+	BroadcastAdminAction        AdminAction = "broadcast"
+	RedeliverWebhookAdminAction AdminAction = "redeliver_webhook"
+	FinishGameAdminAction       AdminAction = "finish_game"
+	CancelGameAdminAction       AdminAction = "cancel_game"
+	BanUserAdminAction          AdminAction = "ban_user"
+	UnbanUserAdminAction        AdminAction = "unban_user"
+	ReviewReportAdminAction     AdminAction = "review_report"
+	ReviewCheatFlagAdminAction  AdminAction = "review_cheat_flag"
+)
+
+// AdminAudit records an action of an admin. Actor is the name the admin sends with the request, the admin token is
+// shared so it is not verified. Error is set when the action failed.
+type AdminAudit struct {
+	Id       primitive.ObjectID `bson:"_id,omitempty"`
+	Action   AdminAction        `bson:"action"`
+	TargetId string             `bson:"target_id,omitempty"`
+	Reason   string             `bson:"reason,omitempty"`
+	Data     map[string]string  `bson:"data,omitempty"`
+	Actor    string             `bson:"actor,omitempty"`
+	RemoteIp string             `bson:"remote_ip,omitempty"`
+	Error    string             `bson:"error,omitempty"`
+	Time     time.Time          `bson:"time"`
+}
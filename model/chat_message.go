@@ -0,0 +1,24 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// ChatMessage has either Text or Emote. Filtered is set when the profanity filter has changed Text.
+type ChatMessage struct {
+	Id       primitive.ObjectID `bson:"_id,omitempty"`
+	GameId   primitive.ObjectID `bson:"game_id"`
+	UserId   primitive.ObjectID `bson:"user_id"`
+	Text     string             `bson:"text,omitempty"`
+	Emote    string             `bson:"emote,omitempty"`
+	Filtered bool               `bson:"filtered,omitempty"`
+	Time     time.Time          `bson:"time"`
+}
+
+// ChatMute means UserId does not receive chat messages of the opponent in GameId.
+type ChatMute struct {
+	Id     primitive.ObjectID `bson:"_id,omitempty"`
+	GameId primitive.ObjectID `bson:"game_id"`
+	UserId primitive.ObjectID `bson:"user_id"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type CheatKind string
+
+const (
+	FastReactionCheat CheatKind = "fast_reaction"
+	HitRateCheat      CheatKind = "hit_rate"
+	CollusionCheat    CheatKind = "collusion"
+)
+
+// CheatFlag is a suspicion of the anti-cheat analysis about a user, OtherUserId is the opponent of a collusion flag.
+// Evidence of later games is added to the pending flag of the same user and kind, so a user has one flag to review
+// at a time. Its status is a ReportStatus like user reports.
+type CheatFlag struct {
+	Id          primitive.ObjectID  `bson:"_id,omitempty"`
+	UserId      primitive.ObjectID  `bson:"user_id"`
+	OtherUserId *primitive.ObjectID `bson:"other_user_id"`
+	Kind        CheatKind           `bson:"kind"`
+	Evidence    []CheatEvidence     `bson:"evidence"`
+	Status      ReportStatus        `bson:"status"`
+	CreateDate  time.Time           `bson:"create_date"`
+	UpdateDate  time.Time           `bson:"update_date"`
+	ReviewNote  string              `bson:"review_note,omitempty"`
+	ReviewedBy  string              `bson:"reviewed_by,omitempty"`
+	ReviewDate  *time.Time          `bson:"review_date,omitempty"`
+}
+
+// CheatEvidence is what a game showed. Reaction times are from the previous event of the game to a shot, blind shots
+// are shots at cells that were not revealed and ExpectedHits is what chance gives for them. Games, Wins and
+// QuickLosses are of the pair in the analysis window.
+type CheatEvidence struct {
+	GameId           primitive.ObjectID `bson:"game_id"`
+	Time             time.Time          `bson:"time"`
+	Shots            int                `bson:"shots,omitempty"`
+	FastShots        int                `bson:"fast_shots,omitempty"`
+	MinReactionMs    int64              `bson:"min_reaction_ms,omitempty"`
+	MedianReactionMs int64              `bson:"median_reaction_ms,omitempty"`
+	BlindShots       int                `bson:"blind_shots,omitempty"`
+	BlindHits        int                `bson:"blind_hits,omitempty"`
+	ExpectedHits     float64            `bson:"expected_hits,omitempty"`
+	ZScore           float64            `bson:"z_score,omitempty"`
+	Games            int                `bson:"games,omitempty"`
+	Wins             int                `bson:"wins,omitempty"`
+	QuickLosses      int                `bson:"quick_losses,omitempty"`
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+)
+
+// CoinToss decides the first turn of a game. ServerSeed is drawn when the game is created and only its sha256,
+// ServerCommitment, is shown until the toss is done. Each side commits to a seed with its sha256 when it joins the
+// game and reveals the seed when it submits its ships, so neither the server nor a side can pick the result alone.
+type CoinToss struct {
+	ServerSeed       string `bson:"server_seed"`
+	ServerCommitment string `bson:"server_commitment"`
+	Side1Commitment  string `bson:"side_1_commitment,omitempty"`
+	Side2Commitment  string `bson:"side_2_commitment,omitempty"`
+	Side1Seed        string `bson:"side_1_seed,omitempty"`
+	Side2Seed        string `bson:"side_2_seed,omitempty"`
+	Result           string `bson:"result,omitempty"`
+}
+
+func NewCoinToss() (*CoinToss, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	seed := hex.EncodeToString(b)
+	return &CoinToss{ServerSeed: seed, ServerCommitment: sha256Hex(seed)}, nil
+}
+
+// CoinTossResult returns the hex sha256 of "serverSeed:side1Seed:side2Seed".
+func CoinTossResult(serverSeed string, side1Seed string, side2Seed string) string {
+	return sha256Hex(serverSeed + ":" + side1Seed + ":" + side2Seed)
+}
+
+// CoinTossTurn returns the side that starts for a result: 1 when its first byte is even, otherwise 2.
+func CoinTossTurn(result string) int {
+	b, err := hex.DecodeString(result)
+	if err != nil || len(b) == 0 {
+		return 1
+	}
+	return int(b[0]%2) + 1
+}
+
+// TossSeedCommitment returns the hex sha256 of a seed, what a side commits to before it reveals the seed.
+func TossSeedCommitment(seed string) string {
+	return sha256Hex(seed)
+}
+
+// RandomTurn returns side 1 or 2 at random, it is the first turn of games that have no coin toss.
+func RandomTurn() int {
+	b := make([]byte, 1)
+	if _, err := rand.Read(b); err != nil {
+		return 1
+	}
+	return int(b[0]%2) + 1
+}
+
+func (r CoinToss) Done() bool {
+	return r.Result != ""
+}
+
+// Commit sets the commitment of side 1 or 2.
+func (r *CoinToss) Commit(side int, commitment string) {
+	if side == 1 {
+		r.Side1Commitment = strings.ToLower(commitment)
+	} else {
+		r.Side2Commitment = strings.ToLower(commitment)
+	}
+}
+
+// Reveal sets the seed of side 1 or 2 if it matches the commitment of the side. A side without a commitment cannot
+// add a seed.
+func (r *CoinToss) Reveal(side int, seed string) bool {
+	commitment := r.Side1Commitment
+	if side == 2 {
+		commitment = r.Side2Commitment
+	}
+	if commitment == "" {
+		return seed == ""
+	}
+	if subtle.ConstantTimeCompare([]byte(TossSeedCommitment(seed)), []byte(commitment)) != 1 {
+		return false
+	}
+	if side == 1 {
+		r.Side1Seed = seed
+	} else {
+		r.Side2Seed = seed
+	}
+	return true
+}
+
+// Toss sets the result from the seeds and returns the side that starts.
+func (r *CoinToss) Toss() int {
+	r.Result = CoinTossResult(r.ServerSeed, r.Side1Seed, r.Side2Seed)
+	return CoinTossTurn(r.Result)
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNewCoinToss(t *testing.T) {
+	toss, err := NewCoinToss()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(toss.ServerSeed))
+	if toss.ServerCommitment != hex.EncodeToString(sum[:]) {
+		t.Errorf("server commitment %s is not the sha256 of the server seed", toss.ServerCommitment)
+	}
+	if toss.Done() {
+		t.Error("new toss is done")
+	}
+	other, err := NewCoinToss()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ServerSeed == toss.ServerSeed {
+		t.Error("two tosses have the same server seed")
+	}
+}
+
+func TestCoinTossTurn(t *testing.T) {
+	tests := []struct {
+		result string
+		want   int
+	}{
+		{"00ff", 1},
+		{"01ff", 2},
+		{"fe", 1},
+		{"ff", 2},
+		{"", 1},
+		{"not hex", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.result, func(t *testing.T) {
+			if got := CoinTossTurn(tt.result); got != tt.want {
+				t.Errorf("CoinTossTurn() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoinTossReveal(t *testing.T) {
+	tests := []struct {
+		name       string
+		commitment string
+		seed       string
+		want       bool
+	}{
+		{"matching seed", TossSeedCommitment("seed of side"), "seed of side", true},
+		{"upper case commitment", strings.ToUpper(TossSeedCommitment("seed of side")), "seed of side", true},
+		{"other seed", TossSeedCommitment("seed of side"), "other seed", false},
+		{"committed but no seed", TossSeedCommitment("seed of side"), "", false},
+		{"no commitment and no seed", "", "", true},
+		{"seed without commitment", "", "seed of side", false},
+	}
+	for _, tt := range tests {
+		for _, side := range []int{1, 2} {
+			t.Run(fmt.Sprintf("%s side %d", tt.name, side), func(t *testing.T) {
+				toss := CoinToss{}
+				toss.Commit(side, tt.commitment)
+				if got := toss.Reveal(side, tt.seed); got != tt.want {
+					t.Errorf("Reveal() = %v, want %v", got, tt.want)
+				}
+				seed := toss.Side1Seed
+				if side == 2 {
+					seed = toss.Side2Seed
+				}
+				if tt.want && seed != tt.seed || !tt.want && seed != "" {
+					t.Errorf("seed is %q after Reveal()", seed)
+				}
+			})
+		}
+	}
+}
+
+func TestCoinTossToss(t *testing.T) {
+	toss := CoinToss{ServerSeed: "server"}
+	toss.Commit(1, TossSeedCommitment("one"))
+	toss.Commit(2, TossSeedCommitment("two"))
+	if !toss.Reveal(1, "one") || !toss.Reveal(2, "two") {
+		t.Fatal("seeds are not revealed")
+	}
+	turn := toss.Toss()
+
+	sum := sha256.Sum256([]byte("server:one:two"))
+	want := hex.EncodeToString(sum[:])
+	if toss.Result != want {
+		t.Errorf("Result = %s, want %s", toss.Result, want)
+	}
+	if !toss.Done() {
+		t.Error("toss is not done")
+	}
+	if wantTurn := int(sum[0]%2) + 1; turn != wantTurn {
+		t.Errorf("Toss() = %d, want %d", turn, wantTurn)
+	}
+	if turn != CoinTossTurn(toss.Result) {
+		t.Error("Toss() and CoinTossTurn() of the result differ")
+	}
+}
+
+func TestRandomTurn(t *testing.T) {
+	seen := map[int]bool{}
+	for i := 0; i < 200; i++ {
+		turn := RandomTurn()
+		if turn != 1 && turn != 2 {
+			t.Fatalf("RandomTurn() = %d", turn)
+		}
+		seen[turn] = true
+	}
+	if len(seen) != 2 {
+		t.Errorf("RandomTurn() gave only %v in 200 calls", seen)
+	}
+}
@@ -10,23 +10,72 @@ import (
 type GameStatus string
 
 const (
-	Init     GameStatus = "init"
-	Joined   GameStatus = "joined"
-	Start    GameStatus = "start"
-	Finished GameStatus = "finished"
+	Init      GameStatus = "init"
+	Joined    GameStatus = "joined"
+	Start     GameStatus = "start"
+	Paused    GameStatus = "paused"
+	Finished  GameStatus = "finished"
+	Cancelled GameStatus = "cancelled"
 )
 
+type EndReason string
+
+const (
+	AllShipsDestroyed EndReason = "all_ships_destroyed"
+	Abandoned         EndReason = "abandoned"
+	Timeout           EndReason = "timeout"
+	CancelledByUser   EndReason = "cancelled_by_user"
+	Expired           EndReason = "expired"
+	FinishedByAdmin   EndReason = "finished_by_admin"
+	CancelledByAdmin  EndReason = "cancelled_by_admin"
+)
+
+const (
+	DefaultBoardSize = 10
+	ClassicRuleset   = "classic"
+)
+
+type GameSettings struct {
+	BoardSize      int
+	MoveTimeoutSec int
+	Ruleset        string
+	Ranked         bool
+	Public         bool
+	CommitReveal   bool
+}
+
 type Game struct {
-	Id             primitive.ObjectID  `bson:"_id,omitempty"`
-	State          GameState           `bson:"state"`
-	LastMoveTime   time.Time           `bson:"last_move_time"`
-	Status         GameStatus          `bson:"status"`
-	Side1User      *primitive.ObjectID `bson:"side_1_user"`
-	Side2User      *primitive.ObjectID `bson:"side_2_user"`
-	Turn           int                 `bson:"turn"`
-	MoveTimeoutSec int                 `bson:"move_timeout_sec"`
-	CreateDate     time.Time           `bson:"create_date"`
-	WinnerUser     *primitive.ObjectID `bson:"winner_user"`
+	Id             primitive.ObjectID   `bson:"_id,omitempty"`
+	State          GameState            `bson:"state"`
+	LastMoveTime   time.Time            `bson:"last_move_time"`
+	Status         GameStatus           `bson:"status"`
+	Side1User      *primitive.ObjectID  `bson:"side_1_user"`
+	Side2User      *primitive.ObjectID  `bson:"side_2_user"`
+	Turn           int                  `bson:"turn"`
+	MoveTimeoutSec int                  `bson:"move_timeout_sec"`
+	CreateDate     time.Time            `bson:"create_date"`
+	WinnerUser     *primitive.ObjectID  `bson:"winner_user"`
+	BoardSize      int                  `bson:"board_size,omitempty"`
+	Ruleset        string               `bson:"ruleset,omitempty"`
+	Ranked         bool                 `bson:"ranked,omitempty"`
+	EndReason      EndReason            `bson:"end_reason,omitempty"`
+	EndDate        *time.Time           `bson:"end_date,omitempty"`
+	RatingApplied  bool                 `bson:"rating_applied,omitempty"`
+	MoveCount      int                  `bson:"move_count,omitempty"`
+	Public         bool                 `bson:"public,omitempty"`
+	PasscodeHash   string               `bson:"passcode_hash,omitempty"`
+	PasscodeFails  int                  `bson:"passcode_failures,omitempty"`
+	Side1Missed    int                  `bson:"side_1_missed_turns"`
+	Side2Missed    int                  `bson:"side_2_missed_turns"`
+	PauseBy        *primitive.ObjectID  `bson:"pause_requested_by,omitempty"`
+	ResumeBy       *primitive.ObjectID  `bson:"resume_requested_by,omitempty"`
+	PausedAt       *time.Time           `bson:"paused_at,omitempty"`
+	PauseDeadline  *time.Time           `bson:"pause_deadline,omitempty"`
+	CommitReveal   bool                 `bson:"commit_reveal,omitempty"`
+	Side1Placement *PlacementCommitment `bson:"side_1_placement,omitempty"`
+	Side2Placement *PlacementCommitment `bson:"side_2_placement,omitempty"`
+	Toss           *CoinToss            `bson:"toss,omitempty"`
+	CheatChecked   bool                 `bson:"cheat_checked,omitempty"`
 }
 
 func (g *Game) MoveShipSide1(from int, to int) error {
@@ -92,8 +141,7 @@ func (g *Game) ExplodeSide1(index int) bool {
 	if g.State.Side1Ships[index] {
 		g.State.Side1Ships[index] = false
 		if allShipsDestroyed(g.State.Side1Ships) {
-			g.Status = Finished
-			g.WinnerUser = g.Side2User
+			g.Finish(g.Side2User, AllShipsDestroyed)
 		}
 		g.State.Side1RevealedShips[index] = false
 		return true
@@ -106,8 +154,7 @@ func (g *Game) ExplodeSide2(index int) bool {
 	if g.State.Side2Ships[index] {
 		g.State.Side2Ships[index] = false
 		if allShipsDestroyed(g.State.Side2Ships) {
-			g.Status = Finished
-			g.WinnerUser = g.Side1User
+			g.Finish(g.Side1User, AllShipsDestroyed)
 		}
 		g.State.Side2RevealedShips[index] = false
 		return true
@@ -115,6 +162,89 @@ func (g *Game) ExplodeSide2(index int) bool {
 	return false
 }
 
+func (g *Game) Finish(winner *primitive.ObjectID, reason EndReason) {
+	now := time.Now()
+	g.Status = Finished
+	g.WinnerUser = winner
+	g.EndReason = reason
+	g.EndDate = &now
+}
+
+// SkipTurn passes the turn of a side that did not move in time to the other side.
+func (g *Game) SkipTurn(now time.Time) {
+	if g.Turn == 1 {
+		g.Side1Missed++
+		g.Turn = 2
+	} else {
+		g.Side2Missed++
+		g.Turn = 1
+	}
+	g.LastMoveTime = now
+}
+
+// MissedTurns returns the turns that a side missed in a row.
+func (g *Game) MissedTurns(userId primitive.ObjectID) int {
+	if g.Side1User != nil && *g.Side1User == userId {
+		return g.Side1Missed
+	}
+	return g.Side2Missed
+}
+
+// ResetMissedTurns is called when a side moves, only turns missed in a row count.
+func (g *Game) ResetMissedTurns(userId primitive.ObjectID) {
+	if g.Side1User != nil && *g.Side1User == userId {
+		g.Side1Missed = 0
+	} else {
+		g.Side2Missed = 0
+	}
+}
+
+// PasscodeLocked reports whether the passcode is guessed wrong maxFailures times, the game cannot be joined anymore.
+// Zero maxFailures never locks.
+func (g *Game) PasscodeLocked(maxFailures int) bool {
+	return g.PasscodeHash != "" && maxFailures > 0 && g.PasscodeFails >= maxFailures
+}
+
+// Pause freezes the turn timer of a started game until Resume.
+func (g *Game) Pause(now time.Time, deadline time.Time) {
+	g.Status = Paused
+	g.PausedAt = &now
+	g.PauseDeadline = &deadline
+	g.PauseBy = nil
+	g.ResumeBy = nil
+}
+
+// Resume starts the game again, the time of the pause is added to LastMoveTime so the side that has the turn keeps
+// the time it had left.
+func (g *Game) Resume(now time.Time) {
+	if g.PausedAt != nil {
+		g.LastMoveTime = g.LastMoveTime.Add(now.Sub(*g.PausedAt))
+	}
+	g.Status = Start
+	g.PausedAt = nil
+	g.PauseDeadline = nil
+	g.ResumeBy = nil
+}
+
+// Cancel ends a game that has not started, it has no winner.
+func (g *Game) Cancel(reason EndReason) {
+	now := time.Now()
+	g.Status = Cancelled
+	g.EndReason = reason
+	g.EndDate = &now
+}
+
+// LoserUser returns the side that is not the winner, nil if game has no winner.
+func (g *Game) LoserUser() *primitive.ObjectID {
+	if g.WinnerUser == nil || g.Side1User == nil || g.Side2User == nil {
+		return nil
+	}
+	if *g.WinnerUser == *g.Side1User {
+		return g.Side2User
+	}
+	return g.Side1User
+}
+
 func allShipsDestroyed(ships map[int]bool) bool {
 	for _, b := range ships {
 		if b {
@@ -13,6 +13,11 @@ const (
 	EmptyExplosion                      = "empty_explosion"
 	ChangeTurn                          = "change_turn"
 	Reveal                              = "reveal"
+	StatusChanged                       = "status_changed"
+	PauseRequested                      = "pause_requested"
+	PauseAccepted                       = "pause_accepted"
+	ResumeRequested                     = "resume_requested"
+	Resumed                             = "resumed"
 )
 
 type GameEventType string
@@ -30,4 +35,6 @@ type GameEvent struct {
 	Time                  time.Time           `bson:"time,omitempty"`
 	UserId                *primitive.ObjectID `bson:"user_id,omitempty"`
 	GameId                primitive.ObjectID  `bson:"game_id,omitempty"`
+	FromStatus            GameStatus          `bson:"from_status,omitempty"`
+	ToStatus              GameStatus          `bson:"to_status,omitempty"`
 }
@@ -0,0 +1,55 @@
+package model
+
+type GameAction string
+
+const (
+	JoinAction        GameAction = "join"
+	InviteAction      GameAction = "invite"
+	CancelAction      GameAction = "cancel"
+	SubmitShipsAction GameAction = "submit_ships"
+	ChangeTurnAction  GameAction = "change_turn"
+	MoveShipAction    GameAction = "move_ship"
+	RevealAction      GameAction = "reveal"
+	ExplodeAction     GameAction = "explode"
+	PauseAction       GameAction = "pause"
+	AcceptPauseAction GameAction = "accept_pause"
+	ResumeAction      GameAction = "resume"
+	ChatAction        GameAction = "chat"
+)
+
+// gameTransitions declares the statuses a game can move to from each status, statuses without an entry are final.
+// Abandonment is not a status on purpose, an abandoned game is Finished with the Abandoned end reason like timeouts
+// and admin finishes, so everything that handles finished games (ratings, stats, webhooks) handles it too.
+var gameTransitions = map[GameStatus][]GameStatus{
+	Init:   {Joined, Cancelled},
+	Joined: {Start, Cancelled},
+	Start:  {Finished, Paused},
+	Paused: {Start, Finished},
+}
+
+// gameActions declares the actions users can perform on a game in each status.
+var gameActions = map[GameStatus][]GameAction{
+	Init:     {JoinAction, InviteAction, CancelAction},
+	Joined:   {SubmitShipsAction, CancelAction, ChatAction},
+	Start:    {ChangeTurnAction, MoveShipAction, RevealAction, ExplodeAction, PauseAction, AcceptPauseAction, ChatAction},
+	Paused:   {ResumeAction, ChatAction},
+	Finished: {ChatAction},
+}
+
+func CanTransition(from GameStatus, to GameStatus) bool {
+	for _, s := range gameTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+func IsActionAllowed(status GameStatus, action GameAction) bool {
+	for _, a := range gameActions[status] {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
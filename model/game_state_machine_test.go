@@ -0,0 +1,79 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from GameStatus
+		to   GameStatus
+		want bool
+	}{
+		{Init, Joined, true},
+		{Init, Cancelled, true},
+		{Init, Start, false},
+		{Init, Finished, false},
+		{Joined, Start, true},
+		{Joined, Cancelled, true},
+		{Joined, Init, false},
+		{Joined, Paused, false},
+		{Start, Finished, true},
+		{Start, Paused, true},
+		{Start, Cancelled, false},
+		{Start, Joined, false},
+		{Paused, Start, true},
+		{Paused, Finished, true},
+		{Paused, Cancelled, false},
+		{Finished, Start, false},
+		{Finished, Paused, false},
+		{Cancelled, Init, false},
+		{Cancelled, Joined, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s to %s", tt.from, tt.to), func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsActionAllowed(t *testing.T) {
+	tests := []struct {
+		status GameStatus
+		action GameAction
+		want   bool
+	}{
+		{Init, JoinAction, true},
+		{Init, InviteAction, true},
+		{Init, CancelAction, true},
+		{Init, SubmitShipsAction, false},
+		{Init, ChatAction, false},
+		{Joined, SubmitShipsAction, true},
+		{Joined, CancelAction, true},
+		{Joined, JoinAction, false},
+		{Joined, ExplodeAction, false},
+		{Start, ExplodeAction, true},
+		{Start, PauseAction, true},
+		{Start, AcceptPauseAction, true},
+		{Start, ResumeAction, false},
+		{Start, CancelAction, false},
+		{Paused, ResumeAction, true},
+		{Paused, ExplodeAction, false},
+		{Paused, PauseAction, false},
+		{Paused, ChatAction, true},
+		{Finished, ChatAction, true},
+		{Finished, ExplodeAction, false},
+		{Cancelled, ChatAction, false},
+		{Cancelled, JoinAction, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s in %s", tt.action, tt.status), func(t *testing.T) {
+			if got := IsActionAllowed(tt.status, tt.action); got != tt.want {
+				t.Errorf("IsActionAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type Invite struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	Code       string             `bson:"code"`
+	GameId     primitive.ObjectID `bson:"game_id"`
+	CreatorId  primitive.ObjectID `bson:"creator_id"`
+	Revoked    bool               `bson:"revoked"`
+	CreateDate time.Time          `bson:"create_date"`
+	ExpireDate time.Time          `bson:"expire_date"`
+}
+
+func (r *Invite) IsExpired() bool {
+	return r.ExpireDate.Before(time.Now())
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type LeaderboardType string
+
+const (
+	AllTimeBoard LeaderboardType = "all_time"
+	SeasonBoard  LeaderboardType = "season"
+	WeeklyBoard  LeaderboardType = "weekly"
+)
+
+const AllTimePeriod = "all"
+
+type LeaderboardEntry struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty"`
+	Board        LeaderboardType    `bson:"board"`
+	Period       string             `bson:"period"`
+	UserId       primitive.ObjectID `bson:"user_id"`
+	Wins         int                `bson:"wins"`
+	Losses       int                `bson:"losses"`
+	LastGameDate time.Time          `bson:"last_game_date"`
+}
+
+type SeasonArchive struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty"`
+	SeasonId    string             `bson:"season_id"`
+	Name        string             `bson:"name"`
+	Start       time.Time          `bson:"start"`
+	End         time.Time          `bson:"end"`
+	Top         []LeaderboardEntry `bson:"top"`
+	ArchiveDate time.Time          `bson:"archive_date"`
+}
+
+// WeekPeriod is the ISO week of t, e.g. 2020-W42.
+func WeekPeriod(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
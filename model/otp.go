@@ -0,0 +1,20 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type Otp struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	Mobile     string             `bson:"mobile"`
+	CodeHash   string             `bson:"code_hash"`
+	Attempts   int                `bson:"attempts"`
+	Verified   bool               `bson:"verified"`
+	CreateDate time.Time          `bson:"create_date"`
+	ExpireDate time.Time          `bson:"expire_date"`
+}
+
+func (r *Otp) IsExpired() bool {
+	return r.ExpireDate.Before(time.Now())
+}
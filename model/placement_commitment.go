@@ -0,0 +1,36 @@
+package model
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PlacementCommitment binds a side to its initial ship placement in a commit-reveal game. Commitment is shown to both
+// sides from the start, Salt and Ships are shown when the game is finished so the commitment can be checked.
+type PlacementCommitment struct {
+	Commitment string `bson:"commitment"`
+	Salt       string `bson:"salt"`
+	Ships      []int  `bson:"ships"`
+}
+
+// PlacementCommitmentOf returns the hex sha256 of the ship indexes sorted ascending and joined by commas, followed by
+// a colon and the salt, e.g. "3,14,27,...:salt".
+func PlacementCommitmentOf(ships []int, salt string) string {
+	sorted := append([]int(nil), ships...)
+	sort.Ints(sorted)
+	indexes := make([]string, len(sorted))
+	for i, ship := range sorted {
+		indexes[i] = strconv.Itoa(ship)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(indexes, ",") + ":" + salt))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r PlacementCommitment) Verify() bool {
+	expected := PlacementCommitmentOf(r.Ships, r.Salt)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(r.Commitment))) == 1
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestPlacementCommitmentOf(t *testing.T) {
+	sum := sha256.Sum256([]byte("3,14,27:salt-of-the-side"))
+	want := hex.EncodeToString(sum[:])
+	if got := PlacementCommitmentOf([]int{27, 3, 14}, "salt-of-the-side"); got != want {
+		t.Errorf("PlacementCommitmentOf() = %s, want %s", got, want)
+	}
+}
+
+func TestPlacementCommitmentVerify(t *testing.T) {
+	ships := []int{0, 5, 12, 23, 34, 45, 56, 67, 78, 99}
+	salt := "0123456789abcdef"
+	commitment := PlacementCommitmentOf(ships, salt)
+	tests := []struct {
+		name      string
+		placement PlacementCommitment
+		want      bool
+	}{
+		{"matching", PlacementCommitment{Commitment: commitment, Salt: salt, Ships: ships}, true},
+		{"upper case commitment", PlacementCommitment{Commitment: strings.ToUpper(commitment), Salt: salt, Ships: ships}, true},
+		{"ships in other order", PlacementCommitment{Commitment: commitment, Salt: salt,
+			Ships: []int{99, 78, 67, 56, 45, 34, 23, 12, 5, 0}}, true},
+		{"other ship", PlacementCommitment{Commitment: commitment, Salt: salt,
+			Ships: []int{1, 5, 12, 23, 34, 45, 56, 67, 78, 99}}, false},
+		{"missing ship", PlacementCommitment{Commitment: commitment, Salt: salt, Ships: ships[1:]}, false},
+		{"other salt", PlacementCommitment{Commitment: commitment, Salt: salt + "x", Ships: ships}, false},
+		{"empty commitment", PlacementCommitment{Salt: salt, Ships: ships}, false},
+		{"truncated commitment", PlacementCommitment{Commitment: commitment[:63], Salt: salt, Ships: ships}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.placement.Verify(); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type RatingHistory struct {
+	Id             primitive.ObjectID `bson:"_id,omitempty"`
+	UserId         primitive.ObjectID `bson:"user_id"`
+	GameId         primitive.ObjectID `bson:"game_id"`
+	OpponentUserId primitive.ObjectID `bson:"opponent_user_id"`
+	OldRating      int                `bson:"old_rating"`
+	NewRating      int                `bson:"new_rating"`
+	Won            bool               `bson:"won"`
+	EndReason      EndReason          `bson:"end_reason"`
+	Time           time.Time          `bson:"time"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// ServerSettingId is the id of the single server setting document.
+const ServerSettingId = "server"
+
+// ServerSetting keeps the modes set by admins in the db, so they survive restarts and are shared by all instances.
+type ServerSetting struct {
+	Id                 string    `bson:"_id"`
+	Maintenance        bool      `bson:"maintenance"`
+	MaintenanceMessage string    `bson:"maintenance_message,omitempty"`
+	UpdateDate         time.Time `bson:"update_date"`
+}
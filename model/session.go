@@ -0,0 +1,14 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type Session struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	UserId     primitive.ObjectID `bson:"user_id"`
+	TokenHash  string             `bson:"token_hash"`
+	CreateDate time.Time          `bson:"create_date"`
+	ExpireDate time.Time          `bson:"expire_date"`
+}
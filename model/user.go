@@ -3,14 +3,41 @@ package model
 import (
 	"battleship/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
 )
 
 type User struct {
-	Id     primitive.ObjectID `bson:"_id,omitempty"`
-	Name   *string            `bson:"name,omitempty"`
-	Mobile *string            `bson:"mobile,omitempty"`
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	Name       *string            `bson:"name,omitempty"`
+	Mobile     *string            `bson:"mobile,omitempty"`
+	Rating     int                `bson:"rating,omitempty"`
+	RatedGames int                `bson:"rated_games,omitempty"`
+	Ban        *UserBan           `bson:"ban,omitempty"`
+}
+
+// UserBan keeps a user from logging in and starting games, a ban without Until is permanent.
+type UserBan struct {
+	Reason string     `bson:"reason"`
+	Date   time.Time  `bson:"date"`
+	Until  *time.Time `bson:"until,omitempty"`
+}
+
+func (r *User) IsBanned(now time.Time) bool {
+	return r.Ban != nil && (r.Ban.Until == nil || now.Before(*r.Ban.Until))
+}
+
+// GetRating returns initial for users that have not played a ranked game yet.
+func (r *User) GetRating(initial int) int {
+	if r.RatedGames == 0 && r.Rating == 0 {
+		return initial
+	}
+	return r.Rating
+}
+
+func (r *User) IsProvisional(provisionalGames int) bool {
+	return r.RatedGames < provisionalGames
 }
 
 func (r *User) GetMaskedUserId() string {
-	return utils.MaskId(r.Id.Hex())
+	return utils.EncodeId(r.Id.Hex())
 }
@@ -0,0 +1,14 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// UserBlock means UserId never gets paired with BlockedUserId, in either direction.
+type UserBlock struct {
+	Id            primitive.ObjectID `bson:"_id,omitempty"`
+	UserId        primitive.ObjectID `bson:"user_id"`
+	BlockedUserId primitive.ObjectID `bson:"blocked_user_id"`
+	CreateDate    time.Time          `bson:"create_date"`
+}
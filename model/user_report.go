@@ -0,0 +1,31 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type ReportStatus string
+
+const (
+	ReportPending   ReportStatus = "pending"
+	ReportConfirmed ReportStatus = "confirmed"
+	ReportDismissed ReportStatus = "dismissed"
+)
+
+// UserReport is a report of a user about the opponent of a game. ChatLog is a copy of the chat of the game when the
+// report is made, so it is kept as it was seen. A report is pending until an admin reviews it.
+type UserReport struct {
+	Id             primitive.ObjectID `bson:"_id,omitempty"`
+	ReporterId     primitive.ObjectID `bson:"reporter_id"`
+	ReportedUserId primitive.ObjectID `bson:"reported_user_id"`
+	GameId         primitive.ObjectID `bson:"game_id"`
+	Category       string             `bson:"category"`
+	Comment        string             `bson:"comment,omitempty"`
+	ChatLog        []ChatMessage      `bson:"chat_log,omitempty"`
+	Status         ReportStatus       `bson:"status"`
+	CreateDate     time.Time          `bson:"create_date"`
+	ReviewNote     string             `bson:"review_note,omitempty"`
+	ReviewedBy     string             `bson:"reviewed_by,omitempty"`
+	ReviewDate     *time.Time         `bson:"review_date,omitempty"`
+}
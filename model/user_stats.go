@@ -0,0 +1,83 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type UserStats struct {
+	Id                   primitive.ObjectID `bson:"_id,omitempty"`
+	UserId               primitive.ObjectID `bson:"user_id"`
+	GamesPlayed          int                `bson:"games_played"`
+	Wins                 int                `bson:"wins"`
+	Losses               int                `bson:"losses"`
+	NoResults            int                `bson:"no_results"` //finished without a winner
+	WinsByEndReason      map[string]int     `bson:"wins_by_end_reason"`
+	LossesByEndReason    map[string]int     `bson:"losses_by_end_reason"`
+	TotalGameDurationSec int64              `bson:"total_game_duration_sec"`
+	Hits                 int                `bson:"hits"`   //explosions on a ship
+	Misses               int                `bson:"misses"` //empty explosions
+	Reveals              int                `bson:"reveals"`
+	RevealedShips        int                `bson:"revealed_ships"` //ships found by reveals
+	ShipMoves            int                `bson:"ship_moves"`
+	CurrentWinStreak     int                `bson:"current_win_streak"`
+	LongestWinStreak     int                `bson:"longest_win_streak"`
+	LastGameDate         time.Time          `bson:"last_game_date"`
+}
+
+type GameResult string
+
+const (
+	WinResult  GameResult = "win"
+	LossResult GameResult = "loss"
+	// NoResult is a game that is finished without a winner, e.g. by an admin.
+	NoResult GameResult = "no_result"
+)
+
+// UserGameStats is what one finished game adds to the stats of a user.
+type UserGameStats struct {
+	UserId        primitive.ObjectID
+	Result        GameResult
+	EndReason     EndReason
+	DurationSec   int64
+	EndDate       time.Time
+	Hits          int
+	Misses        int
+	Reveals       int
+	RevealedShips int
+	ShipMoves     int
+}
+
+// NewUserGameStats returns what the result and the events of one finished game add to the stats of the user.
+func NewUserGameStats(game Game, userId primitive.ObjectID, userEvents []GameEvent) UserGameStats {
+	r := UserGameStats{UserId: userId, EndReason: game.EndReason}
+	switch {
+	case game.WinnerUser == nil:
+		r.Result = NoResult
+	case *game.WinnerUser == userId:
+		r.Result = WinResult
+	default:
+		r.Result = LossResult
+	}
+
+	r.EndDate = time.Now()
+	if game.EndDate != nil {
+		r.EndDate = *game.EndDate
+	}
+	r.DurationSec = int64(r.EndDate.Sub(game.CreateDate).Seconds())
+
+	for _, e := range userEvents {
+		switch e.Type {
+		case Explosion:
+			r.Hits++
+		case EmptyExplosion:
+			r.Misses++
+		case Reveal:
+			r.Reveals++
+			r.RevealedShips += len(e.DiscoverEnemyShips)
+		case MoveShip:
+			r.ShipMoves++
+		}
+	}
+	return r
+}
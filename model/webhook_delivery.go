@@ -0,0 +1,44 @@
+package model
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type WebhookEvent string
+
+const (
+	GameCreatedWebhook   WebhookEvent = "game.created"
+	GameJoinedWebhook    WebhookEvent = "game.joined"
+	GameStartedWebhook   WebhookEvent = "game.started"
+	GameFinishedWebhook  WebhookEvent = "game.finished"
+	GameCancelledWebhook WebhookEvent = "game.cancelled"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	DeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is a payload for one endpoint. A pending delivery is attempted when NextAttemptTime is passed, the
+// secret is not kept here and is read from the endpoint config by Endpoint name. An attempt in progress holds the
+// delivery until LeaseUntil, it is cleared when the attempt is saved.
+type WebhookDelivery struct {
+	Id              primitive.ObjectID    `bson:"_id,omitempty"`
+	Event           WebhookEvent          `bson:"event"`
+	GameId          primitive.ObjectID    `bson:"game_id"`
+	Endpoint        string                `bson:"endpoint"`
+	Url             string                `bson:"url"`
+	Payload         string                `bson:"payload"`
+	Status          WebhookDeliveryStatus `bson:"status"`
+	Attempts        int                   `bson:"attempts"`
+	ResponseCode    int                   `bson:"response_code,omitempty"`
+	LastError       string                `bson:"last_error,omitempty"`
+	CreateDate      time.Time             `bson:"create_date"`
+	LastAttemptTime *time.Time            `bson:"last_attempt_time,omitempty"`
+	NextAttemptTime time.Time             `bson:"next_attempt_time"`
+	LeaseUntil      *time.Time            `bson:"lease_until"`
+}
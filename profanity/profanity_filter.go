@@ -0,0 +1,75 @@
+package profanity
+
+import (
+	"battleship/config"
+	"github.com/rs/zerolog/log"
+	"regexp"
+	"strings"
+)
+
+const (
+	NoFilter       = "none"
+	WordListFilter = "word_list"
+)
+
+type Filter interface {
+	// Clean returns text with profane words masked and whether anything is masked.
+	Clean(text string) (string, bool)
+}
+
+// NewFilter returns the filter selected by chat.profanity_filter config. Other filters, e.g. an external moderation
+// service, can be added here as new implementations.
+func NewFilter() Filter {
+	switch config.C.Chat.ProfanityFilter {
+	case WordListFilter:
+		return NewWordListProfanityFilter(config.C.Chat.BannedWords)
+	case NoFilter, "":
+		return NoProfanityFilter{}
+	default:
+		log.Warn().Str("filter", config.C.Chat.ProfanityFilter).Msg("unknown profanity filter, no filter is used")
+		return NoProfanityFilter{}
+	}
+}
+
+//////////////
+
+type NoProfanityFilter struct {
+}
+
+func (r NoProfanityFilter) Clean(text string) (string, bool) {
+	return text, false
+}
+
+//////////////
+
+// WordListProfanityFilter masks whole words of the list, case insensitive.
+type WordListProfanityFilter struct {
+	pattern *regexp.Regexp
+}
+
+func NewWordListProfanityFilter(words []string) WordListProfanityFilter {
+	var quoted []string
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			quoted = append(quoted, regexp.QuoteMeta(w))
+		}
+	}
+	if len(quoted) == 0 {
+		return WordListProfanityFilter{}
+	}
+	return WordListProfanityFilter{
+		pattern: regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`),
+	}
+}
+
+func (r WordListProfanityFilter) Clean(text string) (string, bool) {
+	if r.pattern == nil {
+		return text, false
+	}
+	filtered := false
+	cleaned := r.pattern.ReplaceAllStringFunc(text, func(word string) string {
+		filtered = true
+		return strings.Repeat("*", len([]rune(word)))
+	})
+	return cleaned, filtered
+}
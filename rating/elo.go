@@ -0,0 +1,19 @@
+package rating
+
+import "math"
+
+const (
+	Win  = 1.0
+	Loss = 0.0
+)
+
+// Expected is the Elo expected score of a player rated ra against a player rated rb.
+func Expected(ra int, rb int) float64 {
+	return 1 / (1 + math.Pow(10, float64(rb-ra)/400))
+}
+
+// Delta is the rating change of a player rated ra after scoring score against a player rated rb. factor scales the
+// change, e.g. for games that did not end normally.
+func Delta(ra int, rb int, score float64, k int, factor float64) int {
+	return int(math.Round(float64(k) * (score - Expected(ra, rb)) * factor))
+}
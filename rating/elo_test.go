@@ -0,0 +1,42 @@
+package rating
+
+import "testing"
+
+func TestDelta(t *testing.T) {
+	tests := []struct {
+		name   string
+		ra     int
+		rb     int
+		score  float64
+		k      int
+		factor float64
+		want   int
+	}{
+		{"equal ratings win", 1500, 1500, Win, 20, 1, 10},
+		{"equal ratings loss", 1500, 1500, Loss, 20, 1, -10},
+		{"favourite wins", 1600, 1400, Win, 20, 1, 5},
+		{"underdog loses", 1400, 1600, Loss, 20, 1, -5},
+		{"underdog wins", 1400, 1600, Win, 20, 1, 15},
+		{"favourite loses", 1600, 1400, Loss, 20, 1, -15},
+		{"provisional k factor", 1500, 1500, Win, 40, 1, 20},
+		{"scaled by factor", 1500, 1500, Win, 20, 0.5, 5},
+		{"zero factor", 1600, 1400, Loss, 20, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Delta(tt.ra, tt.rb, tt.score, tt.k, tt.factor); got != tt.want {
+				t.Errorf("Delta() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeltaIsZeroSum(t *testing.T) {
+	for _, ratings := range [][2]int{{1500, 1500}, {1600, 1400}, {1200, 2000}, {1510, 1490}} {
+		winner := Delta(ratings[0], ratings[1], Win, 20, 1)
+		loser := Delta(ratings[1], ratings[0], Loss, 20, 1)
+		if winner+loser != 0 {
+			t.Errorf("ratings %v: winner delta %d and loser delta %d do not add up to 0", ratings, winner, loser)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"battleship/tracing"
+	"context"
+	"errors"
+	"github.com/rs/zerolog/log"
+	"sync"
+	"time"
+)
+
+var (
+	mux     sync.Mutex
+	quit    = make(chan struct{})
+	jobs    = map[string]*jobState{}
+	running sync.WaitGroup
+)
+
+type jobState struct {
+	interval time.Duration
+	lastBeat time.Time
+	running  bool
+	stopped  bool
+}
+
+// JobStatus is the state of a scheduled job. LastBeat is updated when the job goroutine wakes up and when a run ends,
+// so it gets old if the goroutine is gone or a run is stuck.
+type JobStatus struct {
+	Name     string
+	Interval time.Duration
+	LastBeat time.Time
+	Running  bool
+	Stopped  bool
+}
+
+// Every runs job periodically in its own goroutine until Stop is called. Each run has its own trace. A panic in job is
+// logged and the next run goes on as scheduled.
+func Every(name string, interval time.Duration, job func(ctx context.Context)) {
+	if interval <= 0 {
+		log.Warn().Str("job", name).Msg("job interval is not positive, job is not scheduled")
+		return
+	}
+	beat(name, interval, false)
+	running.Add(1)
+	go func() {
+		defer running.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		log.Info().Str("job", name).Str("interval", interval.String()).Msg("job is scheduled")
+		for {
+			select {
+			case <-ticker.C:
+				// a tick and quit may be ready together, a run is not started after Stop
+				if isStopping() {
+					continue
+				}
+				beat(name, interval, true)
+				run(name, job)
+				beat(name, interval, false)
+			case <-quit:
+				stopped(name)
+				log.Info().Str("job", name).Msg("job is stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops scheduling runs and waits for the runs in progress to end, so they do not lose the resources they use,
+// e.g. the mongo client. It returns an error when ctx is done before the runs end.
+func Stop(ctx context.Context) error {
+	mux.Lock()
+	select {
+	case <-quit:
+	default:
+		close(quit)
+	}
+	mux.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.New("jobs are still running")
+	}
+}
+
+func isStopping() bool {
+	select {
+	case <-quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// Jobs returns the status of the scheduled jobs.
+func Jobs() []JobStatus {
+	mux.Lock()
+	defer mux.Unlock()
+	statuses := make([]JobStatus, 0, len(jobs))
+	for name, j := range jobs {
+		statuses = append(statuses, JobStatus{
+			Name:     name,
+			Interval: j.interval,
+			LastBeat: j.lastBeat,
+			Running:  j.running,
+			Stopped:  j.stopped,
+		})
+	}
+	return statuses
+}
+
+func beat(name string, interval time.Duration, running bool) {
+	mux.Lock()
+	defer mux.Unlock()
+	jobs[name] = &jobState{
+		interval: interval,
+		lastBeat: time.Now(),
+		running:  running,
+	}
+}
+
+func stopped(name string) {
+	mux.Lock()
+	defer mux.Unlock()
+	if j, ok := jobs[name]; ok {
+		j.stopped = true
+	}
+}
+
+func run(name string, job func(ctx context.Context)) {
+	ctx, span := tracing.Start(context.Background(), "job."+name)
+	defer span.End()
+	defer func() {
+		if rec := recover(); rec != nil {
+			tracing.Log(ctx).Error().Str("job", name).Interface("panic", rec).Msg("job panicked")
+		}
+	}()
+	job(ctx)
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"battleship/cache"
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/events/outgoing_events"
+	"battleship/model"
+	"battleship/tracing"
+	"battleship/utils"
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strconv"
+	"time"
+)
+
+type AdminService interface {
+	GetMaintenance(ctx context.Context) (response dto.MaintenanceResponse, err error)
+	SetMaintenance(ctx context.Context, request dto.SetMaintenanceRequest) (response dto.MaintenanceResponse, err error)
+	Broadcast(ctx context.Context, request dto.BroadcastRequest) (response dto.BroadcastResponse, err error)
+	SyncServerSetting(ctx context.Context)
+	RedeliverWebhook(ctx context.Context, request dto.RedeliverWebhookRequest) (response dto.RedeliverWebhookResponse, err error)
+	GetLiveGames(ctx context.Context, request dto.GetLiveGamesRequest) (response dto.GetLiveGamesResponse, err error)
+	GetGame(ctx context.Context, request dto.AdminGameRequest) (response dto.AdminGameResponse, err error)
+	FinishGame(ctx context.Context, request dto.FinishGameRequest) (response dto.AdminGameResponse, err error)
+	CancelGame(ctx context.Context, request dto.CancelGameAdminRequest) (response dto.AdminGameResponse, err error)
+	BanUser(ctx context.Context, request dto.BanUserRequest) (response dto.UserBanResponse, err error)
+	UnbanUser(ctx context.Context, request dto.UnbanUserRequest) (response dto.UserBanResponse, err error)
+	GetAudit(ctx context.Context, request dto.GetAuditRequest) (response dto.GetAuditResponse, err error)
+	GetReports(ctx context.Context, request dto.GetReportsRequest) (response dto.GetReportsResponse, err error)
+	ReviewReport(ctx context.Context, request dto.ReviewReportRequest) (response dto.ReviewReportResponse, err error)
+	GetCheatFlags(ctx context.Context, request dto.GetCheatFlagsRequest) (response dto.GetCheatFlagsResponse, err error)
+	ReviewCheatFlag(ctx context.Context, request dto.ReviewCheatFlagRequest) (response dto.ReviewCheatFlagResponse, err error)
+}
+
+type AdminServiceImpl struct {
+	serverSettingDao     dao.ServerSettingDao
+	adminAuditDao        dao.AdminAuditDao
+	gameDao              dao.GameDao
+	gameEventDao         dao.GameEventDao
+	userDao              dao.UserDao
+	gameService          GameService
+	webhookService       WebhookService
+	reportService        ReportService
+	antiCheatService     AntiCheatService
+	outgoingEventHandler outgoing_events.OutgoingEventHandler
+}
+
+func NewAdminServiceImpl(serverSettingDao dao.ServerSettingDao, adminAuditDao dao.AdminAuditDao, gameDao dao.GameDao,
+	gameEventDao dao.GameEventDao, userDao dao.UserDao, gameService GameService, webhookService WebhookService,
+	reportService ReportService, antiCheatService AntiCheatService,
+	outgoingEventHandler outgoing_events.OutgoingEventHandler) AdminServiceImpl {
+	return AdminServiceImpl{
+		serverSettingDao:     serverSettingDao,
+		adminAuditDao:        adminAuditDao,
+		gameDao:              gameDao,
+		gameEventDao:         gameEventDao,
+		userDao:              userDao,
+		gameService:          gameService,
+		webhookService:       webhookService,
+		reportService:        reportService,
+		antiCheatService:     antiCheatService,
+		outgoingEventHandler: outgoingEventHandler,
+	}
+}
+
+func (r AdminServiceImpl) GetMaintenance(ctx context.Context) (response dto.MaintenanceResponse, err error) {
+	response = dto.MaintenanceResponse{}
+	setting, err := r.serverSettingDao.Get(ctx)
+	if err != nil {
+		return response, err
+	}
+	response.Ok = true
+	response.FromSetting(setting)
+	return response, nil
+}
+
+// SetMaintenance saves the mode in the db and applies it on this instance, other instances follow it on their next
+// sync.
+func (r AdminServiceImpl) SetMaintenance(ctx context.Context, request dto.SetMaintenanceRequest) (response dto.MaintenanceResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.SetMaintenance")
+	defer tracing.End(span, &err)
+	response = dto.MaintenanceResponse{}
+	defer func() {
+		r.audit(ctx, request.AdminActor, model.AdminAudit{
+			Action: model.SetMaintenanceAdminAction,
+			Reason: request.Message,
+			Data:   map[string]string{"enabled": strconv.FormatBool(request.Enabled)},
+		}, err)
+	}()
+	setting, err := r.serverSettingDao.Get(ctx)
+	if err != nil {
+		return response, err
+	}
+	setting.Maintenance = request.Enabled
+	setting.MaintenanceMessage = request.Message
+	setting.UpdateDate = time.Now()
+	err = r.serverSettingDao.Save(ctx, setting)
+	if err != nil {
+		return response, err
+	}
+	cache.SetMaintenance(setting.Maintenance, setting.MaintenanceMessage)
+	tracing.Log(ctx).Info().Bool("maintenance", setting.Maintenance).Msg("maintenance mode is changed")
+
+	response.Ok = true
+	response.FromSetting(setting)
+	return response, nil
+}
+
+func (r AdminServiceImpl) Broadcast(ctx context.Context, request dto.BroadcastRequest) (response dto.BroadcastResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.Broadcast")
+	defer tracing.End(span, &err)
+	response = dto.BroadcastResponse{}
+	defer func() {
+		r.audit(ctx, request.AdminActor, model.AdminAudit{
+			Action: model.BroadcastAdminAction,
+			Reason: request.Message,
+			Data:   map[string]string{"sockets": strconv.Itoa(response.Sockets)},
+		}, err)
+	}()
+	sockets, err := r.outgoingEventHandler.ServerNotice(ctx, dto.ServerNoticeEvent{
+		Message: request.Message,
+		Time:    time.Now(),
+	})
+	if err != nil {
+		return response, err
+	}
+	tracing.Log(ctx).Info().Int("sockets", sockets).Msg("server notice is broadcast")
+
+	response.Ok = true
+	response.Sockets = sockets
+	return response, nil
+}
+
+// SyncServerSetting loads the server setting of the db into the cache of this instance.
+func (r AdminServiceImpl) SyncServerSetting(ctx context.Context) {
+	setting, err := r.serverSettingDao.Get(ctx)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot load server setting")
+		return
+	}
+	if setting.Maintenance != cache.IsMaintenance() {
+		tracing.Log(ctx).Info().Bool("maintenance", setting.Maintenance).Msg("maintenance mode is loaded")
+	}
+	cache.SetMaintenance(setting.Maintenance, setting.MaintenanceMessage)
+}
+
+func (r AdminServiceImpl) RedeliverWebhook(ctx context.Context, request dto.RedeliverWebhookRequest) (response dto.RedeliverWebhookResponse, err error) {
+	defer func() {
+		r.audit(ctx, request.AdminActor, model.AdminAudit{
+			Action:   model.RedeliverWebhookAdminAction,
+			TargetId: request.DeliveryId,
+		}, err)
+	}()
+	return r.webhookService.Redeliver(ctx, request)
+}
+
+// GetLiveGames lists games in progress. The connection status is of the sockets on this server.
+func (r AdminServiceImpl) GetLiveGames(ctx context.Context, request dto.GetLiveGamesRequest) (response dto.GetLiveGamesResponse, err error) {
+	response = dto.GetLiveGamesResponse{Games: []dto.LiveGameDto{}}
+	games, err := r.gameDao.FindLive(ctx, int64(request.Limit))
+	if err != nil {
+		return response, err
+	}
+	for _, game := range games {
+		liveGame := dto.LiveGameDto{}
+		liveGame.FromGame(game)
+		gameId := game.Id.Hex()
+		liveGame.Side1Connected, liveGame.Side2Connected = cache.GameConnection(gameId)
+		liveGame.Side1DisconnectedSince = disconnectedSince(gameId, game.Side1User)
+		liveGame.Side2DisconnectedSince = disconnectedSince(gameId, game.Side2User)
+		response.Games = append(response.Games, liveGame)
+	}
+	response.Ok = true
+	return response, nil
+}
+
+func (r AdminServiceImpl) GetGame(ctx context.Context, request dto.AdminGameRequest) (response dto.AdminGameResponse, err error) {
+	response = dto.AdminGameResponse{}
+	response.Game, err = r.gameDao.GetOne(ctx, request.GameId)
+	if err != nil {
+		return response, err
+	}
+	response.Events, err = r.gameEventDao.FindMany(ctx, request.GameId)
+	if err != nil {
+		return response, err
+	}
+	response.Ok = true
+	return response, nil
+}
+
+func (r AdminServiceImpl) FinishGame(ctx context.Context, request dto.FinishGameRequest) (response dto.AdminGameResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.FinishGame")
+	defer tracing.End(span, &err)
+	defer func() {
+		audit := model.AdminAudit{
+			Action:   model.FinishGameAdminAction,
+			TargetId: utils.EncodeId(request.GameId),
+			Reason:   request.Reason,
+		}
+		if request.WinnerUserId != "" {
+			audit.Data = map[string]string{"winner_user_id": utils.EncodeId(request.WinnerUserId)}
+		}
+		r.audit(ctx, request.AdminActor, audit, err)
+	}()
+	_, err = r.gameService.FinishByAdmin(ctx, request.GameId, request.WinnerUserId)
+	if err != nil {
+		return response, err
+	}
+	return r.GetGame(ctx, dto.AdminGameRequest{GameId: request.GameId})
+}
+
+func (r AdminServiceImpl) CancelGame(ctx context.Context, request dto.CancelGameAdminRequest) (response dto.AdminGameResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.CancelGame")
+	defer tracing.End(span, &err)
+	defer func() {
+		r.audit(ctx, request.AdminActor, model.AdminAudit{
+			Action:   model.CancelGameAdminAction,
+			TargetId: utils.EncodeId(request.GameId),
+			Reason:   request.Reason,
+		}, err)
+	}()
+	_, err = r.gameService.CancelByAdmin(ctx, request.GameId)
+	if err != nil {
+		return response, err
+	}
+	return r.GetGame(ctx, dto.AdminGameRequest{GameId: request.GameId})
+}
+
+func (r AdminServiceImpl) BanUser(ctx context.Context, request dto.BanUserRequest) (response dto.UserBanResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.BanUser")
+	defer tracing.End(span, &err)
+	response = dto.UserBanResponse{}
+	defer func() {
+		r.audit(ctx, request.AdminActor, model.AdminAudit{
+			Action:   model.BanUserAdminAction,
+			TargetId: utils.EncodeId(request.UserId),
+			Reason:   request.Reason,
+			Data:     map[string]string{"duration_hours": strconv.Itoa(request.DurationHours)},
+		}, err)
+	}()
+	user, err := r.userDao.GetOne(ctx, request.UserId)
+	if err != nil {
+		return response, err
+	}
+	now := time.Now()
+	user.Ban = &model.UserBan{
+		Reason: request.Reason,
+		Date:   now,
+	}
+	if request.DurationHours > 0 {
+		until := now.Add(time.Duration(request.DurationHours) * time.Hour)
+		user.Ban.Until = &until
+	}
+	err = r.userDao.SetBan(ctx, user.Id, user.Ban)
+	if err != nil {
+		return response, err
+	}
+	tracing.Log(ctx).Info().Str("user_id", request.UserId).Msg("user is banned")
+
+	response.Ok = true
+	response.FromUser(user)
+	return response, nil
+}
+
+func (r AdminServiceImpl) UnbanUser(ctx context.Context, request dto.UnbanUserRequest) (response dto.UserBanResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.UnbanUser")
+	defer tracing.End(span, &err)
+	response = dto.UserBanResponse{}
+	defer func() {
+		r.audit(ctx, request.AdminActor, model.AdminAudit{
+			Action:   model.UnbanUserAdminAction,
+			TargetId: utils.EncodeId(request.UserId),
+			Reason:   request.Reason,
+		}, err)
+	}()
+	user, err := r.userDao.GetOne(ctx, request.UserId)
+	if err != nil {
+		return response, err
+	}
+	user.Ban = nil
+	err = r.userDao.SetBan(ctx, user.Id, nil)
+	if err != nil {
+		return response, err
+	}
+	tracing.Log(ctx).Info().Str("user_id", request.UserId).Msg("user is unbanned")
+
+	response.Ok = true
+	response.FromUser(user)
+	return response, nil
+}
+
+func (r AdminServiceImpl) GetAudit(ctx context.Context, request dto.GetAuditRequest) (response dto.GetAuditResponse, err error) {
+	response = dto.GetAuditResponse{Audits: []dto.AdminAuditDto{}}
+	audits, err := r.adminAuditDao.FindLast(ctx, request.TargetId, int64(request.Limit))
+	if err != nil {
+		return response, err
+	}
+	for _, audit := range audits {
+		auditDto := dto.AdminAuditDto{}
+		auditDto.FromAudit(audit)
+		response.Audits = append(response.Audits, auditDto)
+	}
+	response.Ok = true
+	return response, nil
+}
+
+func (r AdminServiceImpl) GetReports(ctx context.Context, request dto.GetReportsRequest) (response dto.GetReportsResponse, err error) {
+	return r.reportService.GetReports(ctx, request)
+}
+
+func (r AdminServiceImpl) ReviewReport(ctx context.Context, request dto.ReviewReportRequest) (response dto.ReviewReportResponse, err error) {
+	defer func() {
+		r.audit(ctx, request.AdminActor, model.AdminAudit{
+			Action:   model.ReviewReportAdminAction,
+			TargetId: request.ReportId,
+			Reason:   request.Note,
+			Data: map[string]string{
+				"confirmed":  strconv.FormatBool(request.Confirmed),
+				"restricted": strconv.FormatBool(response.Restricted),
+			},
+		}, err)
+	}()
+	return r.reportService.Review(ctx, request)
+}
+
+func (r AdminServiceImpl) GetCheatFlags(ctx context.Context, request dto.GetCheatFlagsRequest) (response dto.GetCheatFlagsResponse, err error) {
+	return r.antiCheatService.GetFlags(ctx, request)
+}
+
+func (r AdminServiceImpl) ReviewCheatFlag(ctx context.Context, request dto.ReviewCheatFlagRequest) (response dto.ReviewCheatFlagResponse, err error) {
+	defer func() {
+		r.audit(ctx, request.AdminActor, model.AdminAudit{
+			Action:   model.ReviewCheatFlagAdminAction,
+			TargetId: request.FlagId,
+			Reason:   request.Note,
+			Data: map[string]string{
+				"confirmed": strconv.FormatBool(request.Confirmed),
+				"kind":      string(response.Flag.Kind),
+				"user_id":   response.Flag.UserId,
+			},
+		}, err)
+	}()
+	return r.antiCheatService.Review(ctx, request)
+}
+
+// audit records an admin action with its result, failed actions are recorded too. An audit that cannot be saved is
+// logged and does not fail the action.
+func (r AdminServiceImpl) audit(ctx context.Context, actor dto.AdminActor, audit model.AdminAudit, err error) {
+	audit.Actor = actor.Actor
+	audit.RemoteIp = actor.RemoteIp
+	audit.Time = time.Now()
+	if err != nil {
+		audit.Error = err.Error()
+	}
+	if _, auditErr := r.adminAuditDao.Insert(ctx, audit); auditErr != nil {
+		tracing.Log(ctx).Error().Err(auditErr).Str("action", string(audit.Action)).Msg("cannot save admin audit")
+	}
+}
+
+func disconnectedSince(gameId string, userId *primitive.ObjectID) *time.Time {
+	if userId == nil {
+		return nil
+	}
+	presence, ok := cache.GetPresence(gameId, userId.Hex())
+	if !ok || presence.Connected {
+		return nil
+	}
+	return &presence.DisconnectedSince
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"battleship/config"
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/error_codes"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math"
+	"sort"
+	"time"
+)
+
+// pairHistoryLimit bounds the games of a pair that are loaded for the collusion check.
+const pairHistoryLimit = 500
+
+type AntiCheatService interface {
+	AnalyseFinishedGames(ctx context.Context)
+	GetFlags(ctx context.Context, request dto.GetCheatFlagsRequest) (response dto.GetCheatFlagsResponse, err error)
+	Review(ctx context.Context, request dto.ReviewCheatFlagRequest) (response dto.ReviewCheatFlagResponse, err error)
+}
+
+type AntiCheatServiceImpl struct {
+	gameDao      dao.GameDao
+	gameEventDao dao.GameEventDao
+	cheatFlagDao dao.CheatFlagDao
+}
+
+func NewAntiCheatServiceImpl(gameDao dao.GameDao, gameEventDao dao.GameEventDao, cheatFlagDao dao.CheatFlagDao) AntiCheatServiceImpl {
+	return AntiCheatServiceImpl{
+		gameDao:      gameDao,
+		gameEventDao: gameEventDao,
+		cheatFlagDao: cheatFlagDao,
+	}
+}
+
+// AnalyseFinishedGames checks a batch of finished games that are not checked yet and flags suspicious sides for
+// review. A game is marked as checked before it is analysed, so it is checked once even with many instances.
+func (r AntiCheatServiceImpl) AnalyseFinishedGames(ctx context.Context) {
+	ctx, span := tracing.Start(ctx, "AntiCheatService.AnalyseFinishedGames")
+	defer span.End()
+	games, err := r.gameDao.FindCheatUnchecked(ctx, int64(config.C.AntiCheat.BatchSize))
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot find games to check")
+		return
+	}
+	checked, flagged := 0, 0
+	for _, game := range games {
+		claimed, err := r.gameDao.ClaimCheatCheck(ctx, game.Id)
+		if err != nil || !claimed {
+			continue
+		}
+		checked++
+		flagged += r.analyseGame(ctx, game)
+	}
+	tracing.Log(ctx).Debug().Int("checked", checked).Int("flagged", flagged).Msg("finished games are checked")
+}
+
+// analyseGame adds the evidences of the game to the flags and returns how many it has added.
+func (r AntiCheatServiceImpl) analyseGame(ctx context.Context, game model.Game) (flagged int) {
+	if game.Side1User == nil || game.Side2User == nil {
+		return 0
+	}
+	cfg := config.C.AntiCheat
+	events, err := r.gameEventDao.FindMany(ctx, game.Id.Hex())
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("game_id", game.Id.Hex()).Msg("cannot get events of game")
+		return 0
+	}
+	// events are from the newest
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+
+	now := time.Now()
+	for _, userId := range []primitive.ObjectID{*game.Side1User, *game.Side2User} {
+		stats := analyseShots(events, userId, game.BoardSize)
+		if stats.Shots >= cfg.MinShots && stats.Shots > 0 &&
+			float64(stats.FastShots)/float64(stats.Shots) >= cfg.FastShotRatio {
+			if r.flag(ctx, userId, nil, model.FastReactionCheat, model.CheatEvidence{
+				GameId:           game.Id,
+				Time:             now,
+				Shots:            stats.Shots,
+				FastShots:        stats.FastShots,
+				MinReactionMs:    stats.MinReactionMs,
+				MedianReactionMs: stats.MedianReactionMs,
+			}) {
+				flagged++
+			}
+		}
+		if stats.BlindShots >= cfg.MinShots && stats.ZScore >= cfg.HitRateZScore {
+			if r.flag(ctx, userId, nil, model.HitRateCheat, model.CheatEvidence{
+				GameId:       game.Id,
+				Time:         now,
+				BlindShots:   stats.BlindShots,
+				BlindHits:    stats.BlindHits,
+				ExpectedHits: stats.ExpectedHits,
+				ZScore:       stats.ZScore,
+			}) {
+				flagged++
+			}
+		}
+	}
+	if r.checkPairing(ctx, game, now) {
+		flagged++
+	}
+	return flagged
+}
+
+// checkPairing flags the winner of the game when its games with the loser in the window look like wins are farmed,
+// most are won and many of the losses were given up right away.
+func (r AntiCheatServiceImpl) checkPairing(ctx context.Context, game model.Game, now time.Time) bool {
+	cfg := config.C.AntiCheat
+	winner, loser := game.WinnerUser, game.LoserUser()
+	if winner == nil || loser == nil {
+		return false
+	}
+	since := now.Add(-time.Duration(cfg.PairWindowDays) * 24 * time.Hour)
+	status := model.Finished
+	games, err := r.gameDao.FindByUser(ctx, dao.GameFilter{
+		UserId:     *winner,
+		OpponentId: loser,
+		Status:     &status,
+		From:       &since,
+	}, pairHistoryLimit)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("game_id", game.Id.Hex()).Msg("cannot get games of pair")
+		return false
+	}
+
+	wins, quickLosses := 0, 0
+	for _, g := range games {
+		if g.WinnerUser == nil || *g.WinnerUser != *winner {
+			continue
+		}
+		wins++
+		if (g.EndReason == model.Abandoned || g.EndReason == model.Timeout) && g.MoveCount <= cfg.QuickLossMaxMoves {
+			quickLosses++
+		}
+	}
+	if len(games) < cfg.PairMinGames || len(games) == 0 ||
+		float64(wins)/float64(len(games)) < cfg.PairWinRatio || quickLosses < cfg.PairMinQuickLosses {
+		return false
+	}
+	return r.flag(ctx, *winner, loser, model.CollusionCheat, model.CheatEvidence{
+		GameId:      game.Id,
+		Time:        now,
+		Games:       len(games),
+		Wins:        wins,
+		QuickLosses: quickLosses,
+	})
+}
+
+func (r AntiCheatServiceImpl) flag(ctx context.Context, userId primitive.ObjectID, otherUserId *primitive.ObjectID,
+	kind model.CheatKind, evidence model.CheatEvidence) bool {
+	err := r.cheatFlagDao.AddEvidence(ctx, userId, otherUserId, kind, evidence, config.C.AntiCheat.MaxEvidence)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("user_id", userId.Hex()).Str("kind", string(kind)).Msg("cannot flag user")
+		return false
+	}
+	tracing.Log(ctx).Info().Str("user_id", userId.Hex()).Str("kind", string(kind)).
+		Str("game_id", evidence.GameId.Hex()).Msg("user is flagged")
+	return true
+}
+
+type shotStats struct {
+	Shots            int
+	FastShots        int
+	MinReactionMs    int64
+	MedianReactionMs int64
+	BlindShots       int
+	BlindHits        int
+	ExpectedHits     float64
+	ZScore           float64
+}
+
+// analyseShots replays the shots of the user in events, which are from the oldest. The reaction time of a shot is
+// from the previous event of the game. A blind shot is at a cell the user has neither shot nor revealed, it hits with
+// the chance of the hidden ship cells among the unknown cells. Ship moves of the other side are not replayed, they
+// are rare enough not to change the chance much.
+func analyseShots(events []model.GameEvent, userId primitive.ObjectID, boardSize int) (stats shotStats) {
+	cfg := config.C.AntiCheat
+	if boardSize == 0 {
+		boardSize = model.DefaultBoardSize
+	}
+	hiddenShips := 0
+	for _, e := range events {
+		if e.Type == model.InitialShipsLocations && e.UserId != nil && *e.UserId != userId {
+			hiddenShips = len(e.InitialShipsLocations)
+		}
+	}
+	unknownCells := boardSize * boardSize
+	known := map[int]bool{}
+	knownShips := map[int]bool{}
+	var reactions []int64
+	var variance float64
+
+	for i, e := range events {
+		if e.UserId == nil || *e.UserId != userId {
+			continue
+		}
+		var index *int
+		hit := false
+		switch e.Type {
+		case model.Explosion:
+			index, hit = e.Explosion, true
+		case model.EmptyExplosion:
+			index = e.EmptyExplosion
+		case model.Reveal:
+		default:
+			continue
+		}
+
+		if e.Type == model.Reveal {
+			for _, cell := range e.DiscoverEnemy {
+				if !known[cell] {
+					known[cell] = true
+					unknownCells--
+				}
+			}
+			for _, cell := range e.DiscoverEnemyShips {
+				if !knownShips[cell] {
+					knownShips[cell] = true
+					hiddenShips--
+				}
+			}
+			continue
+		}
+
+		stats.Shots++
+		if i > 0 {
+			reaction := e.Time.Sub(events[i-1].Time).Milliseconds()
+			reactions = append(reactions, reaction)
+			if reaction < int64(cfg.MinReactionMs) {
+				stats.FastShots++
+			}
+		}
+		if index == nil || known[*index] {
+			continue
+		}
+		if unknownCells > 0 && hiddenShips > 0 {
+			p := float64(hiddenShips) / float64(unknownCells)
+			stats.BlindShots++
+			stats.ExpectedHits += p
+			variance += p * (1 - p)
+			if hit {
+				stats.BlindHits++
+			}
+		}
+		known[*index] = true
+		unknownCells--
+		if hit {
+			knownShips[*index] = true
+			hiddenShips--
+		}
+	}
+
+	if len(reactions) > 0 {
+		sort.Slice(reactions, func(i, j int) bool { return reactions[i] < reactions[j] })
+		stats.MinReactionMs = reactions[0]
+		stats.MedianReactionMs = reactions[len(reactions)/2]
+	}
+	if variance > 0 {
+		stats.ZScore = (float64(stats.BlindHits) - stats.ExpectedHits) / math.Sqrt(variance)
+	}
+	return stats
+}
+
+func (r AntiCheatServiceImpl) GetFlags(ctx context.Context, request dto.GetCheatFlagsRequest) (response dto.GetCheatFlagsResponse, err error) {
+	response = dto.GetCheatFlagsResponse{Flags: []dto.CheatFlagDto{}}
+	flags, err := r.cheatFlagDao.FindByStatus(ctx, model.ReportStatus(request.Status), int64(request.Limit))
+	if err != nil {
+		return response, err
+	}
+	for _, flag := range flags {
+		flagDto := dto.CheatFlagDto{}
+		flagDto.FromFlag(flag)
+		response.Flags = append(response.Flags, flagDto)
+	}
+	response.Ok = true
+	return response, nil
+}
+
+// Review confirms or dismisses a pending flag, evidence of later games goes to a new flag.
+func (r AntiCheatServiceImpl) Review(ctx context.Context, request dto.ReviewCheatFlagRequest) (response dto.ReviewCheatFlagResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AntiCheatService.Review")
+	defer tracing.End(span, &err)
+	response = dto.ReviewCheatFlagResponse{}
+	flag, err := r.cheatFlagDao.GetOne(ctx, request.FlagId)
+	if err != nil {
+		return response, err
+	}
+	if flag.Status != model.ReportPending {
+		return response, dto.BadRequest2("flag is already reviewed", error_codes.CheatFlagReviewed)
+	}
+
+	now := time.Now()
+	flag.Status = model.ReportDismissed
+	if request.Confirmed {
+		flag.Status = model.ReportConfirmed
+	}
+	flag.ReviewNote = request.Note
+	flag.ReviewedBy = request.Actor
+	flag.ReviewDate = &now
+	updated, err := r.cheatFlagDao.UpdateIfPending(ctx, flag)
+	if err != nil {
+		return response, err
+	}
+	if !updated {
+		return response, dto.BadRequest2("flag is already reviewed", error_codes.CheatFlagReviewed)
+	}
+	response.Ok = true
+	response.Flag.FromFlag(flag)
+	return response, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"battleship/config"
+	"battleship/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAnalyseShots(t *testing.T) {
+	config.C.AntiCheat.MinReactionMs = 300
+	user, other := primitive.NewObjectID(), primitive.NewObjectID()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+	cell := func(i int) *int { return &i }
+	ships := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+
+	game := []model.GameEvent{
+		{Type: model.InitialShipsLocations, UserId: &other, InitialShipsLocations: ships, Time: at(0)},
+		{Type: model.InitialShipsLocations, UserId: &user, InitialShipsLocations: ships, Time: at(0)},
+		{Type: model.Explosion, UserId: &user, Explosion: cell(0), Time: at(1000)},
+		{Type: model.EmptyExplosion, UserId: &other, EmptyExplosion: cell(50), Time: at(1500)},
+		{Type: model.EmptyExplosion, UserId: &user, EmptyExplosion: cell(50), Time: at(1600)},
+		{Type: model.Reveal, UserId: &user, DiscoverEnemy: []int{1, 2, 60}, DiscoverEnemyShips: []int{1, 2}, Time: at(1700)},
+		{Type: model.Explosion, UserId: &user, Explosion: cell(1), Time: at(1800)},
+		{Type: model.EmptyExplosion, UserId: &user, EmptyExplosion: cell(70), Time: at(3800)},
+	}
+	// blind shots: a hit at 0 with 10 of 100 cells hidden ships, a miss at 50 with 9 of 99, the shot at 1 is revealed
+	// before, a miss at 70 with 7 of 95 after the reveal
+	chances := []float64{10.0 / 100, 9.0 / 99, 7.0 / 95}
+	expected, variance := 0.0, 0.0
+	for _, p := range chances {
+		expected += p
+		variance += p * (1 - p)
+	}
+
+	tests := []struct {
+		name      string
+		events    []model.GameEvent
+		userId    primitive.ObjectID
+		boardSize int
+		want      shotStats
+	}{
+		{"shots of the user", game, user, 10, shotStats{
+			Shots:            4,
+			FastShots:        2,
+			MinReactionMs:    100,
+			MedianReactionMs: 1000,
+			BlindShots:       3,
+			BlindHits:        1,
+			ExpectedHits:     expected,
+			ZScore:           (1 - expected) / math.Sqrt(variance),
+		}},
+		{"default board size", game, user, 0, shotStats{
+			Shots:            4,
+			FastShots:        2,
+			MinReactionMs:    100,
+			MedianReactionMs: 1000,
+			BlindShots:       3,
+			BlindHits:        1,
+			ExpectedHits:     expected,
+			ZScore:           (1 - expected) / math.Sqrt(variance),
+		}},
+		{"shots of the other user", game, other, 10, shotStats{
+			Shots:            1,
+			MinReactionMs:    500,
+			MedianReactionMs: 500,
+			BlindShots:       1,
+			ExpectedHits:     0.1,
+			ZScore:           -0.1 / math.Sqrt(0.09),
+		}},
+		{"user without shots", game, primitive.NewObjectID(), 10, shotStats{}},
+		{"no events", nil, user, 10, shotStats{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := analyseShots(tt.events, tt.userId, tt.boardSize)
+			if got.Shots != tt.want.Shots || got.FastShots != tt.want.FastShots ||
+				got.MinReactionMs != tt.want.MinReactionMs || got.MedianReactionMs != tt.want.MedianReactionMs ||
+				got.BlindShots != tt.want.BlindShots || got.BlindHits != tt.want.BlindHits ||
+				!almostEqual(got.ExpectedHits, tt.want.ExpectedHits) || !almostEqual(got.ZScore, tt.want.ZScore) {
+				t.Errorf("analyseShots() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func almostEqual(a float64, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
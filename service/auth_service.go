@@ -0,0 +1,237 @@
+package service
+
+import (
+	"battleship/config"
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/error_codes"
+	"battleship/model"
+	"battleship/sms"
+	"battleship/tracing"
+	"battleship/utils"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"math/big"
+	"time"
+)
+
+type AuthService interface {
+	RequestOtp(ctx context.Context, request dto.RequestOtpRequest) (response dto.RequestOtpResponse, err error)
+	VerifyOtp(ctx context.Context, request dto.VerifyOtpRequest) (response dto.VerifyOtpResponse, err error)
+}
+
+type AuthServiceImpl struct {
+	otpDao     dao.OtpDao
+	sessionDao dao.SessionDao
+	userDao    dao.UserDao
+	smsSender  sms.SmsSender
+}
+
+func NewAuthServiceImpl(otpDao dao.OtpDao, sessionDao dao.SessionDao, userDao dao.UserDao, smsSender sms.SmsSender) AuthServiceImpl {
+	return AuthServiceImpl{
+		otpDao:     otpDao,
+		sessionDao: sessionDao,
+		userDao:    userDao,
+		smsSender:  smsSender,
+	}
+}
+
+// RequestOtp sends a new code to the mobile. The otp is saved before the limits are checked, so requests in parallel
+// see each other and none of them gets around the limits.
+func (r AuthServiceImpl) RequestOtp(ctx context.Context, request dto.RequestOtpRequest) (response dto.RequestOtpResponse, err error) {
+	response = dto.RequestOtpResponse{}
+	now := time.Now()
+
+	code, err := generateOtpCode(config.C.Otp.Length)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot generate otp code")
+		return response, err
+	}
+
+	id, err := r.otpDao.Insert(ctx, model.Otp{
+		Mobile:     request.Mobile,
+		CodeHash:   hashOtp(request.Mobile, code),
+		CreateDate: now,
+		ExpireDate: now.Add(time.Duration(config.C.Otp.ExpireSec) * time.Second),
+	})
+	if err != nil {
+		return response, err
+	}
+
+	err = r.checkOtpLimits(ctx, request.Mobile, now)
+	if err != nil {
+		otpId, _ := primitive.ObjectIDFromHex(id)
+		if err := r.otpDao.Delete(ctx, otpId); err != nil {
+			tracing.Log(ctx).Error().Err(err).Str("mobile", request.Mobile).Msg("cannot delete rejected otp")
+		}
+		return response, err
+	}
+
+	err = r.smsSender.Send(request.Mobile, fmt.Sprintf("Your battleship login code is %s", code))
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("mobile", request.Mobile).Msg("cannot send otp sms")
+		return response, err
+	}
+
+	response.Ok = true
+	response.ExpireSec = config.C.Otp.ExpireSec
+	response.ResendIntervalSec = config.C.Otp.ResendIntervalSec
+	return response, nil
+}
+
+// checkOtpLimits checks the limits of the mobile, the otp that is just saved is counted too.
+func (r AuthServiceImpl) checkOtpLimits(ctx context.Context, mobile string, now time.Time) error {
+	count, err := r.otpDao.CountSince(ctx, mobile, now.Add(-time.Duration(config.C.Otp.ResendIntervalSec)*time.Second))
+	if err != nil {
+		return err
+	}
+	if count > 1 {
+		tracing.Log(ctx).Info().Str("mobile", mobile).Msg("otp is requested before resend interval")
+		return dto.TooManyRequests2("otp is already sent, try again later", error_codes.OtpRateLimited)
+	}
+
+	count, err = r.otpDao.CountSince(ctx, mobile, now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if count > int64(config.C.Otp.MaxPerHour) {
+		tracing.Log(ctx).Info().Str("mobile", mobile).Int64("count", count).Msg("otp hourly limit is reached")
+		return dto.TooManyRequests2("too many otp requests, try again later", error_codes.OtpRateLimited)
+	}
+	return nil
+}
+
+// VerifyOtp checks the code and creates a session for the user of the mobile, only the hash of the session token is
+// stored. The attempt is counted before the code is compared,
+// so parallel guesses cannot go over the attempts limit.
+func (r AuthServiceImpl) VerifyOtp(ctx context.Context, request dto.VerifyOtpRequest) (response dto.VerifyOtpResponse, err error) {
+	response = dto.VerifyOtpResponse{}
+
+	otp, err := r.otpDao.GetLast(ctx, request.Mobile)
+	if err != nil {
+		if isNotFound(err) {
+			return response, dto.BadRequest2("otp is not correct", error_codes.OtpInvalid)
+		}
+		return response, err
+	}
+
+	if otp.Verified || otp.IsExpired() {
+		tracing.Log(ctx).Info().Str("mobile", request.Mobile).Msg("otp is expired")
+		return response, dto.BadRequest2("otp is expired", error_codes.OtpExpired)
+	}
+
+	otp, err = r.otpDao.UseAttempt(ctx, otp.Id, config.C.Otp.MaxAttempts)
+	if err != nil {
+		if isNotFound(err) {
+			tracing.Log(ctx).Info().Str("mobile", request.Mobile).Msg("otp attempts limit is reached")
+			return response, dto.TooManyRequests2("too many attempts, request a new code", error_codes.OtpTooManyAttempts)
+		}
+		return response, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(otp.CodeHash), []byte(hashOtp(request.Mobile, request.Code))) != 1 {
+		return response, dto.BadRequest2("otp is not correct", error_codes.OtpInvalid)
+	}
+
+	verified, err := r.otpDao.MarkVerified(ctx, otp.Id)
+	if err != nil {
+		return response, err
+	}
+	if !verified {
+		tracing.Log(ctx).Info().Str("mobile", request.Mobile).Msg("otp is already used")
+		return response, dto.BadRequest2("otp is expired", error_codes.OtpExpired)
+	}
+
+	user, err := r.userDao.GetByMobile(ctx, request.Mobile)
+	if err != nil {
+		if !isNotFound(err) {
+			return response, err
+		}
+		mobile := request.Mobile
+		id, err := r.userDao.Insert(ctx, model.User{Mobile: &mobile})
+		if err != nil {
+			tracing.Log(ctx).Error().Err(err).Str("mobile", request.Mobile).Msg("cannot create user")
+			return response, err
+		}
+		user, err = r.userDao.GetOne(ctx, id)
+		if err != nil {
+			return response, err
+		}
+		response.NewUser = true
+	}
+	if err = checkNotBanned(user); err != nil {
+		tracing.Log(ctx).Info().Str("user_id", user.Id.Hex()).Msg("banned user cannot log in")
+		return response, err
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot generate session token")
+		return response, err
+	}
+	now := time.Now()
+	session := model.Session{
+		UserId:     user.Id,
+		TokenHash:  hashToken(token),
+		CreateDate: now,
+		ExpireDate: now.Add(time.Duration(config.C.Session.ExpireHours) * time.Hour),
+	}
+	_, err = r.sessionDao.Insert(ctx, session)
+	if err != nil {
+		return response, err
+	}
+
+	response.Ok = true
+	response.Token = token
+	response.ExpireDate = session.ExpireDate
+	response.UserId = utils.EncodeId(user.Id.Hex())
+	return response, nil
+}
+
+func generateOtpCode(length int) (string, error) {
+	if length <= 0 {
+		length = 6
+	}
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+	return string(code), nil
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashOtp returns the hmac of the code with the server key, a hash of a short code alone can be reversed by trying all
+// codes.
+func hashOtp(mobile string, code string) string {
+	mac := hmac.New(sha256.New, []byte(config.C.Otp.HashKey))
+	mac.Write([]byte(mobile + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, mongo.ErrNoDocuments)
+}
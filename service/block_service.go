@@ -0,0 +1,113 @@
+package service
+
+import (
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/tracing"
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BlockService interface {
+	Block(ctx context.Context, request dto.BlockUserRequest) (response dto.GetBlocksResponse, err error)
+	Unblock(ctx context.Context, request dto.BlockUserRequest) (response dto.GetBlocksResponse, err error)
+	GetBlocks(ctx context.Context, userId string) (response dto.GetBlocksResponse, err error)
+	IsBlocked(ctx context.Context, userId1 primitive.ObjectID, userId2 primitive.ObjectID) (blocked bool, err error)
+	BlockedWith(ctx context.Context, userId string) (userIds map[string]bool, err error)
+}
+
+type BlockServiceImpl struct {
+	userBlockDao dao.UserBlockDao
+	userDao      dao.UserDao
+}
+
+func NewBlockServiceImpl(userBlockDao dao.UserBlockDao, userDao dao.UserDao) BlockServiceImpl {
+	return BlockServiceImpl{
+		userBlockDao: userBlockDao,
+		userDao:      userDao,
+	}
+}
+
+func (r BlockServiceImpl) Block(ctx context.Context, request dto.BlockUserRequest) (response dto.GetBlocksResponse, err error) {
+	user, blocked, err := r.getUsers(ctx, request)
+	if err != nil {
+		return dto.GetBlocksResponse{}, err
+	}
+	err = r.userBlockDao.Block(ctx, user, blocked)
+	if err != nil {
+		return dto.GetBlocksResponse{}, err
+	}
+	tracing.Log(ctx).Info().Str("user_id", request.UserId).Str("blocked_user_id", request.BlockedUserId).Msg("user is blocked")
+	return r.GetBlocks(ctx, request.UserId)
+}
+
+func (r BlockServiceImpl) Unblock(ctx context.Context, request dto.BlockUserRequest) (response dto.GetBlocksResponse, err error) {
+	user, blocked, err := r.getUsers(ctx, request)
+	if err != nil {
+		return dto.GetBlocksResponse{}, err
+	}
+	err = r.userBlockDao.Unblock(ctx, user, blocked)
+	if err != nil {
+		return dto.GetBlocksResponse{}, err
+	}
+	return r.GetBlocks(ctx, request.UserId)
+}
+
+func (r BlockServiceImpl) GetBlocks(ctx context.Context, userId string) (response dto.GetBlocksResponse, err error) {
+	response = dto.GetBlocksResponse{Blocks: []dto.UserBlockDto{}}
+	user, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return response, dto.BadRequest1("user id is not correct")
+	}
+	blocks, err := r.userBlockDao.FindByUser(ctx, user)
+	if err != nil {
+		return response, err
+	}
+	for _, block := range blocks {
+		blockDto := dto.UserBlockDto{}
+		blockDto.FromBlock(block)
+		response.Blocks = append(response.Blocks, blockDto)
+	}
+	response.Ok = true
+	return response, nil
+}
+
+// IsBlocked checks whether either user has blocked the other.
+func (r BlockServiceImpl) IsBlocked(ctx context.Context, userId1 primitive.ObjectID, userId2 primitive.ObjectID) (blocked bool, err error) {
+	return r.userBlockDao.IsBlocked(ctx, userId1, userId2)
+}
+
+// BlockedWith returns the users that the user has blocked or that have blocked the user, keyed by hex id.
+func (r BlockServiceImpl) BlockedWith(ctx context.Context, userId string) (userIds map[string]bool, err error) {
+	userIds = map[string]bool{}
+	user, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		return userIds, dto.BadRequest1("user id is not correct")
+	}
+	blocks, err := r.userBlockDao.FindRelated(ctx, user)
+	if err != nil {
+		return userIds, err
+	}
+	for _, block := range blocks {
+		if block.UserId == user {
+			userIds[block.BlockedUserId.Hex()] = true
+		} else {
+			userIds[block.UserId.Hex()] = true
+		}
+	}
+	return userIds, nil
+}
+
+func (r BlockServiceImpl) getUsers(ctx context.Context, request dto.BlockUserRequest) (user primitive.ObjectID, blocked primitive.ObjectID, err error) {
+	u, err := r.userDao.GetOne(ctx, request.UserId)
+	if err != nil {
+		tracing.Log(ctx).Info().Str("userId", request.UserId).Err(err).Msg("cannot get user")
+		return user, blocked, err
+	}
+	b, err := r.userDao.GetOne(ctx, request.BlockedUserId)
+	if err != nil {
+		tracing.Log(ctx).Info().Str("userId", request.BlockedUserId).Err(err).Msg("cannot get blocked user")
+		return user, blocked, err
+	}
+	return u.Id, b.Id, nil
+}
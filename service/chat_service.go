@@ -0,0 +1,168 @@
+package service
+
+import (
+	"battleship/cache"
+	"battleship/config"
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/error_codes"
+	"battleship/events/outgoing_events"
+	"battleship/model"
+	"battleship/profanity"
+	"battleship/tracing"
+	"battleship/utils"
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type ChatService interface {
+	SendMessage(ctx context.Context, gameId string, userId string, request dto.ChatMessageEvent) error
+	SetMute(ctx context.Context, gameId string, userId string, request dto.ChatMuteEvent) error
+	GetChat(ctx context.Context, game model.Game, userId primitive.ObjectID) (chat dto.ChatDto, err error)
+	SendHistory(ctx context.Context, game model.Game, userId primitive.ObjectID) error
+}
+
+type ChatServiceImpl struct {
+	chatDao         dao.ChatDao
+	gameDao         dao.GameDao
+	stateMachine    GameStateMachine
+	eventHandler    outgoing_events.OutgoingEventHandler
+	profanityFilter profanity.Filter
+}
+
+func NewChatServiceImpl(chatDao dao.ChatDao, gameDao dao.GameDao, stateMachine GameStateMachine,
+	eventHandler outgoing_events.OutgoingEventHandler, profanityFilter profanity.Filter) ChatServiceImpl {
+	return ChatServiceImpl{
+		chatDao:         chatDao,
+		gameDao:         gameDao,
+		stateMachine:    stateMachine,
+		eventHandler:    eventHandler,
+		profanityFilter: profanityFilter,
+	}
+}
+
+// SendMessage saves a message of a side and sends it to the sender and to the opponent, unless the opponent has
+// muted the sender.
+func (r ChatServiceImpl) SendMessage(ctx context.Context, gameId string, userId string, request dto.ChatMessageEvent) error {
+	err := request.Validate()
+	if err != nil {
+		return err
+	}
+	game, user, opponent, err := r.getGameOfChat(ctx, gameId, userId)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	window := time.Duration(config.C.Chat.RateLimitWindowSec) * time.Second
+	if !cache.AllowChat(userId, now, config.C.Chat.RateLimitCount, window) {
+		tracing.Log(ctx).Info().Str("user_id", userId).Str("game_id", gameId).Msg("chat is rate limited")
+		return dto.TooManyRequests2("too many chat messages", error_codes.ChatRateLimited)
+	}
+
+	message := model.ChatMessage{
+		GameId: game.Id,
+		UserId: user,
+		Emote:  request.Emote,
+		Time:   now,
+	}
+	if request.Text != "" {
+		message.Text, message.Filtered = r.profanityFilter.Clean(request.Text)
+	}
+	id, err := r.chatDao.Insert(ctx, message)
+	if err != nil {
+		return err
+	}
+	message.Id, _ = primitive.ObjectIDFromHex(id)
+
+	recipients := []primitive.ObjectID{user}
+	muted, err := r.chatDao.IsMuted(ctx, game.Id, opponent)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("game_id", gameId).Msg("cannot get chat mute of opponent")
+	} else if !muted {
+		recipients = append(recipients, opponent)
+	}
+	for _, recipient := range recipients {
+		err = r.eventHandler.ChatMessage(ctx, utils.EncodeId(game.Id.Hex()), utils.EncodeId(recipient.Hex()),
+			chatMessageDto(message))
+		if err != nil {
+			tracing.Log(ctx).Error().Err(err).Str("game_id", gameId).Msg("cannot send chat message")
+		}
+	}
+	return nil
+}
+
+// SetMute mutes or unmutes the opponent for the user. Messages of a muted opponent are not sent to the user and are
+// hidden from the history.
+func (r ChatServiceImpl) SetMute(ctx context.Context, gameId string, userId string, request dto.ChatMuteEvent) error {
+	game, user, _, err := r.getGameOfChat(ctx, gameId, userId)
+	if err != nil {
+		return err
+	}
+	err = r.chatDao.SetMute(ctx, game.Id, user, request.Muted)
+	if err != nil {
+		return err
+	}
+	return r.SendHistory(ctx, game, user)
+}
+
+func (r ChatServiceImpl) GetChat(ctx context.Context, game model.Game, userId primitive.ObjectID) (chat dto.ChatDto, err error) {
+	chat = dto.ChatDto{Messages: []dto.ChatMessageDto{}}
+	chat.Muted, err = r.chatDao.IsMuted(ctx, game.Id, userId)
+	if err != nil {
+		return chat, err
+	}
+	messages, err := r.chatDao.FindLast(ctx, game.Id, int64(config.C.Chat.HistorySize))
+	if err != nil {
+		return chat, err
+	}
+	for _, m := range messages {
+		if chat.Muted && m.UserId != userId {
+			continue
+		}
+		chat.Messages = append(chat.Messages, chatMessageDto(m))
+	}
+	return chat, nil
+}
+
+// SendHistory sends the chat history to the socket of the user, e.g. when the user reconnects.
+func (r ChatServiceImpl) SendHistory(ctx context.Context, game model.Game, userId primitive.ObjectID) error {
+	chat, err := r.GetChat(ctx, game, userId)
+	if err != nil {
+		return err
+	}
+	return r.eventHandler.ChatHistory(ctx, utils.EncodeId(userId.Hex()), dto.ChatHistoryEvent{
+		GameId:   utils.EncodeId(game.Id.Hex()),
+		Muted:    chat.Muted,
+		Messages: chat.Messages,
+	})
+}
+
+func (r ChatServiceImpl) getGameOfChat(ctx context.Context, gameId string, userId string) (game model.Game, user primitive.ObjectID, opponent primitive.ObjectID, err error) {
+	game, err = r.gameDao.GetOne(ctx, gameId)
+	if err != nil {
+		tracing.Log(ctx).Info().Str("gameId", gameId).Err(err).Msg("cannot get game")
+		return game, user, opponent, err
+	}
+	err = r.stateMachine.CheckAction(ctx, game, model.ChatAction)
+	if err != nil {
+		return game, user, opponent, err
+	}
+	if game.Side1User == nil || game.Side2User == nil {
+		return game, user, opponent, dto.BadRequest2("other side has not joined", error_codes.InvalidGameStatus)
+	}
+	switch userId {
+	case game.Side1User.Hex():
+		return game, *game.Side1User, *game.Side2User, nil
+	case game.Side2User.Hex():
+		return game, *game.Side2User, *game.Side1User, nil
+	}
+	tracing.Log(ctx).Error().Str("user_id", userId).Str("game_id", gameId).Msg("user does not belong to game")
+	return game, user, opponent, dto.Forbidden1("cannot perform the operation")
+}
+
+func chatMessageDto(message model.ChatMessage) dto.ChatMessageDto {
+	messageDto := dto.ChatMessageDto{}
+	messageDto.FromMessage(message)
+	return messageDto
+}
@@ -2,96 +2,135 @@ package service
 
 import (
 	"battleship/cache"
+	"battleship/config"
 	"battleship/db/dao"
 	"battleship/dto"
 	"battleship/error_codes"
 	"battleship/events/outgoing_events"
+	"battleship/metrics"
 	"battleship/model"
+	"battleship/tracing"
 	"battleship/utils"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/gorilla/websocket"
-	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"math/rand"
+	"sort"
+	"strings"
 	"time"
 )
 
 type GameService interface {
-	CreateGame(request dto.CreateGameRequest) (response dto.GetGameResponse, err error)
-	GetGame(request dto.GetGameRequest) (game dto.GetGameResponse, err error)
-	JoinGame(request dto.JoinGameRequest) (response dto.GetGameResponse, err error)
-	SubmitShipsLocations(request dto.SubmitShipsLocationsRequest) (response dto.SubmitShipsLocationsResponse, err error)
-	ChangeTurn(request dto.ChangeTurnRequest) (response dto.ChangeTurnResponse, err error)
-	MoveShip(request dto.MoveShipRequest) (response dto.MoveShipResponse, err error)
-	Reveal(request dto.RevealEnemyFieldsRequest) (response dto.RevealEnemyFieldsResponse, err error)
-	Explode(request dto.ExplodeRequest) (response dto.ExplodeResponse, err error)
-	SocketConnect(event dto.Event, socketConn *websocket.Conn) error
+	CreateGame(ctx context.Context, request dto.CreateGameRequest) (response dto.GetGameResponse, err error)
+	GetGame(ctx context.Context, request dto.GetGameRequest) (game dto.GetGameResponse, err error)
+	JoinGame(ctx context.Context, request dto.JoinGameRequest) (response dto.GetGameResponse, err error)
+	SubmitShipsLocations(ctx context.Context, request dto.SubmitShipsLocationsRequest) (response dto.SubmitShipsLocationsResponse, err error)
+	ChangeTurn(ctx context.Context, request dto.ChangeTurnRequest) (response dto.ChangeTurnResponse, err error)
+	MoveShip(ctx context.Context, request dto.MoveShipRequest) (response dto.MoveShipResponse, err error)
+	Reveal(ctx context.Context, request dto.RevealEnemyFieldsRequest) (response dto.RevealEnemyFieldsResponse, err error)
+	Explode(ctx context.Context, request dto.ExplodeRequest) (response dto.ExplodeResponse, err error)
+	SocketConnect(ctx context.Context, event dto.Event, socketConn *websocket.Conn) error
+	CreateMatchedGame(ctx context.Context, side1 MatchedSide, side2 MatchedSide, settings model.GameSettings) (game model.Game, err error)
+	GetUserGames(ctx context.Context, request dto.GetUserGamesRequest) (response dto.GetUserGamesResponse, err error)
+	CancelGame(ctx context.Context, request dto.CancelGameRequest) (response dto.GetGameResponse, err error)
+	ExpireGames(ctx context.Context)
+	RequestPause(ctx context.Context, request dto.PauseGameRequest) (response dto.PauseGameResponse, err error)
+	AcceptPause(ctx context.Context, request dto.AcceptPauseRequest) (response dto.PauseGameResponse, err error)
+	Resume(ctx context.Context, request dto.ResumeGameRequest) (response dto.PauseGameResponse, err error)
+	ResumeExpiredPauses(ctx context.Context)
+	FinishByAdmin(ctx context.Context, gameId string, winnerUserId string) (game model.Game, err error)
+	CancelByAdmin(ctx context.Context, gameId string) (game model.Game, err error)
 }
 
 type GameServiceImpl struct {
-	gameDao      dao.GameDao
-	userDao      dao.UserDao
-	gameEventDao dao.GameEventDao
-	eventHandler outgoing_events.OutgoingEventHandler
+	gameDao            dao.GameDao
+	userDao            dao.UserDao
+	gameEventDao       dao.GameEventDao
+	eventHandler       outgoing_events.OutgoingEventHandler
+	ratingService      RatingService
+	leaderboardService LeaderboardService
+	statisticsService  StatisticsService
+	lobbyService       LobbyService
+	inviteService      InviteService
+	stateMachine       GameStateMachine
+	chatService        ChatService
+	webhookService     WebhookService
+	blockService       BlockService
 }
 
 func NewGameServiceImpl(gameDao dao.GameDao, userDao dao.UserDao, gameEventDao dao.GameEventDao,
-	eventHandler outgoing_events.OutgoingEventHandler) GameServiceImpl {
+	eventHandler outgoing_events.OutgoingEventHandler, ratingService RatingService,
+	leaderboardService LeaderboardService, statisticsService StatisticsService, lobbyService LobbyService,
+	inviteService InviteService, stateMachine GameStateMachine, chatService ChatService,
+	webhookService WebhookService, blockService BlockService) GameServiceImpl {
 	return GameServiceImpl{
-		gameDao:      gameDao,
-		userDao:      userDao,
-		gameEventDao: gameEventDao,
-		eventHandler: eventHandler,
+		gameDao:            gameDao,
+		userDao:            userDao,
+		gameEventDao:       gameEventDao,
+		eventHandler:       eventHandler,
+		ratingService:      ratingService,
+		leaderboardService: leaderboardService,
+		statisticsService:  statisticsService,
+		lobbyService:       lobbyService,
+		inviteService:      inviteService,
+		stateMachine:       stateMachine,
+		chatService:        chatService,
+		webhookService:     webhookService,
+		blockService:       blockService,
 	}
 }
 
-func (r GameServiceImpl) CreateGame(request dto.CreateGameRequest) (response dto.GetGameResponse, err error) {
+func (r GameServiceImpl) CreateGame(ctx context.Context, request dto.CreateGameRequest) (response dto.GetGameResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.CreateGame")
+	defer tracing.End(span, &err)
 	response = dto.GetGameResponse{}
-	user, err := r.userDao.GetOne(request.UserId)
+	if err = checkAcceptingGames(); err != nil {
+		return response, err
+	}
+	user, err := r.userDao.GetOne(ctx, request.UserId)
 	if err != nil {
-		log.Info().Str("userId", request.UserId).Err(err).Msg("cannot insert user")
+		tracing.Log(ctx).Info().Str("userId", request.UserId).Err(err).Msg("cannot insert user")
 		return response, err
 	}
-
-	rand.Seed(time.Now().UnixNano())
-
-	fields := make(map[int]bool, 100)
-	for i := 0; i < 100; i++ {
-		fields[i] = true
+	if err = checkNotBanned(user); err != nil {
+		return response, err
 	}
 
-	game := model.Game{
-		Side1User:      &user.Id,
-		Side2User:      nil,
-		Status:         model.Init,
-		LastMoveTime:   time.Now(),
-		CreateDate:     time.Now(),
+	game, err := newGame(&user.Id, nil, model.GameSettings{
+		BoardSize:      model.DefaultBoardSize,
 		MoveTimeoutSec: request.MoveTimeout,
-		Turn:           int((rand.Uint32() % 2) + 1),
-		State: model.GameState{
-			Side1Ships:         map[int]bool{},
-			Side1Ground:        fields,
-			Side2Ships:         map[int]bool{},
-			Side2Ground:        fields,
-			Side2RevealedShips: map[int]bool{},
-			Side1RevealedShips: map[int]bool{},
-		},
-		WinnerUser: nil,
+		Ruleset:        model.ClassicRuleset,
+		Ranked:         request.Ranked,
+		Public:         request.Public,
+		CommitReveal:   request.CommitReveal,
+	})
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot create coin toss")
+		return response, err
+	}
+	game.Toss.Commit(1, request.TossCommitment)
+	if request.Passcode != "" {
+		game.PasscodeHash, err = hashPasscode(request.Passcode)
+		if err != nil {
+			tracing.Log(ctx).Error().Err(err).Msg("cannot hash passcode")
+			return response, err
+		}
 	}
 
-	gameId, err := r.gameDao.Insert(game)
+	gameId, err := r.gameDao.Insert(ctx, game)
 	if err != nil {
 		return response, err
 	}
 
 	gameObjectId, err := primitive.ObjectIDFromHex(gameId)
 	if err != nil {
-		log.Warn().Err(err).Msg("")
+		tracing.Log(ctx).Warn().Err(err).Msg("")
 		return response, err
 	}
 
-	_, err = r.gameEventDao.Insert(model.GameEvent{
+	_, err = r.gameEventDao.Insert(ctx, model.GameEvent{
 		Time:   time.Now(),
 		Type:   model.JoinGame,
 		GameId: gameObjectId,
@@ -99,14 +138,27 @@ func (r GameServiceImpl) CreateGame(request dto.CreateGameRequest) (response dto
 	})
 
 	if err != nil {
-		log.Warn().Err(err).Msg("")
+		tracing.Log(ctx).Warn().Err(err).Msg("")
 		return response, err
 	}
 
-	gm, err := r.gameDao.GetOne(gameId)
+	gm, err := r.gameDao.GetOne(ctx, gameId)
 	if err != nil {
 		return response, err
 	}
+	r.stateMachine.Created(ctx, gm, &user.Id)
+
+	r.lobbyService.GameAdded(ctx, gm)
+	r.webhookService.GameEvent(ctx, model.GameCreatedWebhook, gm)
+	metrics.GameCreated()
+
+	if !gm.Public {
+		invite, err := r.inviteService.NewInvite(ctx, gm)
+		if err != nil {
+			return response, err
+		}
+		response.Invite = inviteDto(invite)
+	}
 
 	response.Game = new(dto.GameDto)
 	response.Game.FromGame(gm, request.UserId)
@@ -114,94 +166,677 @@ func (r GameServiceImpl) CreateGame(request dto.CreateGameRequest) (response dto
 	return response, nil
 }
 
-func (r GameServiceImpl) GetGame(request dto.GetGameRequest) (gameResponse dto.GetGameResponse, err error) {
+// MatchedSide is a user of a matched game and its coin toss commitment.
+type MatchedSide struct {
+	UserId         string
+	TossCommitment string
+}
+
+func (r GameServiceImpl) CreateMatchedGame(ctx context.Context, side1 MatchedSide, side2 MatchedSide, settings model.GameSettings) (game model.Game, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.CreateMatchedGame")
+	defer tracing.End(span, &err)
+	side1User, err := r.userDao.GetOne(ctx, side1.UserId)
+	if err != nil {
+		tracing.Log(ctx).Info().Str("userId", side1.UserId).Err(err).Msg("cannot get user")
+		return game, err
+	}
+	side2User, err := r.userDao.GetOne(ctx, side2.UserId)
+	if err != nil {
+		tracing.Log(ctx).Info().Str("userId", side2.UserId).Err(err).Msg("cannot get user")
+		return game, err
+	}
+
+	game, err = newGame(&side1User.Id, &side2User.Id, settings)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot create coin toss")
+		return game, err
+	}
+	game.Toss.Commit(1, side1.TossCommitment)
+	game.Toss.Commit(2, side2.TossCommitment)
+	gameId, err := r.gameDao.Insert(ctx, game)
+	if err != nil {
+		return game, err
+	}
+
+	game, err = r.gameDao.GetOne(ctx, gameId)
+	if err != nil {
+		return game, err
+	}
+	r.stateMachine.Created(ctx, game, nil)
+
+	for _, userId := range []primitive.ObjectID{side1User.Id, side2User.Id} {
+		userId := userId
+		_, err = r.gameEventDao.Insert(ctx, model.GameEvent{
+			Time:   time.Now(),
+			Type:   model.JoinGame,
+			GameId: game.Id,
+			UserId: &userId,
+		})
+		if err != nil {
+			tracing.Log(ctx).Warn().Err(err).Str("game_id", gameId).Msg("cannot save join game event")
+			return game, err
+		}
+	}
+	r.webhookService.GameEvent(ctx, model.GameCreatedWebhook, game)
+	r.webhookService.GameEvent(ctx, model.GameJoinedWebhook, game)
+	metrics.GameCreated()
+	return game, nil
+}
+
+func (r GameServiceImpl) GetUserGames(ctx context.Context, request dto.GetUserGamesRequest) (response dto.GetUserGamesResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.GetUserGames")
+	defer tracing.End(span, &err)
+	response = dto.GetUserGamesResponse{Games: []dto.GameSummaryDto{}}
+	userId, err := primitive.ObjectIDFromHex(request.UserId)
+	if err != nil {
+		return response, dto.ParseError(err)
+	}
+
+	filter := dao.GameFilter{
+		UserId: userId,
+		Result: request.Result,
+		From:   request.FromDate,
+		To:     request.ToDate,
+	}
+	if request.Status != "" {
+		status := model.GameStatus(request.Status)
+		filter.Status = &status
+	}
+	if request.OpponentId != "" {
+		opponentId, err := primitive.ObjectIDFromHex(request.OpponentId)
+		if err != nil {
+			return response, dto.ParseError(err)
+		}
+		filter.OpponentId = &opponentId
+	}
+	if request.Cursor != "" {
+		before, err := primitive.ObjectIDFromHex(request.Cursor)
+		if err != nil {
+			return response, dto.ParseError(err)
+		}
+		filter.Before = &before
+	}
+
+	games, err := r.gameDao.FindByUser(ctx, filter, int64(request.Limit))
+	if err != nil {
+		tracing.Log(ctx).Info().Str("userId", request.UserId).Err(err).Msg("cannot get user games")
+		return response, err
+	}
+
+	var opponentIds []primitive.ObjectID
+	for _, g := range games {
+		if opponent := opponentOf(g, userId); opponent != nil {
+			opponentIds = append(opponentIds, *opponent)
+		}
+	}
+	opponents := map[primitive.ObjectID]model.User{}
+	if len(opponentIds) > 0 {
+		users, err := r.userDao.FindByIds(ctx, opponentIds)
+		if err != nil {
+			return response, err
+		}
+		for _, u := range users {
+			opponents[u.Id] = u
+		}
+	}
+
+	for _, g := range games {
+		summary := dto.GameSummaryDto{
+			GameId:     utils.EncodeId(g.Id.Hex()),
+			Status:     g.Status,
+			EndReason:  g.EndReason,
+			Ranked:     g.Ranked,
+			CreateDate: g.CreateDate,
+			EndDate:    g.EndDate,
+			MoveCount:  g.MoveCount,
+		}
+		if opponent := opponentOf(g, userId); opponent != nil {
+			summary.OpponentId = utils.EncodeId(opponent.Hex())
+			summary.OpponentName = opponents[*opponent].Name
+		}
+		if g.Status == model.Finished && g.WinnerUser != nil {
+			if *g.WinnerUser == userId {
+				summary.Result = dao.WinResult
+			} else {
+				summary.Result = dao.LossResult
+			}
+		}
+		if g.EndDate != nil {
+			summary.DurationSec = int64(g.EndDate.Sub(g.CreateDate).Seconds())
+		}
+		response.Games = append(response.Games, summary)
+	}
+
+	if len(games) == request.Limit {
+		response.NextCursor = utils.EncodeId(games[len(games)-1].Id.Hex())
+	}
+	response.Ok = true
+	return response, nil
+}
+
+func opponentOf(game model.Game, userId primitive.ObjectID) *primitive.ObjectID {
+	if game.Side1User != nil && *game.Side1User == userId {
+		return game.Side2User
+	}
+	return game.Side1User
+}
+
+// newGame returns a game without a turn, the coin toss picks it when both sides have submitted their ships. The sides
+// commit to their toss seeds after it.
+func newGame(side1User *primitive.ObjectID, side2User *primitive.ObjectID, settings model.GameSettings) (model.Game, error) {
+	toss, err := model.NewCoinToss()
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	side1Fields := make(map[int]bool, 100)
+	side2Fields := make(map[int]bool, 100)
+	for i := 0; i < 100; i++ {
+		side1Fields[i] = true
+		side2Fields[i] = true
+	}
+
+	status := model.Init
+	if side2User != nil {
+		status = model.Joined
+	}
+
+	return model.Game{
+		Side1User:      side1User,
+		Side2User:      side2User,
+		Status:         status,
+		LastMoveTime:   time.Now(),
+		CreateDate:     time.Now(),
+		MoveTimeoutSec: settings.MoveTimeoutSec,
+		BoardSize:      settings.BoardSize,
+		Ruleset:        settings.Ruleset,
+		Ranked:         settings.Ranked,
+		Public:         settings.Public,
+		CommitReveal:   settings.CommitReveal,
+		Toss:           toss,
+		State: model.GameState{
+			Side1Ships:         map[int]bool{},
+			Side1Ground:        side1Fields,
+			Side2Ships:         map[int]bool{},
+			Side2Ground:        side2Fields,
+			Side2RevealedShips: map[int]bool{},
+			Side1RevealedShips: map[int]bool{},
+		},
+		WinnerUser: nil,
+	}, nil
+}
+
+func (r GameServiceImpl) GetGame(ctx context.Context, request dto.GetGameRequest) (gameResponse dto.GetGameResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.GetGame")
+	defer tracing.End(span, &err)
 	gameResponse = dto.GetGameResponse{}
 
-	g, err := r.gameDao.GetOne(request.GameId)
+	g, err := r.gameDao.GetOne(ctx, request.GameId)
 	if err == nil {
 		if request.UserId != "" && g.Side1User.Hex() != request.UserId && g.Side2User.Hex() != request.UserId {
-			log.Error().Str("user_id", request.UserId).Msg("user does not have access to perform this operation")
+			tracing.Log(ctx).Error().Str("user_id", request.UserId).Msg("user does not have access to perform this operation")
 			return gameResponse, dto.Forbidden1("cannot perform the operation")
 		}
 
 		gameResponse.Game = new(dto.GameDto)
 		gameResponse.Game.FromGame(g, request.UserId)
+		if g.CommitReveal && g.Status == model.Finished {
+			events, err := r.gameEventDao.FindMany(ctx, request.GameId)
+			if err != nil {
+				tracing.Log(ctx).Error().Err(err).Str("game_id", request.GameId).Msg("cannot get events of game")
+			} else {
+				gameResponse.Game.AddPlacementLog(events)
+			}
+		}
+		if userId, err := primitive.ObjectIDFromHex(request.UserId); err == nil && g.Side2User != nil {
+			chat, err := r.chatService.GetChat(ctx, g, userId)
+			if err != nil {
+				tracing.Log(ctx).Error().Err(err).Str("game_id", request.GameId).Msg("cannot get chat of game")
+			} else {
+				gameResponse.Chat = &chat
+			}
+		}
 		gameResponse.Ok = true
 	} else {
-		log.Info().Str("gameId", request.GameId).Err(err).Msg("cannot get Game")
+		tracing.Log(ctx).Info().Str("gameId", request.GameId).Err(err).Msg("cannot get Game")
 	}
 	return gameResponse, err
 }
 
-func (r GameServiceImpl) JoinGame(request dto.JoinGameRequest) (response dto.GetGameResponse, err error) {
+func (r GameServiceImpl) JoinGame(ctx context.Context, request dto.JoinGameRequest) (response dto.GetGameResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.JoinGame")
+	defer tracing.End(span, &err)
 
 	response = dto.GetGameResponse{}
+	if err = checkAcceptingGames(); err != nil {
+		return response, err
+	}
+
+	var invite *model.Invite
+	if request.InviteCode != "" {
+		inv, err := r.inviteService.Resolve(ctx, request.InviteCode)
+		if err != nil {
+			tracing.Log(ctx).Info().Str("userId", request.UserId).Str("code", request.InviteCode).Err(err).Msg("cannot resolve invite")
+			return response, err
+		}
+		if request.GameId != "" && request.GameId != inv.GameId.Hex() {
+			return response, dto.BadRequest2("invite code does not belong to the game", error_codes.InviteInvalid)
+		}
+		request.GameId = inv.GameId.Hex()
+		invite = &inv
+	}
 
-	user, err := r.userDao.GetOne(request.UserId)
+	user, err := r.userDao.GetOne(ctx, request.UserId)
 	if err != nil {
-		log.Info().Str("userId", request.UserId).Str("gameId", request.GameId).Err(err).Msg("cannot get user")
+		tracing.Log(ctx).Info().Str("userId", request.UserId).Str("gameId", request.GameId).Err(err).Msg("cannot get user")
+		return response, err
+	}
+	if err = checkNotBanned(user); err != nil {
 		return response, err
 	}
 
-	game, err := r.gameDao.GetOne(request.GameId)
+	game, err := r.gameDao.GetOne(ctx, request.GameId)
 	if err != nil {
-		log.Info().Str("userId", request.UserId).Str("gameId", request.GameId).Err(err).Msg("cannot get game")
+		tracing.Log(ctx).Info().Str("userId", request.UserId).Str("gameId", request.GameId).Err(err).Msg("cannot get game")
 		return response, err
 	}
 
-	if game.Status == model.Init {
-		if game.Side1User == nil || game.Side1User.IsZero() {
-			log.Error().Str("userId", request.UserId).Str("gameId", request.GameId).
-				Msg("user wants to join but side1 has not joint")
-			return response, dto.BadRequest2("user wants to join but other side has not joint", error_codes.InvalidGameStatus)
-		}
-
-		if game.Side1User.Hex() != request.UserId {
-			game.Side2User = &user.Id
-			game.Status = model.Joined
+	err = r.stateMachine.CheckAction(ctx, game, model.JoinAction)
+	if err != nil {
+		return response, err
+	}
+	if game.Side1User == nil || game.Side1User.IsZero() {
+		tracing.Log(ctx).Error().Str("userId", request.UserId).Str("gameId", request.GameId).
+			Msg("user wants to join but side1 has not joint")
+		return response, dto.BadRequest2("user wants to join but other side has not joint", error_codes.InvalidGameStatus)
+	}
 
-			err = r.gameDao.Update(game)
+	if game.Side1User.Hex() != request.UserId {
+		blocked, err := r.blockService.IsBlocked(ctx, *game.Side1User, user.Id)
+		if err != nil {
+			return response, err
+		}
+		if blocked {
+			tracing.Log(ctx).Info().Str("userId", request.UserId).Str("gameId", request.GameId).Msg("game of blocked user is joined")
+			return response, dto.Forbidden2("game cannot be joined", error_codes.UserBlocked)
+		}
+		if !game.Public && invite == nil {
+			tracing.Log(ctx).Info().Str("userId", request.UserId).Str("gameId", request.GameId).Msg("private game is joined without invite")
+			return response, dto.Forbidden2("private game can be joined by invite code only", error_codes.InviteInvalid)
+		}
+		if game.PasscodeLocked(config.C.Invite.MaxPasscodeFailures) {
+			tracing.Log(ctx).Info().Str("userId", request.UserId).Str("gameId", request.GameId).Msg("passcode is locked")
+			return response, dto.Forbidden2("passcode is not correct", error_codes.PasscodeInvalid)
+		}
+		if game.PasscodeHash != "" && !checkPasscode(game.PasscodeHash, request.Passcode) {
+			failures, err := r.gameDao.AddPasscodeFailure(ctx, game.Id)
 			if err != nil {
-				log.Info().Str("userId", request.UserId).Str("gameId", request.GameId).Err(err).
-					Msg("cannot update game")
 				return response, err
 			}
+			tracing.Log(ctx).Info().Str("userId", request.UserId).Str("gameId", request.GameId).Int("failures", failures).
+				Msg("passcode is not correct")
+			return response, dto.Forbidden2("passcode is not correct", error_codes.PasscodeInvalid)
+		}
+		game.Side2User = &user.Id
+		game.Status = model.Joined
+		game.LastMoveTime = time.Now()
+		if game.Toss != nil {
+			game.Toss.Commit(2, request.TossCommitment)
+		}
 
-			_, err = r.gameEventDao.Insert(model.GameEvent{
-				Time:   time.Now(),
-				Type:   model.JoinGame,
-				GameId: game.Id,
-				UserId: &user.Id,
-			})
+		err = r.stateMachine.Save(ctx, game, model.Init, &user.Id)
+		if err != nil {
+			tracing.Log(ctx).Info().Str("userId", request.UserId).Str("gameId", request.GameId).Err(err).
+				Msg("cannot update game")
+			return response, err
+		}
+		r.lobbyService.GameRemoved(ctx, game)
+		r.webhookService.GameEvent(ctx, model.GameJoinedWebhook, game)
+
+		_, err = r.gameEventDao.Insert(ctx, model.GameEvent{
+			Time:   time.Now(),
+			Type:   model.JoinGame,
+			GameId: game.Id,
+			UserId: &user.Id,
+		})
+	}
+
+	response.Game = new(dto.GameDto)
+	response.Game.FromGame(game, request.UserId)
+	response.Ok = true
+	return response, err
+}
+
+// CancelGame cancels a game that has not started yet, only the creator of the game can cancel it.
+func (r GameServiceImpl) CancelGame(ctx context.Context, request dto.CancelGameRequest) (response dto.GetGameResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.CancelGame")
+	defer tracing.End(span, &err)
+	response = dto.GetGameResponse{}
+	game, err := r.gameDao.GetOne(ctx, request.GameId)
+	if err != nil {
+		tracing.Log(ctx).Info().Str("userId", request.UserId).Str("gameId", request.GameId).Err(err).Msg("cannot get game")
+		return response, err
+	}
+	if game.Side1User == nil || game.Side1User.Hex() != request.UserId {
+		tracing.Log(ctx).Warn().Str("user_id", request.UserId).Str("game_id", request.GameId).Msg("only creator can cancel game")
+		return response, dto.Forbidden1("only creator of the game can cancel it")
+	}
+
+	err = r.cancel(ctx, &game, model.CancelledByUser, game.Side1User)
+	if err != nil {
+		return response, err
+	}
+
+	response.Game = new(dto.GameDto)
+	response.Game.FromGame(game, request.UserId)
+	response.Ok = true
+	return response, nil
+}
+
+// FinishByAdmin finishes a started or paused game, the game has no winner when winnerUserId is empty.
+func (r GameServiceImpl) FinishByAdmin(ctx context.Context, gameId string, winnerUserId string) (game model.Game, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.FinishByAdmin")
+	defer tracing.End(span, &err)
+	game, err = r.gameDao.GetOne(ctx, gameId)
+	if err != nil {
+		return game, err
+	}
+	var winner *primitive.ObjectID
+	if winnerUserId != "" {
+		for _, side := range []*primitive.ObjectID{game.Side1User, game.Side2User} {
+			if side != nil && side.Hex() == winnerUserId {
+				winner = side
+			}
+		}
+		if winner == nil {
+			return game, dto.BadRequest1("winner does not belong to game")
+		}
+	}
+
+	status := game.Status
+	if !model.CanTransition(status, model.Finished) {
+		return game, dto.BadRequest2(fmt.Sprintf("game cannot be finished when it is %s", status),
+			error_codes.InvalidGameStatus)
+	}
+	game.Finish(winner, model.FinishedByAdmin)
+	err = r.stateMachine.Save(ctx, game, status, nil)
+	if err != nil {
+		tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Err(err).Msg("cannot finish game by admin")
+		return game, err
+	}
+	r.gameFinished(ctx, &game)
+
+	endGameEvent := dto.EndGameEvent{GameId: utils.EncodeId(game.Id.Hex())}
+	if winner != nil {
+		endGameEvent.WinnerUserId = utils.EncodeId(winner.Hex())
+	}
+	err = r.eventHandler.EndGame(ctx, endGameEvent)
+	if err != nil {
+		tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Msg("cannot send end game event")
+	}
+	return game, nil
+}
+
+// CancelByAdmin cancels a game that has not started yet.
+func (r GameServiceImpl) CancelByAdmin(ctx context.Context, gameId string) (game model.Game, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.CancelByAdmin")
+	defer tracing.End(span, &err)
+	game, err = r.gameDao.GetOne(ctx, gameId)
+	if err != nil {
+		return game, err
+	}
+	err = r.cancel(ctx, &game, model.CancelledByAdmin, nil)
+	return game, err
+}
+
+// ExpireGames cancels games that nobody joined or that are not started in the configured time.
+func (r GameServiceImpl) ExpireGames(ctx context.Context) {
+	ctx, span := tracing.Start(ctx, "GameService.ExpireGames")
+	defer span.End()
+	const batchSize = 100
+	now := time.Now()
+	initBefore := now.Add(-time.Duration(config.C.Game.InitExpireSec) * time.Second)
+	joinedBefore := now.Add(-time.Duration(config.C.Game.JoinedExpireSec) * time.Second)
+	for {
+		games, err := r.gameDao.FindExpired(ctx, initBefore, joinedBefore, batchSize)
+		if err != nil {
+			tracing.Log(ctx).Error().Err(err).Msg("cannot find expired games")
+			return
+		}
+		cancelled := 0
+		for i := range games {
+			if err := r.cancel(ctx, &games[i], model.Expired, nil); err == nil {
+				cancelled++
+			}
 		}
+		tracing.Log(ctx).Debug().Int("count", cancelled).Msg("expired games are cancelled")
+		if len(games) < batchSize || cancelled == 0 {
+			return
+		}
+	}
+}
+
+// cancel moves a game in init or joined status to cancelled, and notifies sockets of the game and the lobby.
+func (r GameServiceImpl) cancel(ctx context.Context, game *model.Game, reason model.EndReason, userId *primitive.ObjectID) error {
+	err := r.stateMachine.CheckAction(ctx, *game, model.CancelAction)
+	if err != nil {
+		return err
+	}
 
-		response.Game = new(dto.GameDto)
-		response.Game.FromGame(game, request.UserId)
-		response.Ok = true
+	status := game.Status
+	game.Cancel(reason)
+	err = r.stateMachine.Save(ctx, *game, status, userId)
+	if err != nil {
+		tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Err(err).Msg("cannot cancel game")
+		return err
+	}
+
+	if status == model.Init {
+		r.lobbyService.GameRemoved(ctx, *game)
+	}
+	for _, side := range []*primitive.ObjectID{game.Side1User, game.Side2User} {
+		if side != nil {
+			cache.RemovePresence(game.Id.Hex(), side.Hex())
+		}
+	}
+	r.webhookService.GameEvent(ctx, model.GameCancelledWebhook, *game)
+	err = r.eventHandler.GameCancelled(ctx, dto.GameCancelledEvent{
+		GameId: utils.EncodeId(game.Id.Hex()),
+		Reason: reason,
+	})
+	if err != nil {
+		tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Msg("cannot send game cancelled event")
+	}
+	return nil
+}
+
+// RequestPause asks the other side to pause a started game, the game is paused when the other side accepts.
+func (r GameServiceImpl) RequestPause(ctx context.Context, request dto.PauseGameRequest) (response dto.PauseGameResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.RequestPause")
+	defer tracing.End(span, &err)
+	response = dto.PauseGameResponse{}
+	game, userId, err := r.getGameOfSide(ctx, request.GameId, request.UserId)
+	if err != nil {
 		return response, err
 	}
-	log.Info().Str("userId", request.UserId).Str("gameId", request.GameId).Str("status", string(game.Status)).Err(err).
-		Msg("game status is not suitable for joining")
-	return response, dto.BadRequest2("game status is not suitable for joining", error_codes.InvalidGameStatus)
+	err = r.stateMachine.CheckAction(ctx, game, model.PauseAction)
+	if err != nil {
+		return response, err
+	}
+
+	game.PauseBy = &userId
+	err = r.stateMachine.Save(ctx, game, model.Start, &userId)
+	if err != nil {
+		return response, err
+	}
+	r.pauseChanged(ctx, game, &userId, model.PauseRequested, dto.PauseRequested)
+
+	response.Ok = true
+	response.Status = game.Status
+	return response, nil
 }
 
-func (r GameServiceImpl) SubmitShipsLocations(request dto.SubmitShipsLocationsRequest) (response dto.SubmitShipsLocationsResponse, err error) {
+// AcceptPause pauses the game if the other side has requested it.
+func (r GameServiceImpl) AcceptPause(ctx context.Context, request dto.AcceptPauseRequest) (response dto.PauseGameResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.AcceptPause")
+	defer tracing.End(span, &err)
+	response = dto.PauseGameResponse{}
+	game, userId, err := r.getGameOfSide(ctx, request.GameId, request.UserId)
+	if err != nil {
+		return response, err
+	}
+	err = r.stateMachine.CheckAction(ctx, game, model.AcceptPauseAction)
+	if err != nil {
+		return response, err
+	}
+	if game.PauseBy == nil || *game.PauseBy == userId {
+		tracing.Log(ctx).Info().Str("game_id", request.GameId).Str("user_id", request.UserId).Msg("no pause request of the other side")
+		return response, dto.BadRequest1("the other side has not requested a pause")
+	}
+
+	now := time.Now()
+	game.Pause(now, now.Add(time.Duration(config.C.Pause.MaxSec)*time.Second))
+	err = r.stateMachine.Save(ctx, game, model.Start, &userId)
+	if err != nil {
+		return response, err
+	}
+	r.pauseChanged(ctx, game, &userId, model.PauseAccepted, dto.Paused)
+
+	response.Ok = true
+	response.Status = game.Status
+	response.PauseDeadline = game.PauseDeadline
+	return response, nil
+}
+
+// Resume resumes a paused game when both sides have asked for it.
+func (r GameServiceImpl) Resume(ctx context.Context, request dto.ResumeGameRequest) (response dto.PauseGameResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.Resume")
+	defer tracing.End(span, &err)
+	response = dto.PauseGameResponse{}
+	game, userId, err := r.getGameOfSide(ctx, request.GameId, request.UserId)
+	if err != nil {
+		return response, err
+	}
+	err = r.stateMachine.CheckAction(ctx, game, model.ResumeAction)
+	if err != nil {
+		return response, err
+	}
+
+	if game.ResumeBy != nil && *game.ResumeBy != userId {
+		now := time.Now()
+		game.Resume(now)
+		err = r.stateMachine.Save(ctx, game, model.Paused, &userId)
+		if err != nil {
+			return response, err
+		}
+		cache.ResetDisconnected(game.Id.Hex(), now, game.Side1User.Hex(), game.Side2User.Hex())
+		r.pauseChanged(ctx, game, &userId, model.Resumed, dto.Resumed)
+	} else {
+		game.ResumeBy = &userId
+		err = r.stateMachine.Save(ctx, game, model.Paused, &userId)
+		if err != nil {
+			return response, err
+		}
+		r.pauseChanged(ctx, game, &userId, model.ResumeRequested, dto.ResumeRequested)
+	}
+
+	response.Ok = true
+	response.Status = game.Status
+	response.PauseDeadline = game.PauseDeadline
+	return response, nil
+}
+
+// ResumeExpiredPauses resumes paused games whose pause deadline is passed.
+func (r GameServiceImpl) ResumeExpiredPauses(ctx context.Context) {
+	ctx, span := tracing.Start(ctx, "GameService.ResumeExpiredPauses")
+	defer span.End()
+	const batchSize = 100
+	now := time.Now()
+	for {
+		games, err := r.gameDao.FindPauseExpired(ctx, now, batchSize)
+		if err != nil {
+			tracing.Log(ctx).Error().Err(err).Msg("cannot find pause expired games")
+			return
+		}
+		resumed := 0
+		for _, game := range games {
+			game.Resume(now)
+			if err := r.stateMachine.Save(ctx, game, model.Paused, nil); err != nil {
+				continue
+			}
+			cache.ResetDisconnected(game.Id.Hex(), now, game.Side1User.Hex(), game.Side2User.Hex())
+			r.pauseChanged(ctx, game, nil, model.Resumed, dto.Resumed)
+			resumed++
+		}
+		tracing.Log(ctx).Debug().Int("count", resumed).Msg("pause expired games are resumed")
+		if len(games) < batchSize || resumed == 0 {
+			return
+		}
+	}
+}
+
+// pauseChanged saves the pause event of the game and sends it to both sides.
+func (r GameServiceImpl) pauseChanged(ctx context.Context, game model.Game, userId *primitive.ObjectID, eventType model.GameEventType,
+	socketEventType dto.SocketEventType) {
+	_, err := r.gameEventDao.Insert(ctx, model.GameEvent{
+		Time:   time.Now(),
+		Type:   eventType,
+		GameId: game.Id,
+		UserId: userId,
+	})
+	if err != nil {
+		tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Str("type", string(eventType)).Msg("cannot save pause event")
+	}
+
+	event := dto.PauseEvent{
+		GameId:        utils.EncodeId(game.Id.Hex()),
+		PauseDeadline: game.PauseDeadline,
+	}
+	if userId != nil {
+		event.UserId = utils.EncodeId(userId.Hex())
+	}
+	err = r.eventHandler.Pause(ctx, socketEventType, event)
+	if err != nil {
+		tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Str("type", string(socketEventType)).Msg("cannot send pause event")
+	}
+}
+
+// getGameOfSide returns the game and the id of userId if user is one of the sides of the game.
+func (r GameServiceImpl) getGameOfSide(ctx context.Context, gameId string, userId string) (game model.Game, user primitive.ObjectID, err error) {
+	game, err = r.gameDao.GetOne(ctx, gameId)
+	if err != nil {
+		tracing.Log(ctx).Info().Str("game_id", gameId).Str("user_id", userId).Err(err).Msg("cannot get game")
+		return game, user, err
+	}
+	if game.Side1User != nil && game.Side1User.Hex() == userId {
+		return game, *game.Side1User, nil
+	}
+	if game.Side2User != nil && game.Side2User.Hex() == userId {
+		return game, *game.Side2User, nil
+	}
+	tracing.Log(ctx).Error().Str("game_id", gameId).Str("user_id", userId).Msg("user does not belong to this game")
+	return game, user, dto.Forbidden1("user does not belong to this game")
+}
+
+func (r GameServiceImpl) SubmitShipsLocations(ctx context.Context, request dto.SubmitShipsLocationsRequest) (response dto.SubmitShipsLocationsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.SubmitShipsLocations")
+	defer tracing.End(span, &err)
 	response = dto.SubmitShipsLocationsResponse{}
-	game, err := r.gameDao.GetOne(request.GameId)
+	game, err := r.gameDao.GetOne(ctx, request.GameId)
 	if err != nil {
-		log.Warn().Err(err).Str("game_id", request.GameId).Msg("error in get game by id")
+		tracing.Log(ctx).Warn().Err(err).Str("game_id", request.GameId).Msg("error in get game by id")
 		return response, err
 	}
 
-	if game.Status != model.Joined {
-		log.Warn().Msg("game status is not init")
-		return response, dto.BadRequest2("Game status is not valid", error_codes.InvalidGameStatus)
+	err = r.stateMachine.CheckAction(ctx, game, model.SubmitShipsAction)
+	if err != nil {
+		return response, err
 	}
 
 	ships := make(map[int]bool)
 	for _, element := range request.ShipsIndexes {
 		if element < 0 || element >= 100 {
-			log.Error().Str("game_id", request.GameId).Str("user_id", request.UserId).
+			tracing.Log(ctx).Error().Str("game_id", request.GameId).Str("user_id", request.UserId).
 				Int("index", element).Msg("ship indexes must be between 1 and 100")
 			return response, dto.BadRequest2("ship indexes must be between 1 and 100", error_codes.InvalidShipIndexValue)
 		}
@@ -209,64 +844,102 @@ func (r GameServiceImpl) SubmitShipsLocations(request dto.SubmitShipsLocationsRe
 	}
 
 	if len(ships) != 10 {
-		log.Error().Str("game_id", request.GameId).Str("user_id", request.UserId).Msg("repeated index is not allowed in ship indexes")
+		tracing.Log(ctx).Error().Str("game_id", request.GameId).Str("user_id", request.UserId).Msg("repeated index is not allowed in ship indexes")
 		return response, dto.BadRequest2("repeated index is not allowed in ship indexes", error_codes.InvalidShipIndexValue)
 	}
 
+	var placement *model.PlacementCommitment
+	if game.CommitReveal {
+		placement = &model.PlacementCommitment{
+			Commitment: strings.ToLower(request.Commitment),
+			Salt:       request.Salt,
+			Ships:      utils.GetMapKeySlice(ships),
+		}
+		sort.Ints(placement.Ships)
+		if request.Commitment == "" || !placement.Verify() {
+			tracing.Log(ctx).Error().Str("game_id", request.GameId).Str("user_id", request.UserId).
+				Msg("placement commitment does not match the ships")
+			return response, dto.BadRequest2("placement commitment does not match the ships", error_codes.PlacementCommitmentInvalid)
+		}
+	}
+
 	var otherSide string
+	var userId *primitive.ObjectID
 	if game.Side1User != nil && game.Side1User.Hex() == request.UserId {
 		if len(game.State.Side1Ships) > 0 {
-			log.Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
+			tracing.Log(ctx).Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
 				Msg("user already has chosen his/her ships location")
 			return response, dto.Duplicate1("user already has chosen his/her ships location")
 		}
 		game.State.Side1Ships = ships
+		game.Side1Placement = placement
+		if game.Toss != nil && !game.Toss.Reveal(1, request.TossSeed) {
+			tracing.Log(ctx).Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
+				Msg("toss seed does not match the toss commitment")
+			return response, dto.BadRequest2("toss seed does not match the toss commitment", error_codes.TossSeedInvalid)
+		}
 		otherSide = game.Side2User.Hex()
+		userId = game.Side1User
 
-		err := r.persistInitialShipLocationEvent(&game.Id, game.Side1User, utils.GetMapKeySlice(ships))
+		err := r.persistInitialShipLocationEvent(ctx, &game.Id, game.Side1User, utils.GetMapKeySlice(ships))
 		if err != nil {
-			log.Error().Msg("cannot save initial ship location event")
+			tracing.Log(ctx).Error().Msg("cannot save initial ship location event")
 		}
 	} else if game.Side2User != nil && game.Side2User.Hex() == request.UserId {
 		if len(game.State.Side2Ships) > 0 {
-			log.Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
+			tracing.Log(ctx).Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
 				Msg("user already has chosen his/her ships location")
 			return response, dto.Duplicate1("user already has chosen his/her ships location")
 		}
 		game.State.Side2Ships = ships
+		game.Side2Placement = placement
+		if game.Toss != nil && !game.Toss.Reveal(2, request.TossSeed) {
+			tracing.Log(ctx).Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
+				Msg("toss seed does not match the toss commitment")
+			return response, dto.BadRequest2("toss seed does not match the toss commitment", error_codes.TossSeedInvalid)
+		}
 		otherSide = game.Side1User.Hex()
+		userId = game.Side2User
 
-		err := r.persistInitialShipLocationEvent(&game.Id, game.Side2User, utils.GetMapKeySlice(ships))
+		err := r.persistInitialShipLocationEvent(ctx, &game.Id, game.Side2User, utils.GetMapKeySlice(ships))
 		if err != nil {
-			log.Error().Msg("cannot save initial ship location event")
+			tracing.Log(ctx).Error().Msg("cannot save initial ship location event")
 		}
 	} else {
-		log.Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
+		tracing.Log(ctx).Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
 			Msg("user is not belong to this game")
 		return response, dto.BadRequest1("user is not belong to this game")
 	}
 
 	if len(game.State.Side2Ships) == 10 && len(game.State.Side1Ships) == 10 {
 		game.Status = model.Start
+		if game.Toss != nil {
+			game.Turn = game.Toss.Toss()
+		} else if game.Turn == 0 {
+			// games created before the coin toss
+			game.Turn = model.RandomTurn()
+		}
 	}
 
 	game.LastMoveTime = time.Now()
 
-	err = r.gameDao.Update(game)
+	err = r.stateMachine.Save(ctx, game, model.Joined, userId)
 	if err != nil {
-		log.Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
+		tracing.Log(ctx).Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
 			Err(err).Msg("cannot update game")
 		return response, err
 	}
 
 	if game.Status == model.Start {
+		r.webhookService.GameEvent(ctx, model.GameStartedWebhook, game)
+		metrics.GameStarted()
 		gameDto := dto.GameDto{}
 		gameDto.FromGame(game, otherSide)
-		err := r.eventHandler.GameStart(dto.GameStartEvent{
+		err := r.eventHandler.GameStart(ctx, dto.GameStartEvent{
 			Game: gameDto,
 		})
 		if err != nil {
-			log.Error().Str("game_id", game.Id.Hex()).Err(err).Msg("cannot send event")
+			tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Err(err).Msg("cannot send event")
 			return response, err
 		}
 	}
@@ -277,142 +950,151 @@ func (r GameServiceImpl) SubmitShipsLocations(request dto.SubmitShipsLocationsRe
 	return response, nil
 }
 
-func (r GameServiceImpl) persistInitialShipLocationEvent(gameId *primitive.ObjectID, userId *primitive.ObjectID, initialShipLocations []int) error {
+func (r GameServiceImpl) persistInitialShipLocationEvent(ctx context.Context, gameId *primitive.ObjectID, userId *primitive.ObjectID, initialShipLocations []int) error {
 	event := model.GameEvent{
 		Type:                  model.InitialShipsLocations,
 		InitialShipsLocations: initialShipLocations,
 		Time:                  time.Now(),
-		UserId:                gameId,
-		GameId:                *userId,
+		UserId:                userId,
+		GameId:                *gameId,
 	}
-	_, err := r.gameEventDao.Insert(event)
+	_, err := r.gameEventDao.Insert(ctx, event)
 	if err != nil {
-		log.Error().Str("game_id", gameId.Hex()).Str("user_id", userId.Hex()).
+		tracing.Log(ctx).Error().Str("game_id", gameId.Hex()).Str("user_id", userId.Hex()).
 			Msg("cannot save initial ship location event")
 		return err
 	}
 	return nil
 }
 
-func (r GameServiceImpl) ChangeTurn(request dto.ChangeTurnRequest) (response dto.ChangeTurnResponse, err error) {
+func (r GameServiceImpl) ChangeTurn(ctx context.Context, request dto.ChangeTurnRequest) (response dto.ChangeTurnResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.ChangeTurn")
+	defer tracing.End(span, &err)
 	response = dto.ChangeTurnResponse{}
 
-	game, userId, otherSideUserId, err := r.getGameCheckItWithUserAndChangeTurn(request)
+	game, userId, otherSideUserId, err := r.getGameCheckItWithUserAndChangeTurn(ctx, request, model.ChangeTurnAction)
 	if err != nil {
 		if errors.Is(err, error_codes.NotUserTurn) && !game.Id.IsZero() {
 			//in case of leaving game by one of the sides, the present side have to be enable to change turn
 			if game.LastMoveTime.Add(time.Duration(game.MoveTimeoutSec+2) * time.Second).Before(time.Now()) {
-				if game.Turn == 1 {
-					game.Turn = 2
-				} else {
-					game.Turn = 1
-				}
-
-				gameEvents, err := r.gameEventDao.FindMany(request.GameId)
-				if err != nil {
-					log.Err(err).Str("game_id", request.GameId).Str("user_id", request.UserId).
-						Msg("error in getting game events")
-					return response, err
-				}
-				//if four last moves belong to one side, other side is not present anymore and game will be finished
-				if len(gameEvents) >= 4 {
-					if gameEvents[0].UserId.Hex() == gameEvents[1].UserId.Hex() &&
-						gameEvents[0].UserId.Hex() == gameEvents[2].UserId.Hex() &&
-						gameEvents[0].UserId.Hex() == gameEvents[3].UserId.Hex() &&
-						gameEvents[0].UserId.Hex() == gameEvents[4].UserId.Hex() {
-						game.Status = model.Finished
-						game.WinnerUser = gameEvents[0].UserId
-
-						err := r.eventHandler.EndGame(dto.EndGameEvent{
-							GameId:       utils.MaskId(request.GameId),
-							WinnerUserId: utils.MaskId(game.WinnerUser.Hex()),
-						})
-						if err != nil {
-							log.Error().Str("game_id", request.GameId).Msg("cannot send end game event")
-						}
+				game.SkipTurn(time.Now())
+				if r.isAbandoned(game, otherSideUserId, time.Now()) {
+					tracing.Log(ctx).Info().Str("game_id", request.GameId).Str("user_id", otherSideUserId.Hex()).
+						Msg("game is abandoned")
+					game.Finish(&userId, model.Abandoned)
+
+					err := r.eventHandler.EndGame(ctx, dto.EndGameEvent{
+						GameId:       utils.EncodeId(request.GameId),
+						WinnerUserId: utils.EncodeId(game.WinnerUser.Hex()),
+					})
+					if err != nil {
+						tracing.Log(ctx).Error().Str("game_id", request.GameId).Msg("cannot send end game event")
 					}
 				}
 			} else {
-				log.Err(err).Str("game_id", request.GameId).Str("user_id", request.UserId).
+				tracing.Log(ctx).Err(err).Str("game_id", request.GameId).Str("user_id", request.UserId).
 					Msg("it's not user turn")
 				return response, err
 			}
 		} else {
-			log.Error().Err(err).Msg("error in checking game")
+			tracing.Log(ctx).Error().Err(err).Msg("error in checking game")
 			return response, err
 		}
 	}
 
-	err = r.gameDao.Update(game)
+	err = r.stateMachine.Save(ctx, game, model.Start, &userId)
 	if err != nil {
-		log.Err(err).Str("game_id", request.GameId).Str("user_id", request.UserId).
+		tracing.Log(ctx).Err(err).Str("game_id", request.GameId).Str("user_id", request.UserId).
 			Msg("error in updating game")
+		return response, err
 	}
+	r.gameFinished(ctx, &game)
 
 	if game.Status != model.Finished {
-		_, err = r.gameEventDao.Insert(model.GameEvent{
+		_, err = r.gameEventDao.Insert(ctx, model.GameEvent{
 			Time:   time.Now(),
 			Type:   model.ChangeTurn,
 			GameId: game.Id,
 			UserId: &userId,
 		})
 
-		err = r.eventHandler.ChangeTurn(dto.GameChangeTurnEvent{
-			GameId: utils.MaskId(request.GameId),
-			UserId: utils.MaskId(otherSideUserId.Hex()),
+		err = r.eventHandler.ChangeTurn(ctx, dto.GameChangeTurnEvent{
+			GameId: utils.EncodeId(request.GameId),
+			UserId: utils.EncodeId(otherSideUserId.Hex()),
 		})
 		if err != nil {
-			log.Err(err).Msg("cannot send change turn event")
+			tracing.Log(ctx).Err(err).Msg("cannot send change turn event")
 		}
 	}
 	response.Ok = true
 	return response, nil
 }
 
-func (r GameServiceImpl) MoveShip(request dto.MoveShipRequest) (response dto.MoveShipResponse, err error) {
+// isAbandoned applies the abandonment policy to a side that missed its turn. A side that has never connected to the
+// game socket is considered disconnected since the game is created.
+func (r GameServiceImpl) isAbandoned(game model.Game, userId primitive.ObjectID, now time.Time) bool {
+	side := model.SidePresence{
+		MissedTurns:       game.MissedTurns(userId),
+		DisconnectedSince: game.CreateDate,
+	}
+	if presence, ok := cache.GetPresence(game.Id.Hex(), userId.Hex()); ok {
+		side.Connected = presence.Connected
+		side.DisconnectedSince = presence.DisconnectedSince
+	}
+	policy := model.AbandonmentPolicy{
+		MaxMissedTurns:     config.C.Abandonment.MaxMissedTurns,
+		MaxDisconnectedSec: config.C.Abandonment.MaxDisconnectedSec,
+	}
+	return policy.IsAbandoned(side, now)
+}
+
+func (r GameServiceImpl) MoveShip(ctx context.Context, request dto.MoveShipRequest) (response dto.MoveShipResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.MoveShip")
+	defer tracing.End(span, &err)
 	response = dto.MoveShipResponse{}
-	game, userId, otherSide, err := r.getGameCheckItWithUserAndChangeTurn(request)
+	game, userId, otherSide, err := r.getGameCheckItWithUserAndChangeTurn(ctx, request, model.MoveShipAction)
 	if err != nil {
-		log.Error().Err(err).Msg("error in checking game")
+		tracing.Log(ctx).Error().Err(err).Msg("error in checking game")
 		return response, err
 	}
 
 	if game.Side1User.Hex() == request.UserId {
 		err := game.MoveShipSide1(request.OldShipIndex, request.NewShipIndex)
 		if err != nil {
-			log.Err(err).Str("game_id", request.GameId).Str("user_id", request.UserId).
+			tracing.Log(ctx).Err(err).Str("game_id", request.GameId).Str("user_id", request.UserId).
 				Err(err).Msg("error in move ship in side 1")
 			return response, err
 		}
 	} else if game.Side2User.Hex() == request.UserId {
 		err := game.MoveShipSide2(request.OldShipIndex, request.NewShipIndex)
 		if err != nil {
-			log.Err(err).Str("game_id", request.GameId).Str("user_id", request.UserId).
+			tracing.Log(ctx).Err(err).Str("game_id", request.GameId).Str("user_id", request.UserId).
 				Err(err).Msg("error in move ship in side 2")
 			return response, err
 		}
 	}
 
-	err = r.gameDao.Update(game)
+	err = r.stateMachine.Save(ctx, game, model.Start, &userId)
 	if err != nil {
-		log.Error().Str("game_id", request.GameId).Msg("cannot update game state")
+		tracing.Log(ctx).Error().Str("game_id", request.GameId).Msg("cannot update game state")
 		return response, err
 	}
 
-	err = r.submitMoveShipEvent(game.Id, userId, request.OldShipIndex, request.NewShipIndex)
+	err = r.submitMoveShipEvent(ctx, game.Id, userId, request.OldShipIndex, request.NewShipIndex)
 	if err != nil {
-		log.Error().Str("game_id", request.GameId).Str("user_id", request.UserId).
+		tracing.Log(ctx).Error().Str("game_id", request.GameId).Str("user_id", request.UserId).
 			Msg("cannot save move ship event")
 		return response, err
 	}
 
-	err = r.eventHandler.MoveShip(dto.ShipMovedEvent{
-		GameId:       utils.MaskId(request.GameId),
-		UserId:       utils.MaskId(otherSide.Hex()),
+	metrics.GameAction(string(model.MoveShipAction))
+	err = r.eventHandler.MoveShip(ctx, dto.ShipMovedEvent{
+		GameId:       utils.EncodeId(request.GameId),
+		UserId:       utils.EncodeId(otherSide.Hex()),
 		OldShipIndex: request.OldShipIndex,
 	})
 	if err != nil {
-		log.Error().Str("game_id", request.GameId).Str("user_id", request.UserId).
+		tracing.Log(ctx).Error().Str("game_id", request.GameId).Str("user_id", request.UserId).
 			Msg("cannot send ship move event")
 		return response, err
 	}
@@ -421,7 +1103,7 @@ func (r GameServiceImpl) MoveShip(request dto.MoveShipRequest) (response dto.Mov
 	return response, nil
 }
 
-func (r GameServiceImpl) submitMoveShipEvent(gameId primitive.ObjectID, userId primitive.ObjectID, oldShipIndex int,
+func (r GameServiceImpl) submitMoveShipEvent(ctx context.Context, gameId primitive.ObjectID, userId primitive.ObjectID, oldShipIndex int,
 	newShipIndex int) error {
 	event := model.GameEvent{
 		Type:         model.MoveShip,
@@ -431,20 +1113,22 @@ func (r GameServiceImpl) submitMoveShipEvent(gameId primitive.ObjectID, userId p
 		UserId:       &userId,
 		GameId:       gameId,
 	}
-	_, err := r.gameEventDao.Insert(event)
+	_, err := r.gameEventDao.Insert(ctx, event)
 	if err != nil {
-		log.Error().Str("game_id", gameId.Hex()).Str("user_id", userId.Hex()).
+		tracing.Log(ctx).Error().Str("game_id", gameId.Hex()).Str("user_id", userId.Hex()).
 			Msg("cannot save move ship event")
 		return err
 	}
 	return nil
 }
 
-func (r GameServiceImpl) Reveal(request dto.RevealEnemyFieldsRequest) (response dto.RevealEnemyFieldsResponse, err error) {
+func (r GameServiceImpl) Reveal(ctx context.Context, request dto.RevealEnemyFieldsRequest) (response dto.RevealEnemyFieldsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.Reveal")
+	defer tracing.End(span, &err)
 	response = dto.RevealEnemyFieldsResponse{}
-	game, userId, otherSide, err := r.getGameCheckItWithUserAndChangeTurn(request)
+	game, userId, otherSide, err := r.getGameCheckItWithUserAndChangeTurn(ctx, request, model.RevealAction)
 	if err != nil {
-		log.Error().Err(err).Msg("error in checking game")
+		tracing.Log(ctx).Error().Err(err).Msg("error in checking game")
 		return response, err
 	}
 
@@ -455,25 +1139,26 @@ func (r GameServiceImpl) Reveal(request dto.RevealEnemyFieldsRequest) (response
 		revealedShipsIndexes = game.RevealSlotSide1(request.Index)
 	}
 
-	err = r.gameDao.Update(game)
+	err = r.stateMachine.Save(ctx, game, model.Start, &userId)
 	if err != nil {
-		log.Warn().Str("game_id", request.GameId).Msg("cannot update game")
+		tracing.Log(ctx).Warn().Str("game_id", request.GameId).Msg("cannot update game")
 		return response, err
 	}
 
-	err = r.PersistRevealEvent(game.Id, userId, request.Index, revealedShipsIndexes)
+	err = r.PersistRevealEvent(ctx, game.Id, userId, request.Index, revealedShipsIndexes)
 	if err != nil {
-		log.Error().Msg("cannot save reveal event")
+		tracing.Log(ctx).Error().Msg("cannot save reveal event")
 	}
 
-	err = r.eventHandler.Reveal(dto.RevealEvent{
-		UserId:        utils.MaskId(otherSide.Hex()),
-		GameId:        utils.MaskId(request.GameId),
+	metrics.GameAction(string(model.RevealAction))
+	err = r.eventHandler.Reveal(ctx, dto.RevealEvent{
+		UserId:        utils.EncodeId(otherSide.Hex()),
+		GameId:        utils.EncodeId(request.GameId),
 		RevealedShips: revealedShipsIndexes,
 		Slots:         model.FindNeighborIndexes(request.Index),
 	})
 	if err != nil {
-		log.Error().Str("game_id", request.GameId).Str("user_id", request.UserId).
+		tracing.Log(ctx).Error().Str("game_id", request.GameId).Str("user_id", request.UserId).
 			Msg("cannot send reveal event")
 	}
 
@@ -482,7 +1167,9 @@ func (r GameServiceImpl) Reveal(request dto.RevealEnemyFieldsRequest) (response
 	return response, nil
 }
 
-func (r GameServiceImpl) PersistRevealEvent(gameId primitive.ObjectID, userId primitive.ObjectID, index int, revealedShipIndexes []int) error {
+func (r GameServiceImpl) PersistRevealEvent(ctx context.Context, gameId primitive.ObjectID, userId primitive.ObjectID, index int, revealedShipIndexes []int) error {
+	ctx, span := tracing.Start(ctx, "GameService.PersistRevealEvent")
+	defer span.End()
 	event := model.GameEvent{
 		Type:               model.Reveal,
 		Time:               time.Now(),
@@ -491,21 +1178,23 @@ func (r GameServiceImpl) PersistRevealEvent(gameId primitive.ObjectID, userId pr
 		UserId:             &userId,
 		GameId:             gameId,
 	}
-	_, err := r.gameEventDao.Insert(event)
+	_, err := r.gameEventDao.Insert(ctx, event)
 	if err != nil {
-		log.Error().Str("game_id", gameId.Hex()).Str("user_id", userId.Hex()).
+		tracing.Log(ctx).Error().Str("game_id", gameId.Hex()).Str("user_id", userId.Hex()).
 			Msg("cannot save reveal event")
 		return err
 	}
 	return nil
 }
 
-func (r GameServiceImpl) Explode(request dto.ExplodeRequest) (response dto.ExplodeResponse, err error) {
+func (r GameServiceImpl) Explode(ctx context.Context, request dto.ExplodeRequest) (response dto.ExplodeResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GameService.Explode")
+	defer tracing.End(span, &err)
 	response = dto.ExplodeResponse{}
 
-	game, userId, otherSide, err := r.getGameCheckItWithUserAndChangeTurn(request)
+	game, userId, otherSide, err := r.getGameCheckItWithUserAndChangeTurn(ctx, request, model.ExplodeAction)
 	if err != nil {
-		log.Error().Err(err).Msg("error in checking game")
+		tracing.Log(ctx).Error().Err(err).Msg("error in checking game")
 		return response, err
 	}
 
@@ -523,35 +1212,37 @@ func (r GameServiceImpl) Explode(request dto.ExplodeRequest) (response dto.Explo
 		}
 	}
 
-	err = r.PersistExplosionEvent(game.Id, userId, request.Index, !response.HasShip)
+	err = r.PersistExplosionEvent(ctx, game.Id, userId, request.Index, !response.HasShip)
 	if err != nil {
-		log.Error().Msg("cannot save explosion event")
+		tracing.Log(ctx).Error().Msg("cannot save explosion event")
 	}
 
-	err = r.gameDao.Update(game)
+	err = r.stateMachine.Save(ctx, game, model.Start, &userId)
 	if err != nil {
-		log.Warn().Str("game_id", request.GameId).Msg("cannot update game")
+		tracing.Log(ctx).Warn().Str("game_id", request.GameId).Msg("cannot update game")
 		return response, err
 	}
+	r.gameFinished(ctx, &game)
 
-	err = r.eventHandler.Explosion(dto.ExplosionEvent{
-		GameId: utils.MaskId(request.GameId),
-		UserId: utils.MaskId(otherSide.Hex()),
+	metrics.GameAction(string(model.ExplodeAction))
+	err = r.eventHandler.Explosion(ctx, dto.ExplosionEvent{
+		GameId: utils.EncodeId(request.GameId),
+		UserId: utils.EncodeId(otherSide.Hex()),
 		Index:  request.Index,
 	})
 
 	if game.Status == model.Finished && game.WinnerUser.IsZero() == false {
-		err := r.eventHandler.EndGame(dto.EndGameEvent{
-			GameId:       utils.MaskId(request.GameId),
-			WinnerUserId: utils.MaskId(game.WinnerUser.Hex()),
+		err := r.eventHandler.EndGame(ctx, dto.EndGameEvent{
+			GameId:       utils.EncodeId(request.GameId),
+			WinnerUserId: utils.EncodeId(game.WinnerUser.Hex()),
 		})
 		if err != nil {
-			log.Error().Str("game_id", request.GameId).Msg("cannot send end game event")
+			tracing.Log(ctx).Error().Str("game_id", request.GameId).Msg("cannot send end game event")
 		}
 	}
 
 	if err != nil {
-		log.Error().Str("game_id", request.GameId).Str("user_id", request.UserId).
+		tracing.Log(ctx).Error().Str("game_id", request.GameId).Str("user_id", request.UserId).
 			Msg("cannot send explosion event")
 	}
 
@@ -559,7 +1250,9 @@ func (r GameServiceImpl) Explode(request dto.ExplodeRequest) (response dto.Explo
 	return response, nil
 }
 
-func (r GameServiceImpl) PersistExplosionEvent(gameId primitive.ObjectID, userId primitive.ObjectID, index int, empty bool) error {
+func (r GameServiceImpl) PersistExplosionEvent(ctx context.Context, gameId primitive.ObjectID, userId primitive.ObjectID, index int, empty bool) error {
+	ctx, span := tracing.Start(ctx, "GameService.PersistExplosionEvent")
+	defer span.End()
 	var event model.GameEvent
 	if empty {
 		event = model.GameEvent{
@@ -578,16 +1271,18 @@ func (r GameServiceImpl) PersistExplosionEvent(gameId primitive.ObjectID, userId
 			Explosion: &index,
 		}
 	}
-	_, err := r.gameEventDao.Insert(event)
+	_, err := r.gameEventDao.Insert(ctx, event)
 	if err != nil {
-		log.Error().Str("game_id", gameId.Hex()).Str("user_id", userId.Hex()).
+		tracing.Log(ctx).Error().Str("game_id", gameId.Hex()).Str("user_id", userId.Hex()).
 			Msg("cannot save explosion event")
 		return err
 	}
 	return nil
 }
 
-func (r GameServiceImpl) SocketConnect(event dto.Event, socketConn *websocket.Conn) error {
+func (r GameServiceImpl) SocketConnect(ctx context.Context, event dto.Event, socketConn *websocket.Conn) error {
+	ctx, span := tracing.Start(ctx, "GameService.SocketConnect")
+	defer span.End()
 	request := new(dto.UserConnectEvent)
 	err := json.Unmarshal([]byte(event.Payload), request)
 	if err != nil {
@@ -598,67 +1293,67 @@ func (r GameServiceImpl) SocketConnect(event dto.Event, socketConn *websocket.Co
 		return err
 	}
 
-	game, err := r.gameDao.GetOne(request.GameId)
+	game, err := r.gameDao.GetOne(ctx, request.GameId)
 	if err != nil {
 		return err
 	}
 
-	if _, ok := cache.GameCache.Cache[request.GameId]; ok == false {
-		cache.GameCache.Cache[request.GameId] = cache.GameData{}
-	}
-
-	gameData := cache.GameCache.Cache[request.GameId]
-
 	if game.Side1User != nil && request.UserId == game.Side1User.Hex() {
-		gameData.Side1Socket = socketConn
-		gameData.Side1UserId = request.UserId
+		cache.SetGameSocket(request.GameId, 1, request.UserId, socketConn)
 		if game.Status == model.Joined {
-			err := r.eventHandler.GameConnect(dto.GameConnect{
-				GameId: utils.MaskId(game.Id.Hex()),
-				UserId: utils.MaskId(game.Side2User.Hex()),
+			err := r.eventHandler.GameConnect(ctx, dto.GameConnect{
+				GameId: utils.EncodeId(game.Id.Hex()),
+				UserId: utils.EncodeId(game.Side2User.Hex()),
 			})
 			if err != nil {
-				log.Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
+				tracing.Log(ctx).Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
 					Msg("cannot send user connect event")
 			}
 		}
 	} else if game.Side2User != nil && request.UserId == game.Side2User.Hex() {
-		gameData.Side2Socket = socketConn
-		gameData.Side2UserId = request.UserId
+		cache.SetGameSocket(request.GameId, 2, request.UserId, socketConn)
 		if game.Status == model.Joined {
-			err := r.eventHandler.GameConnect(dto.GameConnect{
-				GameId: utils.MaskId(game.Id.Hex()),
-				UserId: utils.MaskId(game.Side1User.Hex()),
+			err := r.eventHandler.GameConnect(ctx, dto.GameConnect{
+				GameId: utils.EncodeId(game.Id.Hex()),
+				UserId: utils.EncodeId(game.Side1User.Hex()),
 			})
 			if err != nil {
-				log.Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
+				tracing.Log(ctx).Error().Str("user_id", request.UserId).Str("game_id", request.GameId).
 					Msg("cannot send user connect event")
 			}
 		}
 	} else {
-		log.Error().Str("user_id", request.UserId).Str("game_id", request.GameId).Msg("user does not belong to game")
+		tracing.Log(ctx).Error().Str("user_id", request.UserId).Str("game_id", request.GameId).Msg("user does not belong to game")
 		return dto.BadRequest1("user does not belong to game")
 	}
-	cache.GameCache.Cache[game.Id.Hex()] = gameData
-	log.Debug().Str("game_id", request.GameId).Str("user_id", request.UserId).Msg("create socket successfully")
+	if game.Status != model.Finished && game.Status != model.Cancelled {
+		cache.MarkConnected(request.GameId, request.UserId)
+	}
+	if game.Side2User != nil {
+		userId, _ := primitive.ObjectIDFromHex(request.UserId)
+		if err := r.chatService.SendHistory(ctx, game, userId); err != nil {
+			tracing.Log(ctx).Error().Err(err).Str("game_id", request.GameId).Msg("cannot send chat history")
+		}
+	}
+	tracing.Log(ctx).Debug().Str("game_id", request.GameId).Str("user_id", request.UserId).Msg("create socket successfully")
 	return nil
 }
 
-func (r GameServiceImpl) getGameCheckItWithUserAndChangeTurn(request dto.UserGame) (game model.Game, userId primitive.ObjectID, otherSide primitive.ObjectID, err error) {
-	game, err = r.gameDao.GetOne(request.GetGameId())
+func (r GameServiceImpl) getGameCheckItWithUserAndChangeTurn(ctx context.Context, request dto.UserGame, action model.GameAction) (game model.Game, userId primitive.ObjectID, otherSide primitive.ObjectID, err error) {
+	game, err = r.gameDao.GetOne(ctx, request.GetGameId())
 	if err != nil {
-		log.Error().Str("game_id", request.GetGameId()).Str("user_id", request.GetUserId()).
+		tracing.Log(ctx).Error().Str("game_id", request.GetGameId()).Str("user_id", request.GetUserId()).
 			Msg("cannot find game")
 		return game, userId, otherSide, err
 	}
 
-	if game.Status != model.Start {
-		log.Error().Str("game_id", request.GetGameId()).Msg("game is not in start status")
-		return game, userId, otherSide, dto.BadRequest2("game is not started", error_codes.InvalidGameStatus)
+	err = r.stateMachine.CheckAction(ctx, game, action)
+	if err != nil {
+		return game, userId, otherSide, err
 	}
 
 	if game.LastMoveTime.Add(1 * time.Minute).Before(time.Now()) {
-		log.Error().Str("game_id", request.GetGameId()).Msg("game is already finished")
+		tracing.Log(ctx).Error().Str("game_id", request.GetGameId()).Msg("game is already finished")
 		return game, userId, otherSide, dto.BadRequest2("game is finished", error_codes.GameIsFinished)
 	}
 
@@ -680,10 +1375,55 @@ func (r GameServiceImpl) getGameCheckItWithUserAndChangeTurn(request dto.UserGam
 			return game, userId, otherSide, error_codes.NotUserTurn
 		}
 	} else {
-		log.Error().Str("game_id", request.GetGameId()).Str("user_id", request.GetUserId()).
+		tracing.Log(ctx).Error().Str("game_id", request.GetGameId()).Str("user_id", request.GetUserId()).
 			Msg("user does not belong to this game")
 		return game, userId, otherSide, dto.Forbidden1("user does not belong to this game")
 	}
 	game.LastMoveTime = time.Now()
+	game.MoveCount++
+	game.ResetMissedTurns(userId)
 	return game, userId, otherSide, nil
 }
+
+// gameFinished runs the post game processing of a persisted finished game.
+func (r GameServiceImpl) gameFinished(ctx context.Context, game *model.Game) {
+	if game.Status != model.Finished {
+		return
+	}
+	cache.RemovePresence(game.Id.Hex(), game.Side1User.Hex(), game.Side2User.Hex())
+	r.webhookService.GameEvent(ctx, model.GameFinishedWebhook, *game)
+	metrics.GameFinished(string(game.EndReason))
+	if err := r.leaderboardService.GameFinished(ctx, *game); err != nil {
+		tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Err(err).Msg("cannot update leaderboards")
+	}
+	if err := r.statisticsService.GameFinished(ctx, *game); err != nil {
+		tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Err(err).Msg("cannot update statistics")
+	}
+	err := r.ratingService.GameFinished(ctx, game)
+	if err != nil {
+		tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Err(err).Msg("cannot update ratings")
+		return
+	}
+	if game.RatingApplied {
+		err = r.stateMachine.Save(ctx, *game, model.Finished, nil)
+		if err != nil {
+			tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Err(err).Msg("cannot mark game rating as applied")
+		}
+	}
+}
+
+// checkAcceptingGames rejects starting new games while the server shuts down or is in maintenance, running games go
+// on.
+func checkAcceptingGames() error {
+	if cache.IsDraining() {
+		return dto.ServiceUnavailable2("server is restarting, try again later", error_codes.ServerDraining)
+	}
+	if cache.IsMaintenance() {
+		message := cache.MaintenanceMessage()
+		if message == "" {
+			message = "server is in maintenance, try again later"
+		}
+		return dto.ServiceUnavailable2(message, error_codes.ServerMaintenance)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/error_codes"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// GameStateMachine is the only way a game status changes. It checks actions and transitions against the
+// declarations in model and records every transition as a GameEvent.
+type GameStateMachine interface {
+	CheckAction(ctx context.Context, game model.Game, action model.GameAction) error
+	Created(ctx context.Context, game model.Game, userId *primitive.ObjectID)
+	Save(ctx context.Context, game model.Game, from model.GameStatus, userId *primitive.ObjectID) error
+}
+
+type GameStateMachineImpl struct {
+	gameDao      dao.GameDao
+	gameEventDao dao.GameEventDao
+}
+
+func NewGameStateMachineImpl(gameDao dao.GameDao, gameEventDao dao.GameEventDao) GameStateMachineImpl {
+	return GameStateMachineImpl{
+		gameDao:      gameDao,
+		gameEventDao: gameEventDao,
+	}
+}
+
+func (r GameStateMachineImpl) CheckAction(ctx context.Context, game model.Game, action model.GameAction) error {
+	if !model.IsActionAllowed(game.Status, action) {
+		tracing.Log(ctx).Info().Str("game_id", game.Id.Hex()).Str("status", string(game.Status)).Str("action", string(action)).
+			Msg("action is not allowed in game status")
+		return dto.BadRequest2(fmt.Sprintf("%s is not allowed when game is %s", action, game.Status),
+			error_codes.InvalidGameStatus)
+	}
+	return nil
+}
+
+// Created records the initial status of a newly inserted game.
+func (r GameStateMachineImpl) Created(ctx context.Context, game model.Game, userId *primitive.ObjectID) {
+	r.record(ctx, game.Id, "", game.Status, userId)
+}
+
+// Save persists a game that was loaded in from status. The game is saved only if its stored status is still from,
+// so concurrent requests cannot both change it. A changed status must be an allowed transition.
+func (r GameStateMachineImpl) Save(ctx context.Context, game model.Game, from model.GameStatus, userId *primitive.ObjectID) error {
+	if game.Status != from && !model.CanTransition(from, game.Status) {
+		tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Str("from", string(from)).Str("to", string(game.Status)).
+			Msg("game status transition is not allowed")
+		return dto.BadRequest2(fmt.Sprintf("game cannot change from %s to %s", from, game.Status),
+			error_codes.InvalidGameStatus)
+	}
+
+	updated, err := r.gameDao.UpdateIfStatus(ctx, game, from)
+	if err != nil {
+		tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Err(err).Msg("cannot update game")
+		return err
+	}
+	if !updated {
+		tracing.Log(ctx).Info().Str("game_id", game.Id.Hex()).Str("from", string(from)).Msg("game status is changed by another request")
+		return dto.BadRequest2("game status is changed, try again", error_codes.InvalidGameStatus)
+	}
+
+	if game.Status != from {
+		r.record(ctx, game.Id, from, game.Status, userId)
+	}
+	return nil
+}
+
+func (r GameStateMachineImpl) record(ctx context.Context, gameId primitive.ObjectID, from model.GameStatus, to model.GameStatus,
+	userId *primitive.ObjectID) {
+	_, err := r.gameEventDao.Insert(ctx, model.GameEvent{
+		Type:       model.StatusChanged,
+		Time:       time.Now(),
+		GameId:     gameId,
+		UserId:     userId,
+		FromStatus: from,
+		ToStatus:   to,
+	})
+	if err != nil {
+		tracing.Log(ctx).Error().Str("game_id", gameId.Hex()).Str("to", string(to)).Err(err).Msg("cannot save status changed event")
+	}
+}
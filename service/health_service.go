@@ -0,0 +1,125 @@
+package service
+
+import (
+	"battleship/cache"
+	"battleship/config"
+	"battleship/db/mongodb"
+	"battleship/dto"
+	"battleship/scheduler"
+	"battleship/tracing"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type HealthService interface {
+	Live(ctx context.Context) dto.HealthResponse
+	Ready(ctx context.Context) dto.HealthResponse
+}
+
+type HealthServiceImpl struct {
+}
+
+func NewHealthServiceImpl() HealthServiceImpl {
+	return HealthServiceImpl{}
+}
+
+// Live only tells the process is serving http, it does not check dependencies so a db outage does not restart pods.
+func (r HealthServiceImpl) Live(ctx context.Context) dto.HealthResponse {
+	return dto.HealthResponse{Status: dto.HealthOk, Time: time.Now()}
+}
+
+// Ready checks whether this server can take traffic, it fails if any check fails.
+func (r HealthServiceImpl) Ready(ctx context.Context) dto.HealthResponse {
+	response := dto.HealthResponse{Status: dto.HealthOk, Time: time.Now()}
+	checks := []func(ctx context.Context) dto.HealthCheck{
+		r.checkMode,
+		r.checkDb,
+		r.checkRegistry,
+		r.checkJobs,
+	}
+	for _, check := range checks {
+		c := check(ctx)
+		if c.Status == dto.HealthFail {
+			response.Status = dto.HealthFail
+			tracing.Log(ctx).Warn().Str("check", c.Name).Str("message", c.Message).Msg("readiness check failed")
+		}
+		response.Checks = append(response.Checks, c)
+	}
+	return response
+}
+
+func (r HealthServiceImpl) checkMode(ctx context.Context) dto.HealthCheck {
+	check := dto.HealthCheck{Name: "mode", Status: dto.HealthOk}
+	switch {
+	case cache.IsDraining():
+		check.Status = dto.HealthFail
+		check.Message = "server is draining"
+	case cache.IsMaintenance():
+		check.Status = dto.HealthWarn
+		check.Message = "server is in maintenance mode"
+	}
+	return check
+}
+
+func (r HealthServiceImpl) checkDb(ctx context.Context) dto.HealthCheck {
+	check := dto.HealthCheck{Name: "mongodb", Status: dto.HealthOk}
+	start := time.Now()
+	if mongodb.DB.Client == nil {
+		check.Status = dto.HealthFail
+		check.Message = "mongodb client is not created"
+		return check
+	}
+	err := mongodb.DB.Ping(ctx, time.Duration(config.C.Health.DbTimeoutMs)*time.Millisecond)
+	check.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		check.Status = dto.HealthFail
+		check.Message = err.Error()
+	}
+	return check
+}
+
+func (r HealthServiceImpl) checkRegistry(ctx context.Context) dto.HealthCheck {
+	check := dto.HealthCheck{Name: "connection_registry", Status: dto.HealthOk}
+	start := time.Now()
+	games, sockets, err := cache.GameRegistryStats(time.Duration(config.C.Health.RegistryTimeoutMs) * time.Millisecond)
+	check.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		check.Status = dto.HealthFail
+		check.Message = err.Error()
+		return check
+	}
+	check.Data = map[string]string{
+		"games":   strconv.Itoa(games),
+		"sockets": strconv.Itoa(sockets),
+	}
+	return check
+}
+
+// checkJobs fails if a job goroutine has missed its beats, i.e. it is gone or a run is stuck.
+func (r HealthServiceImpl) checkJobs(ctx context.Context) dto.HealthCheck {
+	check := dto.HealthCheck{Name: "scheduler", Status: dto.HealthOk, Data: map[string]string{}}
+	now := time.Now()
+	var failed []string
+	for _, job := range scheduler.Jobs() {
+		state := "ok"
+		switch {
+		case job.Stopped:
+			state = "stopped"
+		case now.Sub(job.LastBeat) > time.Duration(config.C.Health.JobMissedBeats)*job.Interval:
+			state = "missed beats since " + job.LastBeat.Format(time.RFC3339)
+		}
+		check.Data[job.Name] = state
+		if state != "ok" {
+			failed = append(failed, job.Name)
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		check.Status = dto.HealthFail
+		check.Message = "jobs are not running: " + strings.Join(failed, ", ")
+	}
+	return check
+}
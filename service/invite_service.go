@@ -0,0 +1,178 @@
+package service
+
+import (
+	"battleship/config"
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/error_codes"
+	"battleship/model"
+	"battleship/tracing"
+	"battleship/utils"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/pbkdf2"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	inviteInsertRetries = 3
+	passcodeIterations  = 100000
+)
+
+type InviteService interface {
+	CreateInvite(ctx context.Context, request dto.CreateInviteRequest) (response dto.InviteResponse, err error)
+	RevokeInvite(ctx context.Context, request dto.RevokeInviteRequest) (response dto.RevokeInviteResponse, err error)
+	NewInvite(ctx context.Context, game model.Game) (invite model.Invite, err error)
+	Resolve(ctx context.Context, code string) (invite model.Invite, err error)
+}
+
+type InviteServiceImpl struct {
+	inviteDao    dao.InviteDao
+	gameDao      dao.GameDao
+	stateMachine GameStateMachine
+}
+
+func NewInviteServiceImpl(inviteDao dao.InviteDao, gameDao dao.GameDao, stateMachine GameStateMachine) InviteServiceImpl {
+	return InviteServiceImpl{
+		inviteDao:    inviteDao,
+		gameDao:      gameDao,
+		stateMachine: stateMachine,
+	}
+}
+
+// CreateInvite creates a new invite code for a game that is waiting for the second side, e.g. after the previous
+// code is revoked or expired. Only the creator of the game can invite.
+func (r InviteServiceImpl) CreateInvite(ctx context.Context, request dto.CreateInviteRequest) (response dto.InviteResponse, err error) {
+	response = dto.InviteResponse{}
+	game, err := r.gameDao.GetOne(ctx, request.GameId)
+	if err != nil {
+		tracing.Log(ctx).Info().Str("gameId", request.GameId).Err(err).Msg("cannot get game")
+		return response, err
+	}
+	if game.Side1User == nil || game.Side1User.Hex() != request.UserId {
+		tracing.Log(ctx).Warn().Str("user_id", request.UserId).Str("game_id", request.GameId).Msg("only creator can invite")
+		return response, dto.Forbidden1("only creator of the game can invite")
+	}
+	err = r.stateMachine.CheckAction(ctx, game, model.InviteAction)
+	if err != nil {
+		return response, err
+	}
+
+	invite, err := r.NewInvite(ctx, game)
+	if err != nil {
+		return response, err
+	}
+	response.Ok = true
+	response.Invite = inviteDto(invite)
+	return response, nil
+}
+
+func (r InviteServiceImpl) RevokeInvite(ctx context.Context, request dto.RevokeInviteRequest) (response dto.RevokeInviteResponse, err error) {
+	response = dto.RevokeInviteResponse{}
+	creatorId, err := primitive.ObjectIDFromHex(request.UserId)
+	if err != nil {
+		return response, dto.ParseError(err)
+	}
+	revoked, err := r.inviteDao.Revoke(ctx, request.Code, creatorId)
+	if err != nil {
+		return response, err
+	}
+	if !revoked {
+		return response, dto.NotFoundError2("invite code is not found", error_codes.InviteInvalid)
+	}
+	response.Ok = true
+	return response, nil
+}
+
+// NewInvite saves a new random code for the game, a code that is already taken is generated again.
+func (r InviteServiceImpl) NewInvite(ctx context.Context, game model.Game) (invite model.Invite, err error) {
+	now := time.Now()
+	for i := 0; i < inviteInsertRetries; i++ {
+		var code string
+		code, err = generateInviteCode()
+		if err != nil {
+			tracing.Log(ctx).Error().Err(err).Msg("cannot generate invite code")
+			return invite, err
+		}
+		invite = model.Invite{
+			Code:       code,
+			GameId:     game.Id,
+			CreatorId:  *game.Side1User,
+			CreateDate: now,
+			ExpireDate: now.Add(time.Duration(config.C.Invite.ExpireSec) * time.Second),
+		}
+		if _, err = r.inviteDao.Insert(ctx, invite); err == nil {
+			return invite, nil
+		}
+	}
+	tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Msg("cannot save invite code")
+	return invite, err
+}
+
+// Resolve returns the invite of a code if it is still usable.
+func (r InviteServiceImpl) Resolve(ctx context.Context, code string) (invite model.Invite, err error) {
+	invite, err = r.inviteDao.GetByCode(ctx, code)
+	if err != nil {
+		if isNotFound(err) {
+			return invite, dto.NotFoundError2("invite code is not found", error_codes.InviteInvalid)
+		}
+		return invite, err
+	}
+	if invite.Revoked {
+		return invite, dto.BadRequest2("invite code is revoked", error_codes.InviteInvalid)
+	}
+	if invite.IsExpired() {
+		return invite, dto.BadRequest2("invite code is expired", error_codes.InviteExpired)
+	}
+	return invite, nil
+}
+
+func inviteDto(invite model.Invite) *dto.InviteDto {
+	return &dto.InviteDto{
+		Code:       invite.Code,
+		GameId:     utils.EncodeId(invite.GameId.Hex()),
+		ExpireDate: invite.ExpireDate,
+	}
+}
+
+func generateInviteCode() (string, error) {
+	var b strings.Builder
+	alphabetLength := big.NewInt(int64(len(dto.InviteCodeAlphabet)))
+	for i := 0; i < dto.InviteCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, alphabetLength)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(dto.InviteCodeAlphabet[n.Int64()])
+	}
+	return dto.FormatInviteCode(b.String()), nil
+}
+
+// hashPasscode returns a salted hash in the form salt:hash.
+func hashPasscode(passcode string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	saltHex := hex.EncodeToString(salt)
+	return saltHex + ":" + passcodeSum(saltHex, passcode), nil
+}
+
+func checkPasscode(passcodeHash string, passcode string) bool {
+	parts := strings.SplitN(passcodeHash, ":", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(parts[1]), []byte(passcodeSum(parts[0], passcode))) == 1
+}
+
+// passcodeSum stretches the passcode with pbkdf2, a short passcode is slow to find from a leaked hash.
+func passcodeSum(salt string, passcode string) string {
+	return hex.EncodeToString(pbkdf2.Key([]byte(passcode), []byte(salt), passcodeIterations, sha256.Size, sha256.New))
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"battleship/config"
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/model"
+	"battleship/tracing"
+	"battleship/utils"
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type LeaderboardService interface {
+	GameFinished(ctx context.Context, game model.Game) error
+	GetLeaderboard(ctx context.Context, request dto.GetLeaderboardRequest) (response dto.GetLeaderboardResponse, err error)
+	GetSeasons(ctx context.Context) (response dto.GetSeasonsResponse, err error)
+	ArchiveEndedSeasons(ctx context.Context)
+}
+
+type LeaderboardServiceImpl struct {
+	leaderboardDao   dao.LeaderboardDao
+	seasonArchiveDao dao.SeasonArchiveDao
+	userDao          dao.UserDao
+}
+
+func NewLeaderboardServiceImpl(leaderboardDao dao.LeaderboardDao, seasonArchiveDao dao.SeasonArchiveDao,
+	userDao dao.UserDao) LeaderboardServiceImpl {
+	return LeaderboardServiceImpl{
+		leaderboardDao:   leaderboardDao,
+		seasonArchiveDao: seasonArchiveDao,
+		userDao:          userDao,
+	}
+}
+
+// GameFinished adds the result of a finished game to every board the game end date belongs to.
+func (r LeaderboardServiceImpl) GameFinished(ctx context.Context, game model.Game) error {
+	if game.Status != model.Finished || game.LoserUser() == nil {
+		return nil
+	}
+	date := time.Now()
+	if game.EndDate != nil {
+		date = *game.EndDate
+	}
+
+	boards := map[model.LeaderboardType]string{
+		model.AllTimeBoard: model.AllTimePeriod,
+		model.WeeklyBoard:  model.WeekPeriod(date),
+	}
+	if season, ok := config.SeasonAt(date); ok {
+		boards[model.SeasonBoard] = season.Id
+	}
+
+	for board, period := range boards {
+		if err := r.leaderboardDao.AddResult(ctx, board, period, *game.WinnerUser, true, date); err != nil {
+			return err
+		}
+		if err := r.leaderboardDao.AddResult(ctx, board, period, *game.LoserUser(), false, date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r LeaderboardServiceImpl) GetLeaderboard(ctx context.Context, request dto.GetLeaderboardRequest) (response dto.GetLeaderboardResponse, err error) {
+	response = dto.GetLeaderboardResponse{Entries: []dto.LeaderboardEntryDto{}}
+
+	period, err := boardPeriod(request.Board, request.SeasonId)
+	if err != nil {
+		return response, err
+	}
+
+	skip := int64((request.Page - 1) * request.PageSize)
+	entries, err := r.leaderboardDao.FindPage(ctx, request.Board, period, skip, int64(request.PageSize))
+	if err != nil {
+		return response, err
+	}
+	total, err := r.leaderboardDao.Count(ctx, request.Board, period)
+	if err != nil {
+		return response, err
+	}
+
+	var myEntry *model.LeaderboardEntry
+	if request.UserId != "" {
+		entry, err := r.leaderboardDao.GetEntry(ctx, request.Board, period, request.UserId)
+		if err == nil {
+			myEntry = &entry
+		} else if !isNotFound(err) {
+			return response, err
+		}
+	}
+
+	var rank int64
+	if len(entries) > 0 {
+		ahead, err := r.leaderboardDao.CountAhead(ctx, entries[0])
+		if err != nil {
+			return response, err
+		}
+		rank = ahead + 1
+	}
+
+	toDto, err := r.entryMapper(ctx, entries, myEntry)
+	if err != nil {
+		return response, err
+	}
+	for i, e := range entries {
+		if i > 0 && (e.Wins != entries[i-1].Wins || e.Losses != entries[i-1].Losses) {
+			rank = skip + int64(i) + 1
+		}
+		response.Entries = append(response.Entries, toDto(e, rank))
+	}
+
+	if myEntry != nil {
+		ahead, err := r.leaderboardDao.CountAhead(ctx, *myEntry)
+		if err != nil {
+			return response, err
+		}
+		my := toDto(*myEntry, ahead+1)
+		response.MyPosition = &my
+	}
+
+	response.Ok = true
+	response.Board = request.Board
+	response.Period = period
+	response.Page = request.Page
+	response.PageSize = request.PageSize
+	response.Total = total
+	return response, nil
+}
+
+func (r LeaderboardServiceImpl) GetSeasons(ctx context.Context) (response dto.GetSeasonsResponse, err error) {
+	response = dto.GetSeasonsResponse{Seasons: []dto.SeasonDto{}}
+	archives, err := r.seasonArchiveDao.FindAll(ctx)
+	if err != nil {
+		return response, err
+	}
+	archiveBySeason := make(map[string]model.SeasonArchive, len(archives))
+	for _, a := range archives {
+		archiveBySeason[a.SeasonId] = a
+	}
+
+	now := time.Now()
+	for _, season := range config.C.Leaderboard.Seasons {
+		start, end := season.Period()
+		seasonDto := dto.SeasonDto{
+			Id:     season.Id,
+			Name:   season.Name,
+			Start:  start,
+			End:    end,
+			Active: !now.Before(start) && now.Before(end),
+		}
+		if archive, ok := archiveBySeason[season.Id]; ok {
+			seasonDto.Archived = true
+			toDto, err := r.entryMapper(ctx, archive.Top, nil)
+			if err != nil {
+				return response, err
+			}
+			for i, e := range archive.Top {
+				seasonDto.Winners = append(seasonDto.Winners, toDto(e, int64(i+1)))
+			}
+		}
+		response.Seasons = append(response.Seasons, seasonDto)
+	}
+	response.Ok = true
+	return response, nil
+}
+
+// ArchiveEndedSeasons stores the top of every ended season that is not archived yet.
+func (r LeaderboardServiceImpl) ArchiveEndedSeasons(ctx context.Context) {
+	archives, err := r.seasonArchiveDao.FindAll(ctx)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Msg("cannot get season archives")
+		return
+	}
+	archived := make(map[string]bool, len(archives))
+	for _, a := range archives {
+		archived[a.SeasonId] = true
+	}
+
+	for _, season := range config.C.Leaderboard.Seasons {
+		start, end := season.Period()
+		if archived[season.Id] || end.IsZero() || end.After(time.Now()) {
+			continue
+		}
+		top, err := r.leaderboardDao.FindPage(ctx, model.SeasonBoard, season.Id, 0, int64(config.C.Leaderboard.ArchiveTop))
+		if err != nil {
+			tracing.Log(ctx).Error().Err(err).Str("season", season.Id).Msg("cannot get season top")
+			continue
+		}
+		_, err = r.seasonArchiveDao.Insert(ctx, model.SeasonArchive{
+			SeasonId:    season.Id,
+			Name:        season.Name,
+			Start:       start,
+			End:         end,
+			Top:         top,
+			ArchiveDate: time.Now(),
+		})
+		if err != nil {
+			tracing.Log(ctx).Error().Err(err).Str("season", season.Id).Msg("cannot archive season")
+			continue
+		}
+		tracing.Log(ctx).Info().Str("season", season.Id).Int("top", len(top)).Msg("season is archived")
+	}
+}
+
+// entryMapper loads users of entries (and extra) at once and returns a function that converts an entry to dto.
+func (r LeaderboardServiceImpl) entryMapper(ctx context.Context, entries []model.LeaderboardEntry, extra *model.LeaderboardEntry) (
+	func(entry model.LeaderboardEntry, rank int64) dto.LeaderboardEntryDto, error) {
+	ids := make([]primitive.ObjectID, 0, len(entries)+1)
+	for _, e := range entries {
+		ids = append(ids, e.UserId)
+	}
+	if extra != nil {
+		ids = append(ids, extra.UserId)
+	}
+	users := map[primitive.ObjectID]model.User{}
+	if len(ids) > 0 {
+		found, err := r.userDao.FindByIds(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range found {
+			users[u.Id] = u
+		}
+	}
+
+	return func(entry model.LeaderboardEntry, rank int64) dto.LeaderboardEntryDto {
+		user := users[entry.UserId]
+		return dto.LeaderboardEntryDto{
+			Rank:   rank,
+			UserId: utils.EncodeId(entry.UserId.Hex()),
+			Name:   user.Name,
+			Rating: user.GetRating(config.C.Rating.Initial),
+			Wins:   entry.Wins,
+			Losses: entry.Losses,
+		}
+	}, nil
+}
+
+func boardPeriod(board model.LeaderboardType, seasonId string) (string, error) {
+	switch board {
+	case model.WeeklyBoard:
+		return model.WeekPeriod(time.Now()), nil
+	case model.SeasonBoard:
+		if seasonId == "" {
+			season, ok := config.SeasonAt(time.Now())
+			if !ok {
+				return "", dto.NotFoundError1("there is no active season")
+			}
+			return season.Id, nil
+		}
+		if _, ok := config.FindSeason(seasonId); !ok {
+			return "", dto.NotFoundError1("season is not found")
+		}
+		return seasonId, nil
+	default:
+		return model.AllTimePeriod, nil
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"battleship/config"
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/events/outgoing_events"
+	"battleship/model"
+	"battleship/tracing"
+	"battleship/utils"
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type LobbyService interface {
+	GetLobby(ctx context.Context, request dto.GetLobbyRequest) (response dto.GetLobbyResponse, err error)
+	GameAdded(ctx context.Context, game model.Game)
+	GameRemoved(ctx context.Context, game model.Game)
+}
+
+type LobbyServiceImpl struct {
+	gameDao      dao.GameDao
+	userDao      dao.UserDao
+	blockService BlockService
+	eventHandler outgoing_events.OutgoingEventHandler
+}
+
+func NewLobbyServiceImpl(gameDao dao.GameDao, userDao dao.UserDao, blockService BlockService,
+	eventHandler outgoing_events.OutgoingEventHandler) LobbyServiceImpl {
+	return LobbyServiceImpl{
+		gameDao:      gameDao,
+		userDao:      userDao,
+		blockService: blockService,
+		eventHandler: eventHandler,
+	}
+}
+
+func (r LobbyServiceImpl) GetLobby(ctx context.Context, request dto.GetLobbyRequest) (response dto.GetLobbyResponse, err error) {
+	response = dto.GetLobbyResponse{Games: []dto.LobbyGameDto{}}
+	games, err := r.gameDao.FindOpen(ctx, int64(request.Limit))
+	if err != nil {
+		return response, err
+	}
+	blockedWith := map[string]bool{}
+	if request.UserId != "" {
+		blockedWith, err = r.blockService.BlockedWith(ctx, request.UserId)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	var creatorIds []primitive.ObjectID
+	for _, g := range games {
+		if g.Side1User != nil {
+			creatorIds = append(creatorIds, *g.Side1User)
+		}
+	}
+	creators := map[primitive.ObjectID]model.User{}
+	if len(creatorIds) > 0 {
+		users, err := r.userDao.FindByIds(ctx, creatorIds)
+		if err != nil {
+			return response, err
+		}
+		for _, u := range users {
+			creators[u.Id] = u
+		}
+	}
+
+	for _, g := range games {
+		if g.Side1User == nil || blockedWith[g.Side1User.Hex()] {
+			continue
+		}
+		response.Games = append(response.Games, lobbyGame(g, creators[*g.Side1User]))
+	}
+	response.Ok = true
+	return response, nil
+}
+
+// GameAdded pushes a newly created public game to lobby sockets.
+func (r LobbyServiceImpl) GameAdded(ctx context.Context, game model.Game) {
+	if !game.Public || game.Side1User == nil {
+		return
+	}
+	creator, err := r.userDao.GetOne(ctx, game.Side1User.Hex())
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("game_id", game.Id.Hex()).Msg("cannot get lobby game creator")
+		return
+	}
+	g := lobbyGame(game, creator)
+	err = r.eventHandler.LobbyUpdate(ctx, dto.LobbyUpdateEvent{
+		Action: dto.LobbyGameAdded,
+		GameId: g.GameId,
+		Game:   &g,
+	})
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("game_id", game.Id.Hex()).Msg("cannot send lobby update")
+	}
+}
+
+// GameRemoved tells lobby sockets that a public game is not open anymore, e.g. it is joined or expired.
+func (r LobbyServiceImpl) GameRemoved(ctx context.Context, game model.Game) {
+	if !game.Public {
+		return
+	}
+	err := r.eventHandler.LobbyUpdate(ctx, dto.LobbyUpdateEvent{
+		Action: dto.LobbyGameRemoved,
+		GameId: utils.EncodeId(game.Id.Hex()),
+	})
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("game_id", game.Id.Hex()).Msg("cannot send lobby update")
+	}
+}
+
+func lobbyGame(game model.Game, creator model.User) dto.LobbyGameDto {
+	return dto.LobbyGameDto{
+		GameId:         utils.EncodeId(game.Id.Hex()),
+		CreatorId:      utils.EncodeId(game.Side1User.Hex()),
+		CreatorName:    creator.Name,
+		CreatorRating:  creator.GetRating(config.C.Rating.Initial),
+		BoardSize:      game.BoardSize,
+		MoveTimeoutSec: game.MoveTimeoutSec,
+		Ruleset:        game.Ruleset,
+		Ranked:         game.Ranked,
+		HasPasscode:    game.PasscodeHash != "",
+		CreateDate:     game.CreateDate,
+		AgeSec:         int64(time.Since(game.CreateDate).Seconds()),
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"battleship/cache"
+	"battleship/config"
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/error_codes"
+	"battleship/events/outgoing_events"
+	"battleship/tracing"
+	"battleship/utils"
+	"context"
+	"time"
+)
+
+type MatchmakingService interface {
+	Enqueue(ctx context.Context, request dto.EnqueueMatchmakingRequest) (response dto.EnqueueMatchmakingResponse, err error)
+	Cancel(ctx context.Context, request dto.CancelMatchmakingRequest) (response dto.CancelMatchmakingResponse, err error)
+	ExpireTickets(ctx context.Context)
+}
+
+type MatchmakingServiceImpl struct {
+	gameService  GameService
+	userDao      dao.UserDao
+	blockService BlockService
+	eventHandler outgoing_events.OutgoingEventHandler
+}
+
+func NewMatchmakingServiceImpl(gameService GameService, userDao dao.UserDao, blockService BlockService,
+	eventHandler outgoing_events.OutgoingEventHandler) MatchmakingServiceImpl {
+	return MatchmakingServiceImpl{
+		gameService:  gameService,
+		userDao:      userDao,
+		blockService: blockService,
+		eventHandler: eventHandler,
+	}
+}
+
+func (r MatchmakingServiceImpl) Enqueue(ctx context.Context, request dto.EnqueueMatchmakingRequest) (response dto.EnqueueMatchmakingResponse, err error) {
+	response = dto.EnqueueMatchmakingResponse{}
+	if err = checkAcceptingGames(); err != nil {
+		return response, err
+	}
+	user, err := r.userDao.GetOne(ctx, request.UserId)
+	if err != nil {
+		tracing.Log(ctx).Info().Str("userId", request.UserId).Err(err).Msg("cannot get user")
+		return response, err
+	}
+	if err = checkNotBanned(user); err != nil {
+		return response, err
+	}
+
+	blockedWith, err := r.blockService.BlockedWith(ctx, request.UserId)
+	if err != nil {
+		return response, err
+	}
+
+	ticket := cache.MatchmakingTicket{
+		UserId:         request.UserId,
+		Settings:       request.Settings(),
+		Rating:         user.GetRating(config.C.Rating.Initial),
+		EnqueueTime:    time.Now(),
+		BlockedWith:    blockedWith,
+		TossCommitment: request.TossCommitment,
+	}
+
+	opponent, matched, err := pairOrEnqueue(ctx, ticket)
+	if err != nil {
+		return response, err
+	}
+
+	if !matched {
+		response.Ok = true
+		response.Status = dto.MatchmakingQueued
+		response.ExpireDate = ticket.EnqueueTime.Add(time.Duration(config.C.Matchmaking.TimeoutSec) * time.Second)
+		return response, nil
+	}
+
+	game, err := r.gameService.CreateMatchedGame(ctx,
+		MatchedSide{UserId: opponent.UserId, TossCommitment: opponent.TossCommitment},
+		MatchedSide{UserId: ticket.UserId, TossCommitment: ticket.TossCommitment}, ticket.Settings)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("user_id", ticket.UserId).Str("opponent_id", opponent.UserId).
+			Msg("cannot create matched game")
+		// the opponent is still waiting for a match, it goes back to its place in the queue
+		requeue(opponent)
+		return response, err
+	}
+
+	for _, userId := range []string{opponent.UserId, ticket.UserId} {
+		gameDto := dto.GameDto{}
+		gameDto.FromGame(game, userId)
+		err := r.eventHandler.MatchFound(ctx, dto.MatchFoundEvent{
+			UserId: utils.EncodeId(userId),
+			Game:   gameDto,
+		})
+		if err != nil {
+			tracing.Log(ctx).Error().Str("game_id", game.Id.Hex()).Str("user_id", userId).Msg("cannot send match found event")
+		}
+	}
+
+	response.Ok = true
+	response.Status = dto.MatchmakingMatched
+	response.Game = new(dto.GameDto)
+	response.Game.FromGame(game, ticket.UserId)
+	return response, nil
+}
+
+func (r MatchmakingServiceImpl) Cancel(ctx context.Context, request dto.CancelMatchmakingRequest) (response dto.CancelMatchmakingResponse, err error) {
+	response = dto.CancelMatchmakingResponse{}
+	cache.MatchmakingQueue.Mux.Lock()
+	defer cache.MatchmakingQueue.Mux.Unlock()
+
+	for i, t := range cache.MatchmakingQueue.Tickets {
+		if t.UserId == request.UserId {
+			cache.MatchmakingQueue.Tickets = append(cache.MatchmakingQueue.Tickets[:i], cache.MatchmakingQueue.Tickets[i+1:]...)
+			response.Ok = true
+			return response, nil
+		}
+	}
+	tracing.Log(ctx).Info().Str("user_id", request.UserId).Msg("user is not in matchmaking queue")
+	return response, dto.NotFoundError1("user is not in matchmaking queue")
+}
+
+func (r MatchmakingServiceImpl) ExpireTickets(ctx context.Context) {
+	now := time.Now()
+	var expired []cache.MatchmakingTicket
+
+	cache.MatchmakingQueue.Mux.Lock()
+	remaining := cache.MatchmakingQueue.Tickets[:0]
+	for _, t := range cache.MatchmakingQueue.Tickets {
+		if ticketExpired(t, now) {
+			expired = append(expired, t)
+		} else {
+			remaining = append(remaining, t)
+		}
+	}
+	cache.MatchmakingQueue.Tickets = remaining
+	cache.MatchmakingQueue.Mux.Unlock()
+
+	for _, t := range expired {
+		tracing.Log(ctx).Debug().Str("user_id", t.UserId).Msg("matchmaking ticket is expired")
+		err := r.eventHandler.MatchmakingTimeout(ctx, dto.MatchmakingTimeoutEvent{
+			UserId: utils.EncodeId(t.UserId),
+		})
+		if err != nil {
+			tracing.Log(ctx).Error().Str("user_id", t.UserId).Msg("cannot send matchmaking timeout event")
+		}
+	}
+}
+
+// pairOrEnqueue removes and returns the oldest compatible ticket, or adds ticket to the queue if none is waiting.
+// Expired tickets are left for ExpireTickets, their users are told about the timeout.
+func pairOrEnqueue(ctx context.Context, ticket cache.MatchmakingTicket) (opponent cache.MatchmakingTicket, matched bool, err error) {
+	cache.MatchmakingQueue.Mux.Lock()
+	defer cache.MatchmakingQueue.Mux.Unlock()
+
+	for _, t := range cache.MatchmakingQueue.Tickets {
+		if t.UserId == ticket.UserId {
+			tracing.Log(ctx).Info().Str("user_id", ticket.UserId).Msg("user is already in matchmaking queue")
+			return opponent, false, dto.Duplicate2("user is already in matchmaking queue", error_codes.AlreadyInMatchmaking)
+		}
+	}
+
+	for i, t := range cache.MatchmakingQueue.Tickets {
+		if !ticketExpired(t, ticket.EnqueueTime) && isCompatible(t, ticket, ticket.EnqueueTime) {
+			cache.MatchmakingQueue.Tickets = append(cache.MatchmakingQueue.Tickets[:i], cache.MatchmakingQueue.Tickets[i+1:]...)
+			return t, true, nil
+		}
+	}
+
+	cache.MatchmakingQueue.Tickets = append(cache.MatchmakingQueue.Tickets, ticket)
+	return opponent, false, nil
+}
+
+// requeue puts back a ticket that was taken by pairOrEnqueue in front of the queue, its EnqueueTime is kept so it
+// expires as before. Nothing is done if the user has enqueued again meanwhile.
+func requeue(ticket cache.MatchmakingTicket) {
+	cache.MatchmakingQueue.Mux.Lock()
+	defer cache.MatchmakingQueue.Mux.Unlock()
+	for _, t := range cache.MatchmakingQueue.Tickets {
+		if t.UserId == ticket.UserId {
+			return
+		}
+	}
+	cache.MatchmakingQueue.Tickets = append([]cache.MatchmakingTicket{ticket}, cache.MatchmakingQueue.Tickets...)
+}
+
+func ticketExpired(ticket cache.MatchmakingTicket, now time.Time) bool {
+	return ticket.EnqueueTime.Add(time.Duration(config.C.Matchmaking.TimeoutSec) * time.Second).Before(now)
+}
+
+// isCompatible checks settings equality, blocks between the users and the rating window of the waiting ticket, which widens the longer it waits.
+func isCompatible(waiting cache.MatchmakingTicket, ticket cache.MatchmakingTicket, now time.Time) bool {
+	if waiting.Settings != ticket.Settings {
+		return false
+	}
+	if waiting.BlockedWith[ticket.UserId] || ticket.BlockedWith[waiting.UserId] {
+		return false
+	}
+	waited := int(now.Sub(waiting.EnqueueTime).Seconds())
+	window := config.C.Matchmaking.RatingWindow + waited*config.C.Matchmaking.RatingWindowGrowthPerSec
+	diff := waiting.Rating - ticket.Rating
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= window
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"battleship/config"
+	"battleship/db/dao"
+	"battleship/model"
+	"battleship/rating"
+	"battleship/tracing"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type RatingService interface {
+	GameFinished(ctx context.Context, game *model.Game) error
+}
+
+type RatingServiceImpl struct {
+	userDao          dao.UserDao
+	ratingHistoryDao dao.RatingHistoryDao
+}
+
+func NewRatingServiceImpl(userDao dao.UserDao, ratingHistoryDao dao.RatingHistoryDao) RatingServiceImpl {
+	return RatingServiceImpl{
+		userDao:          userDao,
+		ratingHistoryDao: ratingHistoryDao,
+	}
+}
+
+// ratingAttempts bounds how many times the rating of a user is read again when another game has changed it meanwhile.
+const ratingAttempts = 3
+
+// GameFinished updates both users rating for a finished ranked game. game.RatingApplied is set, caller persists it.
+// The deltas are from the ratings before the game, a rating is saved only if it has not changed since it was read.
+func (r RatingServiceImpl) GameFinished(ctx context.Context, game *model.Game) error {
+	if !game.Ranked || game.RatingApplied || game.Status != model.Finished || game.LoserUser() == nil {
+		return nil
+	}
+
+	winner, err := r.userDao.GetOne(ctx, game.WinnerUser.Hex())
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("game_id", game.Id.Hex()).Msg("cannot get winner user")
+		return err
+	}
+	loser, err := r.userDao.GetOne(ctx, game.LoserUser().Hex())
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("game_id", game.Id.Hex()).Msg("cannot get loser user")
+		return err
+	}
+	winnerRating := winner.GetRating(config.C.Rating.Initial)
+	loserRating := loser.GetRating(config.C.Rating.Initial)
+
+	winnerDelta, err := r.applyRating(ctx, game, winner, loser.Id, loserRating, rating.Win)
+	if err != nil {
+		return err
+	}
+	loserDelta, err := r.applyRating(ctx, game, loser, winner.Id, winnerRating, rating.Loss)
+	if err != nil {
+		return err
+	}
+
+	game.RatingApplied = true
+	tracing.Log(ctx).Debug().Str("game_id", game.Id.Hex()).Int("winner_delta", winnerDelta).Int("loser_delta", loserDelta).
+		Msg("ratings are updated")
+	return nil
+}
+
+// applyRating saves the rating of user after the game, user is read again when its rating has changed meanwhile.
+func (r RatingServiceImpl) applyRating(ctx context.Context, game *model.Game, user model.User, opponent primitive.ObjectID,
+	opponentRating int, score float64) (delta int, err error) {
+	factor := endReasonFactor(game.EndReason)
+	for attempt := 0; ; attempt++ {
+		oldRating := user.GetRating(config.C.Rating.Initial)
+		delta = rating.Delta(oldRating, opponentRating, score, kFactor(user), factor)
+		updated, err := r.userDao.SetRating(ctx, user.Id, user.RatedGames, oldRating+delta)
+		if err != nil {
+			tracing.Log(ctx).Error().Err(err).Str("user_id", user.Id.Hex()).Msg("cannot update user rating")
+			return 0, err
+		}
+		if updated {
+			r.saveHistory(ctx, game, user.Id, opponent, oldRating, oldRating+delta, score == rating.Win)
+			return delta, nil
+		}
+		if attempt+1 == ratingAttempts {
+			tracing.Log(ctx).Error().Str("user_id", user.Id.Hex()).Msg("user rating keeps changing")
+			return 0, errors.New("user rating keeps changing")
+		}
+		userId := user.Id
+		user, err = r.userDao.GetOne(ctx, userId.Hex())
+		if err != nil {
+			tracing.Log(ctx).Error().Err(err).Str("user_id", userId.Hex()).Msg("cannot get user")
+			return 0, err
+		}
+	}
+}
+
+// saveHistory saves a rating change, the rating itself is already saved so a failure is only logged.
+func (r RatingServiceImpl) saveHistory(ctx context.Context, game *model.Game, userId primitive.ObjectID,
+	opponent primitive.ObjectID, oldRating int, newRating int, won bool) {
+	_, err := r.ratingHistoryDao.Insert(ctx, model.RatingHistory{
+		UserId:         userId,
+		GameId:         game.Id,
+		OpponentUserId: opponent,
+		OldRating:      oldRating,
+		NewRating:      newRating,
+		Won:            won,
+		EndReason:      game.EndReason,
+		Time:           time.Now(),
+	})
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("user_id", userId.Hex()).Msg("cannot save rating history")
+	}
+}
+
+func kFactor(user model.User) int {
+	if user.IsProvisional(config.C.Rating.ProvisionalGames) {
+		return config.C.Rating.ProvisionalKFactor
+	}
+	return config.C.Rating.KFactor
+}
+
+func endReasonFactor(reason model.EndReason) float64 {
+	switch reason {
+	case model.Timeout:
+		return config.C.Rating.TimeoutFactor
+	case model.Abandoned:
+		return config.C.Rating.AbandonFactor
+	default:
+		return 1
+	}
+}
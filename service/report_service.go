@@ -0,0 +1,178 @@
+package service
+
+import (
+	"battleship/config"
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/error_codes"
+	"battleship/model"
+	"battleship/tracing"
+	"context"
+	"fmt"
+	"time"
+)
+
+type ReportService interface {
+	Report(ctx context.Context, request dto.ReportUserRequest) (response dto.ReportUserResponse, err error)
+	GetReports(ctx context.Context, request dto.GetReportsRequest) (response dto.GetReportsResponse, err error)
+	Review(ctx context.Context, request dto.ReviewReportRequest) (response dto.ReviewReportResponse, err error)
+}
+
+type ReportServiceImpl struct {
+	userReportDao dao.UserReportDao
+	gameDao       dao.GameDao
+	chatDao       dao.ChatDao
+	userDao       dao.UserDao
+}
+
+func NewReportServiceImpl(userReportDao dao.UserReportDao, gameDao dao.GameDao, chatDao dao.ChatDao,
+	userDao dao.UserDao) ReportServiceImpl {
+	return ReportServiceImpl{
+		userReportDao: userReportDao,
+		gameDao:       gameDao,
+		chatDao:       chatDao,
+		userDao:       userDao,
+	}
+}
+
+// Report adds a report of a user about its opponent in a game to the moderation queue, a user can report the
+// opponent of a game once.
+func (r ReportServiceImpl) Report(ctx context.Context, request dto.ReportUserRequest) (response dto.ReportUserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReportService.Report")
+	defer tracing.End(span, &err)
+	response = dto.ReportUserResponse{}
+	game, err := r.gameDao.GetOne(ctx, request.GameId)
+	if err != nil {
+		tracing.Log(ctx).Info().Str("gameId", request.GameId).Err(err).Msg("cannot get game")
+		return response, err
+	}
+	if game.Side1User == nil || game.Side2User == nil {
+		return response, dto.BadRequest2("game has no opponent to report", error_codes.ReportInvalid)
+	}
+	reporter, reported := *game.Side1User, *game.Side2User
+	if reporter.Hex() != request.UserId {
+		reporter, reported = reported, reporter
+	}
+	if reporter.Hex() != request.UserId || reported.Hex() != request.ReportedUserId {
+		tracing.Log(ctx).Warn().Str("user_id", request.UserId).Str("game_id", request.GameId).Msg("users do not belong to game")
+		return response, dto.Forbidden2("users do not belong to the game", error_codes.ReportInvalid)
+	}
+
+	chatLog, err := r.chatDao.FindLast(ctx, game.Id, int64(config.C.Report.ChatLogSize))
+	if err != nil {
+		return response, err
+	}
+	id, inserted, err := r.userReportDao.Insert(ctx, model.UserReport{
+		ReporterId:     reporter,
+		ReportedUserId: reported,
+		GameId:         game.Id,
+		Category:       request.Category,
+		Comment:        request.Comment,
+		ChatLog:        chatLog,
+		Status:         model.ReportPending,
+		CreateDate:     time.Now(),
+	})
+	if err != nil {
+		return response, err
+	}
+	if !inserted {
+		return response, dto.Duplicate2("user is already reported in the game", error_codes.ReportDuplicate)
+	}
+	tracing.Log(ctx).Info().Str("report_id", id).Str("game_id", request.GameId).Msg("user is reported")
+
+	response.Ok = true
+	response.ReportId = id
+	return response, nil
+}
+
+func (r ReportServiceImpl) GetReports(ctx context.Context, request dto.GetReportsRequest) (response dto.GetReportsResponse, err error) {
+	response = dto.GetReportsResponse{Reports: []dto.UserReportDto{}}
+	reports, err := r.userReportDao.FindByStatus(ctx, model.ReportStatus(request.Status), int64(request.Limit))
+	if err != nil {
+		return response, err
+	}
+	for _, report := range reports {
+		reportDto := dto.UserReportDto{}
+		reportDto.FromReport(report)
+		response.Reports = append(response.Reports, reportDto)
+	}
+	response.Ok = true
+	return response, nil
+}
+
+// Review confirms or dismisses a pending report. A confirmed report restricts the reported user when it has enough
+// confirmed reports in the configured window.
+func (r ReportServiceImpl) Review(ctx context.Context, request dto.ReviewReportRequest) (response dto.ReviewReportResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ReportService.Review")
+	defer tracing.End(span, &err)
+	response = dto.ReviewReportResponse{}
+	report, err := r.userReportDao.GetOne(ctx, request.ReportId)
+	if err != nil {
+		return response, err
+	}
+	if report.Status != model.ReportPending {
+		return response, dto.BadRequest2("report is already reviewed", error_codes.ReportInvalid)
+	}
+
+	now := time.Now()
+	report.Status = model.ReportDismissed
+	if request.Confirmed {
+		report.Status = model.ReportConfirmed
+	}
+	report.ReviewNote = request.Note
+	report.ReviewedBy = request.Actor
+	report.ReviewDate = &now
+	updated, err := r.userReportDao.UpdateIfPending(ctx, report)
+	if err != nil {
+		return response, err
+	}
+	if !updated {
+		return response, dto.BadRequest2("report is already reviewed", error_codes.ReportInvalid)
+	}
+
+	if report.Status == model.ReportConfirmed {
+		response.Restricted, err = r.restrict(ctx, report, now)
+		if err != nil {
+			return response, err
+		}
+	}
+	response.Ok = true
+	response.Report.FromReport(report)
+	return response, nil
+}
+
+// restrict bans the reported user for the configured hours when it has reached the confirmed report count. Each
+// further confirmed report in the window extends the restriction, a longer ban of the user is kept.
+func (r ReportServiceImpl) restrict(ctx context.Context, report model.UserReport, now time.Time) (restricted bool, err error) {
+	cfg := config.C.Report
+	if cfg.AutoRestrictCount <= 0 {
+		return false, nil
+	}
+	since := now.Add(-time.Duration(cfg.AutoRestrictWindowDays) * 24 * time.Hour)
+	count, err := r.userReportDao.CountConfirmed(ctx, report.ReportedUserId, since)
+	if err != nil {
+		return false, err
+	}
+	if count < int64(cfg.AutoRestrictCount) {
+		return false, nil
+	}
+
+	user, err := r.userDao.GetOne(ctx, report.ReportedUserId.Hex())
+	if err != nil {
+		return false, err
+	}
+	until := now.Add(time.Duration(cfg.AutoRestrictHours) * time.Hour)
+	if user.IsBanned(now) && (user.Ban.Until == nil || user.Ban.Until.After(until)) {
+		return false, nil
+	}
+	err = r.userDao.SetBan(ctx, user.Id, &model.UserBan{
+		Reason: fmt.Sprintf("%d confirmed reports", count),
+		Date:   now,
+		Until:  &until,
+	})
+	if err != nil {
+		return false, err
+	}
+	tracing.Log(ctx).Info().Str("user_id", user.Id.Hex()).Int64("reports", count).Msg("user is restricted by reports")
+	return true, nil
+}
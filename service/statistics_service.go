@@ -0,0 +1,98 @@
+package service
+
+import (
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/model"
+	"battleship/tracing"
+	"battleship/utils"
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type StatisticsService interface {
+	GameFinished(ctx context.Context, game model.Game) error
+	GetUserStats(ctx context.Context, userId string) (response dto.UserStatsResponse, err error)
+}
+
+type StatisticsServiceImpl struct {
+	userStatsDao dao.UserStatsDao
+	gameEventDao dao.GameEventDao
+}
+
+func NewStatisticsServiceImpl(userStatsDao dao.UserStatsDao, gameEventDao dao.GameEventDao) StatisticsServiceImpl {
+	return StatisticsServiceImpl{
+		userStatsDao: userStatsDao,
+		gameEventDao: gameEventDao,
+	}
+}
+
+// GameFinished adds a finished game to the precomputed stats of both sides, a game without a winner is a no result
+// for both.
+func (r StatisticsServiceImpl) GameFinished(ctx context.Context, game model.Game) error {
+	if game.Status != model.Finished || game.Side1User == nil || game.Side2User == nil {
+		return nil
+	}
+	events, err := r.gameEventDao.FindMany(ctx, game.Id.Hex())
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("game_id", game.Id.Hex()).Msg("cannot get game events")
+		return err
+	}
+
+	for _, userId := range []primitive.ObjectID{*game.Side1User, *game.Side2User} {
+		var userEvents []model.GameEvent
+		for _, e := range events {
+			if e.UserId != nil && *e.UserId == userId {
+				userEvents = append(userEvents, e)
+			}
+		}
+		err = r.userStatsDao.AddGame(ctx, model.NewUserGameStats(game, userId, userEvents))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r StatisticsServiceImpl) GetUserStats(ctx context.Context, userId string) (response dto.UserStatsResponse, err error) {
+	response = dto.UserStatsResponse{}
+	stats, err := r.userStatsDao.GetByUser(ctx, userId)
+	if err != nil && !isNotFound(err) {
+		tracing.Log(ctx).Info().Str("userId", userId).Err(err).Msg("cannot get user stats")
+		return response, err
+	}
+
+	response.UserId = utils.EncodeId(userId)
+	response.GamesPlayed = stats.GamesPlayed
+	response.Wins = stats.Wins
+	response.Losses = stats.Losses
+	response.NoResults = stats.NoResults
+	response.WinsByEndReason = stats.WinsByEndReason
+	if response.WinsByEndReason == nil {
+		response.WinsByEndReason = map[string]int{}
+	}
+	response.LossesByEndReason = stats.LossesByEndReason
+	if response.LossesByEndReason == nil {
+		response.LossesByEndReason = map[string]int{}
+	}
+	response.Shots = stats.Hits + stats.Misses
+	response.Hits = stats.Hits
+	response.Reveals = stats.Reveals
+	response.RevealedShips = stats.RevealedShips
+	response.ShipMoves = stats.ShipMoves
+	response.CurrentWinStreak = stats.CurrentWinStreak
+	response.LongestWinStreak = stats.LongestWinStreak
+	response.AverageGameDurationSec = ratio(int(stats.TotalGameDurationSec), stats.GamesPlayed)
+	response.Accuracy = ratio(stats.Hits, response.Shots)
+	response.RevealEfficiency = ratio(stats.RevealedShips, stats.Reveals)
+	response.ShipMovesPerGame = ratio(stats.ShipMoves, stats.GamesPlayed)
+	response.Ok = true
+	return response, nil
+}
+
+func ratio(a int, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
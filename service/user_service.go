@@ -1,51 +1,94 @@
 package service
 
 import (
+	"battleship/config"
 	"battleship/db/dao"
 	"battleship/dto"
+	"battleship/error_codes"
 	"battleship/model"
+	"battleship/tracing"
 	"battleship/utils"
-	"github.com/rs/zerolog/log"
+	"context"
+	"time"
 )
 
 type UserService interface {
-	CreateUser(request dto.CreateUserRequest) (response dto.CreateUserResponse, err error)
-	GetUser(id string) (user dto.UserDto, err error)
+	CreateUser(ctx context.Context, request dto.CreateUserRequest) (response dto.CreateUserResponse, err error)
+	GetUser(ctx context.Context, id string) (user dto.UserDto, err error)
+	GetRatingHistory(ctx context.Context, id string) (response dto.RatingHistoryResponse, err error)
 }
 
 type UserServiceImpl struct {
-	userDao dao.UserDao
+	userDao          dao.UserDao
+	ratingHistoryDao dao.RatingHistoryDao
 }
 
-func NewUserServiceImpl(userDao dao.UserDao) UserServiceImpl {
+func NewUserServiceImpl(userDao dao.UserDao, ratingHistoryDao dao.RatingHistoryDao) UserServiceImpl {
 	return UserServiceImpl{
-		userDao: userDao,
+		userDao:          userDao,
+		ratingHistoryDao: ratingHistoryDao,
 	}
 }
 
-func (r UserServiceImpl) CreateUser(request dto.CreateUserRequest) (response dto.CreateUserResponse, err error) {
-	id, err := r.userDao.Insert(model.User{
+func (r UserServiceImpl) CreateUser(ctx context.Context, request dto.CreateUserRequest) (response dto.CreateUserResponse, err error) {
+	id, err := r.userDao.Insert(ctx, model.User{
 		Name:   request.Name,
 		Mobile: request.Mobile,
 	})
 	if err == nil {
-		response.Id = utils.MaskId(id)
+		response.Id = utils.EncodeId(id)
 		response.Ok = true
 	} else {
-		log.Info().Err(err).Msg("cannot create user")
+		tracing.Log(ctx).Info().Err(err).Msg("cannot create user")
 	}
 	return response, err
 }
 
-func (r UserServiceImpl) GetUser(id string) (user dto.UserDto, err error) {
-	u, err := r.userDao.GetOne(id)
+func (r UserServiceImpl) GetUser(ctx context.Context, id string) (user dto.UserDto, err error) {
+	u, err := r.userDao.GetOne(ctx, id)
 	if err == nil {
 		user.Mobile = u.Mobile
 		user.Name = u.Name
-		user.Id = utils.MaskId(u.Id.Hex())
+		user.Id = utils.EncodeId(u.Id.Hex())
+		user.Rating = u.GetRating(config.C.Rating.Initial)
+		user.RatedGames = u.RatedGames
+		user.Provisional = u.IsProvisional(config.C.Rating.ProvisionalGames)
 		user.Ok = true
 	} else {
-		log.Info().Str("userId", id).Err(err).Msg("cannot get user")
+		tracing.Log(ctx).Info().Str("userId", id).Err(err).Msg("cannot get user")
 	}
 	return user, err
 }
+
+func (r UserServiceImpl) GetRatingHistory(ctx context.Context, id string) (response dto.RatingHistoryResponse, err error) {
+	response = dto.RatingHistoryResponse{History: []dto.RatingHistoryDto{}}
+	histories, err := r.ratingHistoryDao.FindByUser(ctx, id, 100)
+	if err != nil {
+		tracing.Log(ctx).Info().Str("userId", id).Err(err).Msg("cannot get rating history")
+		return response, err
+	}
+	for _, h := range histories {
+		response.History = append(response.History, dto.RatingHistoryDto{
+			GameId:         utils.EncodeId(h.GameId.Hex()),
+			OpponentUserId: utils.EncodeId(h.OpponentUserId.Hex()),
+			OldRating:      h.OldRating,
+			NewRating:      h.NewRating,
+			Won:            h.Won,
+			EndReason:      h.EndReason,
+			Time:           h.Time,
+		})
+	}
+	response.Ok = true
+	return response, nil
+}
+
+// checkNotBanned rejects a banned user, the message has the end of a temporary ban.
+func checkNotBanned(user model.User) error {
+	if !user.IsBanned(time.Now()) {
+		return nil
+	}
+	if user.Ban.Until != nil {
+		return dto.Forbidden2("user is banned until "+user.Ban.Until.Format(time.RFC3339), error_codes.UserBanned)
+	}
+	return dto.Forbidden2("user is banned", error_codes.UserBanned)
+}
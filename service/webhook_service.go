@@ -0,0 +1,262 @@
+package service
+
+import (
+	"battleship/config"
+	"battleship/db/dao"
+	"battleship/dto"
+	"battleship/error_codes"
+	"battleship/model"
+	"battleship/tracing"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	webhookRetryBatch     = 100
+	webhookEventHeader    = "X-Battleship-Event"
+	webhookDeliveryHeader = "X-Battleship-Delivery"
+	webhookTimeHeader     = "X-Battleship-Timestamp"
+	webhookSignHeader     = "X-Battleship-Signature"
+)
+
+// webhookDeliveries tracks the deliveries started by GameEvent, so shutdown waits for them before closing the client.
+var webhookDeliveries sync.WaitGroup
+
+type WebhookService interface {
+	GameEvent(ctx context.Context, event model.WebhookEvent, game model.Game)
+	RetryDue(ctx context.Context)
+	Redeliver(ctx context.Context, request dto.RedeliverWebhookRequest) (response dto.RedeliverWebhookResponse, err error)
+}
+
+type WebhookServiceImpl struct {
+	webhookDao dao.WebhookDao
+}
+
+func NewWebhookServiceImpl(webhookDao dao.WebhookDao) WebhookServiceImpl {
+	return WebhookServiceImpl{
+		webhookDao: webhookDao,
+	}
+}
+
+// GameEvent records a delivery of the event for each subscribed endpoint and sends them in the background, the
+// caller is not blocked by the endpoints. Failed deliveries are retried by RetryDue.
+func (r WebhookServiceImpl) GameEvent(ctx context.Context, event model.WebhookEvent, game model.Game) {
+	endpoints := subscribedEndpoints(event)
+	if len(endpoints) == 0 {
+		return
+	}
+	now := time.Now()
+	payload := dto.WebhookPayload{Event: event, Time: now}
+	payload.Game.FromGame(game)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("game_id", game.Id.Hex()).Msg("cannot marshal webhook payload")
+		return
+	}
+
+	// the request context is done when the request returns, the deliveries only keep its span
+	ctx = trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+	webhookDeliveries.Add(1)
+	go func() {
+		defer webhookDeliveries.Done()
+		for _, endpoint := range endpoints {
+			lease := now.Add(webhookLease())
+			delivery := model.WebhookDelivery{
+				Event:           event,
+				GameId:          game.Id,
+				Endpoint:        endpoint.Name,
+				Url:             endpoint.Url,
+				Payload:         string(body),
+				Status:          model.DeliveryPending,
+				CreateDate:      now,
+				NextAttemptTime: lease,
+				LeaseUntil:      &lease,
+			}
+			id, err := r.webhookDao.Insert(ctx, delivery)
+			if err != nil {
+				continue
+			}
+			delivery.Id, _ = primitive.ObjectIDFromHex(id)
+			r.deliver(ctx, &delivery)
+		}
+	}()
+}
+
+// WaitWebhookDeliveries waits for the deliveries started by GameEvent to end. It returns an error when ctx is done
+// before they end, the rest are retried by RetryDue after restart.
+func WaitWebhookDeliveries(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		webhookDeliveries.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.New("webhook deliveries are still running")
+	}
+}
+
+// RetryDue attempts pending deliveries whose backoff is passed.
+func (r WebhookServiceImpl) RetryDue(ctx context.Context) {
+	now := time.Now()
+	for i := 0; i < webhookRetryBatch; i++ {
+		delivery, found, err := r.webhookDao.ClaimDue(ctx, now, webhookLease())
+		if err != nil || !found {
+			return
+		}
+		r.deliver(ctx, &delivery)
+	}
+}
+
+// Redeliver sends a delivery again whatever its status is, it gets a new set of attempts. A delivery that is being
+// attempted, e.g. by RetryDue, is not sent twice and a conflict is returned.
+func (r WebhookServiceImpl) Redeliver(ctx context.Context, request dto.RedeliverWebhookRequest) (response dto.RedeliverWebhookResponse, err error) {
+	response = dto.RedeliverWebhookResponse{}
+	delivery, err := r.webhookDao.GetOne(ctx, request.DeliveryId)
+	if err != nil {
+		return response, err
+	}
+	delivery, claimed, err := r.webhookDao.ClaimForRedelivery(ctx, delivery.Id, time.Now(), webhookLease())
+	if err != nil {
+		return response, err
+	}
+	if !claimed {
+		tracing.Log(ctx).Info().Str("delivery_id", request.DeliveryId).Msg("webhook delivery is being attempted")
+		return response, dto.Duplicate2("webhook delivery is being attempted, try again later", error_codes.WebhookDeliveryBusy)
+	}
+	r.deliver(ctx, &delivery)
+
+	response.Ok = true
+	response.Delivery.FromDelivery(delivery)
+	return response, nil
+}
+
+// deliver makes one attempt and saves its result. A failed attempt is scheduled again with exponential backoff
+// until the max attempts is reached.
+func (r WebhookServiceImpl) deliver(ctx context.Context, delivery *model.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptTime = &now
+	delivery.ResponseCode = 0
+	delivery.LastError = ""
+
+	endpoint, ok := findEndpoint(delivery.Endpoint)
+	if !ok {
+		delivery.Status = model.DeliveryFailed
+		delivery.LastError = "endpoint is not configured"
+	} else if code, err := r.post(ctx, endpoint, *delivery, now); err != nil {
+		delivery.ResponseCode = code
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= config.C.Webhook.MaxAttempts {
+			delivery.Status = model.DeliveryFailed
+		} else {
+			delivery.NextAttemptTime = now.Add(webhookBackoff(delivery.Attempts))
+		}
+	} else {
+		delivery.ResponseCode = code
+		delivery.Status = model.DeliveryDelivered
+	}
+
+	if delivery.Status == model.DeliveryFailed {
+		tracing.Log(ctx).Warn().Str("delivery_id", delivery.Id.Hex()).Str("endpoint", delivery.Endpoint).
+			Str("error", delivery.LastError).Msg("webhook delivery failed")
+	}
+	delivery.LeaseUntil = nil
+	if err := r.webhookDao.Update(ctx, *delivery); err != nil {
+		tracing.Log(ctx).Error().Err(err).Str("delivery_id", delivery.Id.Hex()).Msg("cannot save webhook delivery")
+	}
+}
+
+func (r WebhookServiceImpl) post(ctx context.Context, endpoint config.WebhookEndpoint, delivery model.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, string(delivery.Event))
+	req.Header.Set(webhookDeliveryHeader, delivery.Id.Hex())
+	req.Header.Set(webhookTimeHeader, timestamp)
+	req.Header.Set(webhookSignHeader, "sha256="+signWebhook(endpoint.Secret, timestamp, delivery.Payload))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	client := &http.Client{Timeout: time.Duration(config.C.Webhook.TimeoutSec) * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res.StatusCode, fmt.Errorf("endpoint responded with status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// signWebhook returns the HMAC-SHA256 of "timestamp.payload", receivers can check the timestamp to reject replays.
+func signWebhook(secret string, timestamp string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookBackoff(attempts int) time.Duration {
+	backoff := time.Duration(config.C.Webhook.InitialBackoffSec) * time.Second
+	max := time.Duration(config.C.Webhook.MaxBackoffSec) * time.Second
+	for i := 1; i < attempts && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	return backoff
+}
+
+// webhookLease is the time a delivery in progress is hidden from RetryDue.
+func webhookLease() time.Duration {
+	return 2 * time.Duration(config.C.Webhook.TimeoutSec+1) * time.Second
+}
+
+func subscribedEndpoints(event model.WebhookEvent) []config.WebhookEndpoint {
+	var endpoints []config.WebhookEndpoint
+	for _, endpoint := range config.C.Webhook.Endpoints {
+		if len(endpoint.Events) == 0 {
+			endpoints = append(endpoints, endpoint)
+			continue
+		}
+		for _, e := range endpoint.Events {
+			if e == string(event) {
+				endpoints = append(endpoints, endpoint)
+				break
+			}
+		}
+	}
+	return endpoints
+}
+
+func findEndpoint(name string) (config.WebhookEndpoint, bool) {
+	for _, endpoint := range config.C.Webhook.Endpoints {
+		if endpoint.Name == name {
+			return endpoint, true
+		}
+	}
+	return config.WebhookEndpoint{}, false
+}
@@ -0,0 +1,83 @@
+package sms
+
+import (
+	"battleship/config"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	ConsoleSender = "console"
+	FileSender    = "file"
+)
+
+type SmsSender interface {
+	Send(mobile string, message string) error
+}
+
+// NewSmsSender returns the sender selected by sms.sender config. Real gateways can be added here as new implementations.
+func NewSmsSender() SmsSender {
+	switch config.C.Sms.Sender {
+	case FileSender:
+		return NewFileSmsSender(config.C.Sms.FilePath)
+	case ConsoleSender, "":
+		return NewConsoleSmsSender()
+	default:
+		log.Warn().Str("sender", config.C.Sms.Sender).Msg("unknown sms sender, console sender is used")
+		return NewConsoleSmsSender()
+	}
+}
+
+//////////////
+
+type ConsoleSmsSender struct {
+}
+
+func NewConsoleSmsSender() ConsoleSmsSender {
+	return ConsoleSmsSender{}
+}
+
+func (r ConsoleSmsSender) Send(mobile string, message string) error {
+	log.Info().Str("mobile", mobile).Str("message", message).Msg("sms")
+	return nil
+}
+
+//////////////
+
+var fileMux sync.Mutex
+
+type FileSmsSender struct {
+	path string
+}
+
+func NewFileSmsSender(path string) FileSmsSender {
+	return FileSmsSender{
+		path: path,
+	}
+}
+
+func (r FileSmsSender) Send(mobile string, message string) error {
+	fileMux.Lock()
+	defer fileMux.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		log.Error().Err(err).Str("path", r.path).Msg("cannot create sms file directory")
+		return err
+	}
+	file, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Error().Err(err).Str("path", r.path).Msg("cannot open sms file")
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\n", time.Now().Format(time.RFC3339), mobile, message)
+	if err != nil {
+		log.Error().Err(err).Str("path", r.path).Msg("cannot write sms file")
+	}
+	return err
+}
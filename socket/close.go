@@ -0,0 +1,24 @@
+package socket
+
+import (
+	"battleship/cache"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+const closeWriteWait = time.Second
+
+// CloseAll sends a close frame to all sockets and closes them, their read loops end and clean up the caches.
+func CloseAll(reason string) {
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, reason)
+	conns := cache.AllSockets()
+	for _, conn := range conns {
+		err := conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(closeWriteWait))
+		if err != nil {
+			log.Debug().Err(err).Msg("cannot send close message")
+		}
+		_ = conn.Close()
+	}
+	log.Info().Int("sockets", len(conns)).Msg("sockets are closed")
+}
@@ -1,21 +1,29 @@
 package socket
 
 import (
+	"battleship/cache"
 	"battleship/config"
 	"battleship/dto"
 	"battleship/events/incoming_events"
+	"battleship/metrics"
 	"battleship/service"
+	"battleship/tracing"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
 	"net/http"
+	"time"
 )
 
 //goland:noinspection GoNameStartsWithPackageName
 type SocketHandler interface {
 	CreateSocket(c echo.Context) error
+	CreateUserSocket(c echo.Context) error
+	CreateLobbySocket(c echo.Context) error
 }
 
 var upgrader = websocket.Upgrader{
@@ -64,6 +72,9 @@ func (r SocketHandlerImpl) connect(c echo.Context) error {
 		log.Error().Msg("error in upgrading:" + err.Error())
 		return err
 	}
+	metrics.SocketOpened(metrics.GameSocket)
+	defer metrics.SocketClosed(metrics.GameSocket)
+	defer cache.ForgetSocket(socketConn)
 
 	request := new(dto.UserConnectEvent)
 	request.GameId = gameId
@@ -78,11 +89,15 @@ func (r SocketHandlerImpl) connect(c echo.Context) error {
 		Type:    dto.Connect,
 		Payload: string(marshal),
 	}
-	err = r.gameService.SocketConnect(event, socketConn)
+	err = r.socketConnect(c.Request().Context(), event, socketConn)
 	if err != nil {
 		log.Error().Err(err).Msg("error in NewConnectionHandler")
 		return err
 	}
+	err = request.ValidateAndUnmask()
+	if err != nil {
+		return err
+	}
 
 	for {
 		_, message, err := socketConn.ReadMessage()
@@ -90,17 +105,87 @@ func (r SocketHandlerImpl) connect(c echo.Context) error {
 			log.Warn().Msg("error in reading message:" + err.Error())
 			break
 		}
-		log.Debug().Msg("recv: " + string(message))
-		event := new(dto.Event)
-		err = json.Unmarshal(message, event)
+		err = r.handleMessage(c.Request().Context(), request, message)
 		if err != nil {
-			log.Error().Str("message", string(message)).Msg("message format is not Event")
-			//_ = c.Close()
+			_ = socketConn.Close()
 		}
+	}
+	// the user may have connected with a new socket since, then it is still connected
+	if cache.RemoveGameSocket(request.GameId, request.UserId, socketConn) {
+		cache.MarkDisconnected(request.GameId, request.UserId, time.Now())
+	}
+	return nil
+}
 
-		err = r.incomingEventHandler.HandleEvent(*event)
-		if err != nil {
-			_ = socketConn.Close()
+func (r SocketHandlerImpl) socketConnect(ctx context.Context, event dto.Event, socketConn *websocket.Conn) (err error) {
+	ctx, span := tracing.Start(ctx, "socket.connect")
+	defer tracing.End(span, &err)
+	return r.gameService.SocketConnect(ctx, event, socketConn)
+}
+
+// handleMessage handles a message of a game socket in its own trace, as the socket lives longer than a request.
+func (r SocketHandlerImpl) handleMessage(ctx context.Context, request *dto.UserConnectEvent, message []byte) (err error) {
+	ctx, span := tracing.Start(ctx, "socket.message",
+		attribute.String("game_id", request.GameId), attribute.String("user_id", request.UserId))
+	defer tracing.End(span, &err)
+
+	tracing.Log(ctx).Debug().Msg("recv: " + string(message))
+	event := new(dto.Event)
+	err = json.Unmarshal(message, event)
+	if err != nil {
+		tracing.Log(ctx).Error().Str("message", string(message)).Msg("message format is not Event")
+		return nil
+	}
+	span.SetAttributes(attribute.String("event_type", string(event.Type)))
+	return r.incomingEventHandler.HandleEvent(ctx, request.GameId, request.UserId, *event)
+}
+
+// CreateUserSocket opens a socket that is bound to the user only, it receives events that happen before a game
+// exists for the user, like matchmaking results.
+func (r SocketHandlerImpl) CreateUserSocket(c echo.Context) error {
+	userId, err := dto.UnmaskId(c.QueryParam("user_id"))
+	if err != nil {
+		log.Error().Str("user_id", c.QueryParam("user_id")).Msg("user_id is not correct in create user socket")
+		return err
+	}
+	socketConn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		log.Error().Msg("error in upgrading:" + err.Error())
+		return err
+	}
+	metrics.SocketOpened(metrics.UserSocket)
+	defer metrics.SocketClosed(metrics.UserSocket)
+	defer cache.ForgetSocket(socketConn)
+	cache.SetUserSocket(userId, socketConn)
+	defer cache.RemoveUserSocket(userId, socketConn)
+	log.Debug().Str("user_id", userId).Msg("create user socket successfully")
+
+	for {
+		if _, _, err := socketConn.ReadMessage(); err != nil {
+			log.Debug().Str("user_id", userId).Msg("user socket is closed: " + err.Error())
+			break
+		}
+	}
+	return nil
+}
+
+// CreateLobbySocket opens a socket that receives lobby updates, it does not need a user.
+func (r SocketHandlerImpl) CreateLobbySocket(c echo.Context) error {
+	socketConn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		log.Error().Msg("error in upgrading:" + err.Error())
+		return err
+	}
+	metrics.SocketOpened(metrics.LobbySocket)
+	defer metrics.SocketClosed(metrics.LobbySocket)
+	defer cache.ForgetSocket(socketConn)
+	cache.AddLobbySocket(socketConn)
+	defer cache.RemoveLobbySocket(socketConn)
+
+	for {
+		if _, _, err := socketConn.ReadMessage(); err != nil {
+			log.Debug().Msg("lobby socket is closed: " + err.Error())
+			break
 		}
 	}
 	return nil
@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"battleship/config"
+	"context"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"os"
+)
+
+const (
+	NoExporter     = "none"
+	StdoutExporter = "stdout"
+	FileExporter   = "file"
+
+	tracerName = "battleship"
+)
+
+// Init sets the global tracer provider selected by tracing.exporter config. The returned function flushes and stops
+// the exporter, it is called on shutdown. Spans are not recorded when no exporter is configured.
+func Init() func(ctx context.Context) error {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var writer io.Writer
+	switch config.C.Tracing.Exporter {
+	case StdoutExporter:
+		writer = os.Stdout
+	case FileExporter:
+		file, err := os.OpenFile(config.C.Tracing.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			log.Error().Err(err).Str("path", config.C.Tracing.FilePath).Msg("cannot open trace file, tracing is disabled")
+			return noShutdown
+		}
+		writer = file
+	case NoExporter, "":
+		return noShutdown
+	default:
+		log.Warn().Str("exporter", config.C.Tracing.Exporter).Msg("unknown trace exporter, tracing is disabled")
+		return noShutdown
+	}
+
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(writer))
+	if err != nil {
+		log.Error().Err(err).Msg("cannot create trace exporter, tracing is disabled")
+		return noShutdown
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.C.Tracing.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(config.C.Tracing.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	log.Info().Str("exporter", config.C.Tracing.Exporter).Msg("tracing is enabled")
+	return provider.Shutdown
+}
+
+func noShutdown(context.Context) error {
+	return nil
+}
+
+// Start starts a span that is a child of the span in ctx, if there is one.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span and ends it, it is deferred with a pointer to a named error result.
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
+
+// Log returns the logger with trace_id and span_id of the span in ctx, or the global logger when there is no span.
+func Log(ctx context.Context) *zerolog.Logger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return &log.Logger
+	}
+	logger := log.Logger.With().
+		Str("trace_id", spanContext.TraceID().String()).
+		Str("span_id", spanContext.SpanID().String()).
+		Logger()
+	return &logger
+}
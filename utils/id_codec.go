@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"sync"
+)
+
+// Public ids are base64url(version | AES(objectId | padding) | HMAC(version | block)[:8]).
+// version selects the key, so old ids keep working after a new key becomes current.
+
+const (
+	idBlockSize = aes.BlockSize
+	idTagSize   = 8
+	idSize      = 1 + idBlockSize + idTagSize
+)
+
+var (
+	ErrMalformedId = errors.New("malformed id")
+	idPadding      = make([]byte, idBlockSize-12)
+	idCodec        = struct {
+		mux     sync.RWMutex
+		current byte
+		keys    map[byte]idKey
+	}{
+		keys: map[byte]idKey{},
+	}
+)
+
+type idKey struct {
+	block  cipher.Block
+	macKey []byte
+}
+
+// InitIdCodec registers the id keys. current is the version used for encoding, every registered key is accepted
+// for decoding.
+func InitIdCodec(current int, secrets map[int]string) error {
+	if len(secrets) == 0 {
+		return errors.New("no id key is configured")
+	}
+	keys := make(map[byte]idKey, len(secrets))
+	for version, secret := range secrets {
+		if version <= 0 || version > 255 {
+			return fmt.Errorf("id key version %d must be between 1 and 255", version)
+		}
+		if len(secret) < 16 {
+			return fmt.Errorf("id key version %d is shorter than 16 characters", version)
+		}
+		encKey := sha256.Sum256([]byte("id-enc:" + secret))
+		macKey := sha256.Sum256([]byte("id-mac:" + secret))
+		block, err := aes.NewCipher(encKey[:])
+		if err != nil {
+			return err
+		}
+		keys[byte(version)] = idKey{block: block, macKey: macKey[:]}
+	}
+	if _, ok := keys[byte(current)]; !ok || current <= 0 || current > 255 {
+		return fmt.Errorf("current id key version %d is not configured", current)
+	}
+
+	idCodec.mux.Lock()
+	defer idCodec.mux.Unlock()
+	idCodec.current = byte(current)
+	idCodec.keys = keys
+	return nil
+}
+
+// EncodeId converts a hex ObjectID to its public form. Empty string is returned for invalid input. It panics when
+// InitIdCodec has not succeeded, the server does not start without valid id keys.
+func EncodeId(hexId string) string {
+	objectId, err := primitive.ObjectIDFromHex(hexId)
+	if err != nil {
+		return ""
+	}
+
+	idCodec.mux.RLock()
+	version := idCodec.current
+	key, ok := idCodec.keys[version]
+	idCodec.mux.RUnlock()
+	if !ok {
+		panic("id codec is not initialized")
+	}
+
+	out := make([]byte, idSize)
+	out[0] = version
+	plain := append(objectId[:], idPadding...)
+	key.block.Encrypt(out[1:1+idBlockSize], plain)
+	copy(out[1+idBlockSize:], idTag(key.macKey, out[:1+idBlockSize]))
+	return base64.RawURLEncoding.EncodeToString(out)
+}
+
+// DecodeId converts a public id back to hex ObjectID. ErrMalformedId is returned for anything not produced by
+// EncodeId with a configured key.
+func DecodeId(publicId string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(publicId)
+	if err != nil || len(raw) != idSize {
+		return "", ErrMalformedId
+	}
+
+	idCodec.mux.RLock()
+	key, ok := idCodec.keys[raw[0]]
+	idCodec.mux.RUnlock()
+	if !ok {
+		return "", ErrMalformedId
+	}
+
+	if !hmac.Equal(raw[1+idBlockSize:], idTag(key.macKey, raw[:1+idBlockSize])) {
+		return "", ErrMalformedId
+	}
+
+	plain := make([]byte, idBlockSize)
+	key.block.Decrypt(plain, raw[1:1+idBlockSize])
+	if !bytes.Equal(plain[12:], idPadding) {
+		return "", ErrMalformedId
+	}
+	var objectId primitive.ObjectID
+	copy(objectId[:], plain[:12])
+	return objectId.Hex(), nil
+}
+
+func idTag(macKey []byte, data []byte) []byte {
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(data)
+	return mac.Sum(nil)[:idTagSize]
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"encoding/base64"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+)
+
+const (
+	testSecret1 = "first-test-secret-of-ids"
+	testSecret2 = "second-test-secret-of-ids"
+)
+
+func initTestCodec(t *testing.T, current int, secrets map[int]string) {
+	t.Helper()
+	if err := InitIdCodec(current, secrets); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIdCodecRoundTrip(t *testing.T) {
+	initTestCodec(t, 1, map[int]string{1: testSecret1})
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		hexId := primitive.NewObjectID().Hex()
+		publicId := EncodeId(hexId)
+		if publicId == "" || publicId == hexId {
+			t.Fatalf("EncodeId(%s) = %q", hexId, publicId)
+		}
+		if seen[publicId] {
+			t.Fatalf("EncodeId(%s) = %q is not unique", hexId, publicId)
+		}
+		seen[publicId] = true
+		decoded, err := DecodeId(publicId)
+		if err != nil || decoded != hexId {
+			t.Fatalf("DecodeId(EncodeId(%s)) = %s, %v", hexId, decoded, err)
+		}
+	}
+}
+
+func TestDecodeIdRejectsTampering(t *testing.T) {
+	initTestCodec(t, 1, map[int]string{1: testSecret1})
+	hexId := primitive.NewObjectID().Hex()
+	raw, err := base64.RawURLEncoding.DecodeString(EncodeId(hexId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range raw {
+		for _, mask := range []byte{0x01, 0x80, 0xff} {
+			tampered := append([]byte(nil), raw...)
+			tampered[i] ^= mask
+			if decoded, err := DecodeId(base64.RawURLEncoding.EncodeToString(tampered)); err != ErrMalformedId {
+				t.Errorf("byte %d ^ %#x: DecodeId() = %s, %v, want ErrMalformedId", i, mask, decoded, err)
+			}
+		}
+	}
+}
+
+func TestDecodeIdRejectsMalformed(t *testing.T) {
+	initTestCodec(t, 1, map[int]string{1: testSecret1})
+	publicId := EncodeId(primitive.NewObjectID().Hex())
+	tests := []struct {
+		name     string
+		publicId string
+	}{
+		{"empty", ""},
+		{"hex object id", primitive.NewObjectID().Hex()},
+		{"not base64", "!!!!"},
+		{"truncated", publicId[:len(publicId)-1]},
+		{"extended", publicId + "A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if decoded, err := DecodeId(tt.publicId); err != ErrMalformedId {
+				t.Errorf("DecodeId() = %s, %v, want ErrMalformedId", decoded, err)
+			}
+		})
+	}
+}
+
+func TestIdCodecKeyRotation(t *testing.T) {
+	hexId := primitive.NewObjectID().Hex()
+	initTestCodec(t, 1, map[int]string{1: testSecret1})
+	oldId := EncodeId(hexId)
+
+	initTestCodec(t, 2, map[int]string{1: testSecret1, 2: testSecret2})
+	newId := EncodeId(hexId)
+	if newId == oldId {
+		t.Fatal("id is not encoded with the current key")
+	}
+	for _, publicId := range []string{oldId, newId} {
+		if decoded, err := DecodeId(publicId); err != nil || decoded != hexId {
+			t.Errorf("DecodeId(%s) = %s, %v", publicId, decoded, err)
+		}
+	}
+
+	initTestCodec(t, 2, map[int]string{2: testSecret2})
+	if _, err := DecodeId(oldId); err != ErrMalformedId {
+		t.Errorf("id of a removed key is decoded, err %v", err)
+	}
+
+	initTestCodec(t, 2, map[int]string{2: testSecret1})
+	if _, err := DecodeId(newId); err != ErrMalformedId {
+		t.Errorf("id is decoded with another secret, err %v", err)
+	}
+}
+
+func TestInitIdCodecRejectsInvalidKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		current int
+		secrets map[int]string
+	}{
+		{"no keys", 1, nil},
+		{"short secret", 1, map[int]string{1: "short"}},
+		{"zero version", 0, map[int]string{0: testSecret1}},
+		{"version above 255", 256, map[int]string{256: testSecret1}},
+		{"current is not configured", 2, map[int]string{1: testSecret1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := InitIdCodec(tt.current, tt.secrets); err == nil {
+				t.Error("InitIdCodec() accepts invalid keys")
+			}
+		})
+	}
+}
+
+func TestEncodeId(t *testing.T) {
+	initTestCodec(t, 1, map[int]string{1: testSecret1})
+	if publicId := EncodeId("not an object id"); publicId != "" {
+		t.Errorf("EncodeId() of invalid hex = %q", publicId)
+	}
+
+	idCodec.mux.Lock()
+	keys := idCodec.keys
+	idCodec.keys = map[byte]idKey{}
+	idCodec.mux.Unlock()
+	defer func() {
+		idCodec.mux.Lock()
+		idCodec.keys = keys
+		idCodec.mux.Unlock()
+		if recover() == nil {
+			t.Error("EncodeId() does not panic without keys")
+		}
+	}()
+	EncodeId(primitive.NewObjectID().Hex())
+}